@@ -4,7 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/counting"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +23,25 @@ var stickKeywords = []string{
 	"stay",
 	"s",
 }
+var doubleKeywords = []string{
+	"double",
+	"double down",
+	"d",
+}
+var surrenderKeywords = []string{
+	"surrender",
+	"give up",
+	"r",
+}
+var hintKeywords = []string{
+	"hint",
+	"help",
+	"?",
+}
+var countKeywords = []string{
+	"count",
+	"c",
+}
 
 // contains is a helper function: searches sl for any instance of target, returning a boolean truthfulness value.
 // Capitalisation normalised.
@@ -34,12 +56,40 @@ func contains(sl []string, target string) bool {
 	return false
 }
 
-// PlayBlackjackSP plays a single round of Blackjack on stdout, with the player simply playing for themselves (single player).
-// A win condition is simply getting 21 or less; the only way the player loses is if they go bust.
-func PlayBlackjackSP(table *blackjack.Table, player *blackjack.Player) (err error) {
-	if table == nil || player == nil {
+// PlayBlackjackSP plays a single round of Blackjack on stdout, with the player playing against the dealer (single player).
+// The player wins by beating the dealer's hand without going bust. Hints come from the given basic strategy chart.
+// If there's a counter (it may be nil), the count can be checked during play, and is shown before each round.
+func PlayBlackjackSP(table *blackjack.Table, player *blackjack.Player, chart *strategy.Chart, counter *counting.Counter) (err error) {
+	if table == nil || player == nil || chart == nil {
 		return ErrNilReference
 	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	if counter != nil {
+		fmt.Printf("Count - %s\n", counter)
+	}
+
+	// Take a bet, if the player has any money to bet with
+	if player.Bankroll() > 0 {
+		fmt.Printf("You have %v to bet with. Your bet: ", player.Bankroll())
+		for {
+			input, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			input = strings.TrimSpace(input)
+			bet, err := strconv.Atoi(input)
+			if err == nil {
+				err = player.PlaceBet(bet)
+			}
+			if err == nil {
+				break
+			}
+			fmt.Printf("Invalid bet (%s)! Bet between 0 and %v: ", err, player.Bankroll())
+		}
+	}
+
 	fmt.Print("Dealing new table...")
 	// Calling Deal() resets the table automatically, which for our use case is absolutely fine
 	errs := table.Deal()
@@ -47,8 +97,11 @@ func PlayBlackjackSP(table *blackjack.Table, player *blackjack.Player) (err erro
 		return fmt.Errorf("errors returned when dealing new table: %v", errs)
 	}
 	fmt.Print("...Table ready to play!\n")
-
-	reader := bufio.NewReader(os.Stdin)
+	upCard, err := table.DealerUpCard()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The dealer shows the %s.\n", upCard.String())
 
 	// Gameplay loop - breaks out when the hand is completed.
 	// SWEng: It may make more sense for this to loop over each hand. Discussion point?
@@ -84,7 +137,11 @@ func PlayBlackjackSP(table *blackjack.Table, player *blackjack.Player) (err erro
 
 		// Accept user input
 		var endHand bool
-		fmt.Print("Action ([h]it, [s]tick): ")
+		if counter != nil {
+			fmt.Print("Action ([h]it, [s]tick, [d]ouble down, su[r]render, ? for a hint, or [c]ount): ")
+		} else {
+			fmt.Print("Action ([h]it, [s]tick, [d]ouble down, su[r]render, or ? for a hint): ")
+		}
 		for {
 			input, err := reader.ReadString('\n')
 			if err != nil {
@@ -106,9 +163,36 @@ func PlayBlackjackSP(table *blackjack.Table, player *blackjack.Player) (err erro
 				}
 				endHand = true
 				break
+			} else if contains(doubleKeywords, input) {
+				err := player.Hands[0].DoubleDown()
+				if err == nil {
+					// Loop back around to show the final card
+					break
+				}
+				fmt.Printf("Can't double down (%s)! Choose one of [h]it, [s]tick: ", err)
+				continue
+			} else if contains(surrenderKeywords, input) {
+				err := player.Hands[0].Surrender()
+				if err == nil {
+					fmt.Print("Surrendering this hand.\n")
+					endHand = true
+					break
+				}
+				fmt.Printf("Can't surrender (%s)! Choose one of [h]it, [s]tick: ", err)
+				continue
+			} else if contains(hintKeywords, input) {
+				action, err := chart.Hint(player.Hands[0])
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Basic strategy says: %s. Your action: ", action)
+				continue
+			} else if counter != nil && contains(countKeywords, input) {
+				fmt.Printf("Count - %s. Your action: ", counter)
+				continue
 			}
 			// We didn't get a valid input, be sad with the user and loop again
-			fmt.Print("Invalid action! Choose one of [h]it, [s]tick:")
+			fmt.Print("Invalid action! Choose one of [h]it, [s]tick, [d]ouble down, su[r]render: ")
 		}
 		if endHand {
 			break
@@ -119,11 +203,45 @@ func PlayBlackjackSP(table *blackjack.Table, player *blackjack.Player) (err erro
 	if err != nil {
 		return err
 	}
-	score, _, _, valid := player.Hands[0].Score()
-	if valid {
-		fmt.Printf("Congratulations on a score of %v!\n", score)
+
+	// Show what the dealer had
+	dealer, err := table.DealerHand()
+	if err != nil {
+		return err
+	}
+	fmt.Print("Dealer's hand:\n")
+	for _, v := range dealer.Cards {
+		fmt.Printf(" - %s\n", v.String())
+	}
+	fmt.Print("----------\n")
+	dealerScore, _, _, dealerValid := dealer.Score()
+	if dealerValid {
+		fmt.Printf("Dealer has %v\n", dealerScore)
 	} else {
-		fmt.Printf("Commiserations on a score of %v!\n", score)
+		fmt.Printf("Dealer is bust with %v\n", dealerScore)
+	}
+
+	results, err := table.Settle()
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		score, _, _, _ := r.Hand.Score()
+		switch r.Outcome {
+		case blackjack.OutcomeBlackjack:
+			fmt.Printf("Blackjack! Congratulations, you win!\n")
+		case blackjack.OutcomeWin:
+			fmt.Printf("Congratulations, you win with a score of %v!\n", score)
+		case blackjack.OutcomePush:
+			fmt.Printf("Push - you tied with the dealer on %v.\n", score)
+		case blackjack.OutcomeSurrender:
+			fmt.Printf("You surrendered with a score of %v.\n", score)
+		default:
+			fmt.Printf("Commiserations, you lose with a score of %v!\n", score)
+		}
+		if r.Wager > 0 {
+			fmt.Printf("You staked %v and got back %v. Your bankroll is now %v.\n", r.Wager, r.Payout, player.Bankroll())
+		}
 	}
 
 	return
@@ -5,24 +5,123 @@ import (
 	"flag"
 	"fmt"
 	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/counting"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/server"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"net"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func Initialise(deckCount int) (table *blackjack.Table, player *blackjack.Player, err error) {
+func Initialise(deckCount int, bankroll int, seed int64) (table *blackjack.Table, player *blackjack.Player, err error) {
 	table = blackjack.NewTable(deckCount)
 	if err != nil {
 		return nil, nil, err
 	}
+	// A seed of 0 means a different game every time.
+	if seed != 0 {
+		err = table.SetSource(playdeck.NewSeededSource(seed))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	// Surrender's no fun if you can't use it.
+	err = table.SetSurrenderRule(blackjack.SurrenderLate)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Only a single hand is played here, so there's no splitting (and no hints telling you to).
+	err = table.SetSplitRules(blackjack.SplitRules{MaxHands: 0})
+	if err != nil {
+		return nil, nil, err
+	}
 	player = blackjack.NewPlayer()
+	if bankroll > 0 {
+		err = player.Deposit(bankroll)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 	err = table.Join(player)
 	// Shorthand on the return, saves a needless if err check
 	return table, player, err
 }
 
+// WatchCount starts counting the cards dealt at the table by the named counting system, estimating the decks left to the
+// nearest half deck like a player would. The shoe is kept between rounds (until three quarters of it have been dealt),
+// since there's nothing to count if it's replaced every round.
+func WatchCount(table *blackjack.Table, system string) (counter *counting.Counter, err error) {
+	s, err := counting.ParseSystem(system)
+	if err != nil {
+		return nil, err
+	}
+	rules := table.Rules()
+	rules.Penetration = 0.75
+	err = table.SetRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	counter = counting.NewCounter(s, rules.Decks)
+	counter.SetPrecision(0.5)
+	counter.WatchTable(table)
+	return counter, nil
+}
+
+// Serve hosts the given number of tables (named "1", "2" and so on) for players to join over TCP, until the listener fails.
+// Each new player starts with the bankroll, and has the timeout to make each decision (0 means they can take forever).
+func Serve(addr string, tables int, decks int, bankroll int, seed int64, timeout time.Duration) (err error) {
+	srv := server.NewServer()
+	srv.Bankroll = bankroll
+	for i := 1; i <= tables; i++ {
+		rules := blackjack.DefaultRules
+		rules.Decks = decks
+		rules.DecisionTimeout = timeout
+		table, err := blackjack.NewTableWithRules(rules)
+		if err != nil {
+			return err
+		}
+		// Every table gets its own seed, or they'd all deal the same cards.
+		if seed != 0 {
+			err = table.SetSource(playdeck.NewSeededSource(seed + int64(i)))
+			if err != nil {
+				return err
+			}
+		}
+		err = srv.AddTable(strconv.Itoa(i), table)
+		if err != nil {
+			return err
+		}
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Serving %v table(s) on %s\n", tables, l.Addr())
+	return srv.Serve(l)
+}
+
 func main() {
 	var decks int
+	var bankroll int
+	var seed int64
+	var chartPath string
+	var countSystem string
 	flag.IntVar(&decks, "decks", 1, "Number of decks to draw from.")
+	flag.IntVar(&bankroll, "bankroll", 100, "Amount of money to start with. Set to 0 to play without betting.")
+
+	flag.Int64Var(&seed, "seed", 0, "Seed for shuffling, to replay the same game. Set to 0 for a random game.")
+	flag.StringVar(&chartPath, "chart", "", "Basic strategy chart (CSV) to give hints from. Defaults to the built-in chart.")
+	flag.StringVar(&countSystem, "count", "", "Card counting system to keep count with for practice (e.g. hilo, ko, zen). Off by default.")
+
+	var serveAddr string
+	var tables int
+	var timeout time.Duration
+	flag.StringVar(&serveAddr, "serve", "", "Address (e.g. :4000) to host tables on for players to join over TCP, instead of playing locally.")
+	flag.IntVar(&tables, "tables", 1, "Number of tables to host, when serving.")
+	flag.DurationVar(&timeout, "timeout", 0, "Time each player has to make a decision when serving. Set to 0 for no limit.")
 
 	flag.Parse()
 
@@ -31,14 +130,34 @@ func main() {
 		os.Exit(2)
 	}
 
-	deck, player, err := Initialise(decks)
+	if serveAddr != "" {
+		err := Serve(serveAddr, tables, decks, bankroll, seed, timeout)
+		fmt.Printf("error: %s", err)
+		os.Exit(1)
+	}
+
+	deck, player, err := Initialise(decks, bankroll, seed)
+	if err != nil {
+		fmt.Printf("error: %s", err)
+		os.Exit(1)
+	}
+	chart, err := strategy.LoadChart(chartPath)
 	if err != nil {
 		fmt.Printf("error: %s", err)
 		os.Exit(1)
 	}
 
+	var counter *counting.Counter
+	if countSystem != "" {
+		counter, err = WatchCount(deck, countSystem)
+		if err != nil {
+			fmt.Printf("error: %s", err)
+			os.Exit(1)
+		}
+	}
+
 	for {
-		err = PlayBlackjackSP(deck, player)
+		err = PlayBlackjackSP(deck, player, chart, counter)
 		if err != nil {
 			fmt.Printf("execution error! %s", err)
 			os.Exit(1)
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/simulator"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// ruleSets are the rules that can be picked with -rules.
+var ruleSets = map[string]blackjack.RuleSet{
+	"default":  blackjack.DefaultRules,
+	"vegas":    blackjack.VegasStripRules,
+	"atlantic": blackjack.AtlanticCityRules,
+	"european": blackjack.EuropeanNoHoleCardRules,
+}
+
+// Rules returns the named rule set, with the number of decks, penetration and soft 17 rule changed if asked.
+func Rules(name string, decks int, penetration float64, h17 bool) (rules blackjack.RuleSet, err error) {
+	rules, exists := ruleSets[name]
+	if !exists {
+		return rules, fmt.Errorf("%w: unknown rule set %q", blackjack.ErrInvalidRule, name)
+	}
+	if decks > 0 {
+		rules.Decks = decks
+	}
+	if penetration > 0 {
+		rules.Penetration = penetration
+	}
+	if h17 {
+		rules.DealerRule = blackjack.DealerHitsSoft17
+	}
+	return rules, rules.Validate()
+}
+
+func main() {
+	var config simulator.Config
+	var ruleName, chartPath string
+	var decks, bet int
+	var penetration float64
+	var h17, asJSON bool
+	flag.IntVar(&config.Rounds, "rounds", 1000000, "Number of rounds to play.")
+	flag.Int64Var(&config.Seed, "seed", 1, "Seed for shuffling. The same seed and settings always give the same results.")
+	flag.IntVar(&config.Workers, "workers", 0, "Number of tables to play at once. Set to 0 for one per CPU.")
+	flag.IntVar(&config.Players, "players", 1, "Number of players at each table.")
+	flag.StringVar(&ruleName, "rules", "vegas", "Rules to play by: default, vegas, atlantic or european.")
+	flag.IntVar(&decks, "decks", 0, "Number of decks in the shoe. Set to 0 to use the rules' own.")
+	flag.Float64Var(&penetration, "penetration", 0, "How far through the shoe the cut card sits. Set to 0 to use the rules' own.")
+	flag.BoolVar(&h17, "h17", false, "Have the dealer hit soft 17.")
+	flag.StringVar(&chartPath, "chart", "", "Basic strategy chart (CSV) to play by. Defaults to the built-in chart.")
+	flag.IntVar(&bet, "bet", int(simulator.DefaultBet), "Flat bet to place every round.")
+	flag.BoolVar(&asJSON, "json", false, "Print the report as JSON.")
+
+	flag.Parse()
+
+	var err error
+	config.Rules, err = Rules(ruleName, decks, penetration, h17)
+	if err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(2)
+	}
+	config.Strategy, err = strategy.LoadChart(chartPath)
+	if err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+	config.Betting = simulator.FlatBet(bet)
+
+	// Ctrl+C stops the simulation rather than leaving it half-printed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	start := time.Now()
+	report, err := simulator.Run(ctx, config)
+	if err != nil {
+		fmt.Printf("error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+		if err != nil {
+			fmt.Printf("error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Printf("Played %v rounds in %s.\n\n", config.Rounds, time.Since(start).Round(time.Millisecond))
+	fmt.Print(report)
+}
@@ -1 +1,3 @@
 module github.com/duckfullstop/checkmate
+
+go 1.22
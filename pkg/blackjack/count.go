@@ -0,0 +1,35 @@
+package blackjack
+
+// A Count keeps count of the cards dealt at a Table, as a card counter would. The counting package has them for the
+// common counting systems.
+type Count interface {
+	// RunningCount returns the count of every card seen since the shoe was shuffled.
+	RunningCount() float64
+	// TrueCount returns the running count per deck left in the shoe.
+	TrueCount() float64
+}
+
+// SetCount shares a Count with everyone at the table (e.g. so bots can bet by it), or stops sharing one with nil.
+// The Table doesn't keep the Count up to date itself; see the counting package for Counts that watch a Table.
+func (t *Table) SetCount(c Count) {
+	t.Lock()
+	defer t.Unlock()
+	t.count = c
+}
+
+// Count returns the Count shared with everyone at the table, or nil if there isn't one.
+func (t *Table) Count() Count {
+	t.Lock()
+	defer t.Unlock()
+	return t.count
+}
+
+// TrueCount returns the true count from the Count shared with everyone at the table, and false if there isn't one.
+func (t *Table) TrueCount() (count float64, ok bool) {
+	// The table lock isn't held while asking, so Counts are free to look at the Table.
+	c := t.Count()
+	if c == nil {
+		return 0, false
+	}
+	return c.TrueCount(), true
+}
@@ -0,0 +1,38 @@
+package blackjack
+
+import "testing"
+
+// fixedCount is a Count that never changes.
+type fixedCount float64
+
+func (c fixedCount) RunningCount() float64 {
+	return float64(c) * 2
+}
+
+func (c fixedCount) TrueCount() float64 {
+	return float64(c)
+}
+
+// Test that a Table shares the Count it's given.
+func TestTableCount(t *testing.T) {
+	table := NewTable(1)
+	if _, ok := table.TrueCount(); ok {
+		t.Errorf("expected no true count from a table without a count")
+	}
+	table.SetCount(fixedCount(1.5))
+	if count, ok := table.TrueCount(); !ok || count != 1.5 {
+		t.Errorf("expected a true count of 1.5, got %v (%v)", count, ok)
+	}
+	if table.Count().RunningCount() != 3 {
+		t.Errorf("expected a running count of 3, got %v", table.Count().RunningCount())
+	}
+	table.SetCount(nil)
+	if table.Count() != nil {
+		t.Errorf("expected the count to be removed, got %v", table.Count())
+	}
+
+	empty := &Table{}
+	if empty.CardsRemaining() != 0 {
+		t.Errorf("expected a table without a shoe to have no cards remaining, got %v", empty.CardsRemaining())
+	}
+}
@@ -0,0 +1,311 @@
+package blackjack
+
+import "github.com/duckfullstop/checkmate/pkg/playdeck"
+
+// DealerRule describes how the dealer plays out their hand once every Player has finished.
+type DealerRule uint8
+
+// The dealer plays by one of these rules.
+const (
+	// DealerStandsSoft17 (S17) has the dealer stand on any 17, including a soft 17 (e.g. ace and six).
+	DealerStandsSoft17 DealerRule = iota
+	// DealerHitsSoft17 (H17) has the dealer draw to a soft 17, standing only on a hard 17 and above.
+	DealerHitsSoft17
+)
+
+var dealerRuleNames = map[DealerRule]string{
+	DealerStandsSoft17: "S17",
+	DealerHitsSoft17:   "H17",
+}
+
+// String returns the common shorthand for this rule (e.g. "S17").
+// Unknown rules return "unknown".
+func (r DealerRule) String() string {
+	name, exists := dealerRuleNames[r]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// newDealer creates the special Player that plays the house's hand on the given Table.
+// SWEng: The dealer is deliberately just a Player with a flag set, so that it gets Hands (and scoring) for free.
+// It is never added to Table.Players, so it can't be acted upon by anyone else.
+func newDealer(t *Table) (dealer *Player) {
+	return &Player{
+		Table:  t,
+		dealer: true,
+		seat:   SeatDealer,
+	}
+}
+
+// SetDealerRule sets whether the dealer stands or hits on a soft 17. This is a shortcut for changing a single rule with SetRules().
+// The rule cannot be changed while the table is in play.
+func (t *Table) SetDealerRule(rule DealerRule) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.DealerRule = rule
+	})
+}
+
+// DealerRule returns the rule the dealer is currently playing by.
+func (t *Table) DealerRule() (rule DealerRule) {
+	return t.Rules().DealerRule
+}
+
+// DealerUpCard returns the dealer's face-up card for the current round.
+// It returns an error if the dealer has not been dealt a hand.
+func (t *Table) DealerUpCard() (card playdeck.Card, err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	h := t.dealerHand()
+	if h == nil {
+		return card, ErrDealerNoHand
+	}
+	h.RLock()
+	defer h.RUnlock()
+	if len(h.Cards) == 0 {
+		return card, ErrDealerNoHand
+	}
+	return h.Cards[0], nil
+}
+
+// DealerHand returns the dealer's Hand once the round has ended, so that it may be compared against.
+// While the round is in play the hole card is secret, so this returns ErrRoundNotEnded.
+func (t *Table) DealerHand() (hand *Hand, err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	err = t.checkPhase("see the dealer's hand", ErrRoundNotEnded, PhaseSettlement, PhaseIdle)
+	if err != nil {
+		return nil, err
+	}
+	hand = t.dealerHand()
+	if hand == nil {
+		return nil, ErrDealerNoHand
+	}
+	return hand, nil
+}
+
+// dealerHand returns the dealer's current Hand, or nil if there isn't one.
+// The table lock must be held by the caller.
+func (t *Table) dealerHand() (hand *Hand) {
+	if t.dealer == nil {
+		return nil
+	}
+	t.dealer.RLock()
+	defer t.dealer.RUnlock()
+	if len(t.dealer.Hands) == 0 {
+		return nil
+	}
+	return t.dealer.Hands[0]
+}
+
+// dealerShouldHit returns whether the dealer must draw another card to the given hand under the table's DealerRule.
+// The hand must already have been scored.
+func (t *Table) dealerShouldHit(h *Hand) bool {
+	h.RLock()
+	defer h.RUnlock()
+
+	if h.locked {
+		return false
+	}
+	if h.score < 17 {
+		return true
+	}
+	// A soft hand is one where an ace is still being counted as 11, so the best and minimum scores differ.
+	soft := h.score != h.minScore
+	return h.score == 17 && soft && t.sRules.DealerRule == DealerHitsSoft17
+}
+
+// playDealer plays out the dealer's hand according to the table's DealerRule, then locks it.
+// The table lock must be held by the caller.
+func (t *Table) playDealer() (err error) {
+	h := t.dealerHand()
+	if h == nil {
+		return ErrDealerNoHand
+	}
+
+	// Turn over the hole card.
+	h.RLock()
+	cards := len(h.Cards)
+	var hole *playdeck.Card
+	if cards >= 2 {
+		hole = &playdeck.Card{Suit: h.Cards[1].Suit, Value: h.Cards[1].Value}
+	}
+	h.RUnlock()
+	e := h.event(EventDealerTurn)
+	e.Card = hole
+	t.emit(e)
+
+	// Without a hole card, the dealer's second card comes now.
+	if cards < 2 {
+		err = h.addCard()
+		if err != nil {
+			return err
+		}
+		err = h.EvalScore()
+		if err != nil {
+			return err
+		}
+	}
+
+	// SWEng: If every Player is bust (or has surrendered) there's nobody left to beat, so the dealer just turns over the hole card.
+	if t.anyLiveHands() {
+		for t.dealerShouldHit(h) {
+			err = h.addCard()
+			if err != nil {
+				return err
+			}
+			err = h.EvalScore()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Bust hands are already locked by EvalScore.
+	h.Lock()
+	h.locked = true
+	bust := !h.valid
+	h.Unlock()
+	if bust {
+		t.emit(h.event(EventBust))
+	} else {
+		t.emit(h.event(EventDealerStand))
+	}
+	return
+}
+
+// anyLiveHands returns true if any Player at the table holds a hand that is neither bust nor surrendered.
+// The table lock must be held by the caller.
+func (t *Table) anyLiveHands() bool {
+	for _, p := range t.Players {
+		p.RLock()
+		for _, h := range p.Hands {
+			h.RLock()
+			valid := h.valid && !h.surrendered
+			h.RUnlock()
+			if valid {
+				p.RUnlock()
+				return true
+			}
+		}
+		p.RUnlock()
+	}
+	return false
+}
+
+// SetDealerPeek sets whether the dealer checks their hole card for blackjack straight after the deal when showing an ace or
+// ten-value card. If they have it, the round is over before any Player has to act.
+// This is a shortcut for changing a single rule with SetRules(), and cannot be changed while the table is in play.
+func (t *Table) SetDealerPeek(peek bool) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.DealerPeek = peek
+	})
+}
+
+// DealerPeeks returns whether the dealer checks their hole card for blackjack after the deal.
+func (t *Table) DealerPeeks() bool {
+	return t.Rules().DealerPeek
+}
+
+// PeekPending returns true while the dealer is waiting to check their hole card for blackjack.
+// While the peek is pending, Players may only make decisions that come before it (such as early surrender).
+func (t *Table) PeekPending() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.phase == PhaseInsurance
+}
+
+// DealerPeek has the dealer check their hole card for blackjack, closing the window for decisions that come before the peek
+// (insurance, even money and early surrender).
+// If the dealer has blackjack, every Player's hand is locked and the round can be ended straight away.
+// It returns ErrNoPeekPending if the dealer isn't waiting to peek.
+func (t *Table) DealerPeek() (err error) {
+	defer t.dispatchEvents()
+	t.Lock()
+	defer t.Unlock()
+
+	notPending := ErrTableNotInPlay
+	if t.phase == PhasePlayerTurns {
+		notPending = ErrNoPeekPending
+	}
+	err = t.checkPhase("peek", notPending, PhaseInsurance)
+	if err != nil {
+		return err
+	}
+	t.record(Action{Type: ActionDealerPeek, Seat: SeatDealer, HandIndex: -1})
+	return t.peek()
+}
+
+// startPeek checks whether the dealer should peek at their hole card after the deal, either doing so straight away
+// or opening a window for decisions that have to come first (insurance and early surrender). It moves the table on from
+// PhaseDealing, to PhaseInsurance if there's a window, or otherwise to PhasePlayerTurns.
+// The table lock must be held by the caller.
+func (t *Table) startPeek() (err error) {
+	if !(t.sRules.DealerPeek || t.sRules.Insurance) {
+		return t.setPhase(PhasePlayerTurns)
+	}
+	h := t.dealerHand()
+	if h == nil {
+		return ErrDealerNoHand
+	}
+	h.RLock()
+	if len(h.Cards) == 0 {
+		h.RUnlock()
+		return ErrDealerNoHand
+	}
+	upValue := h.Cards[0].Value
+	h.RUnlock()
+	// The dealer can only have blackjack if they're showing an ace or a ten-value card.
+	if !(upValue == playdeck.ValueAce || upValue >= playdeck.ValueTen) {
+		return t.setPhase(PhasePlayerTurns)
+	}
+	// Insurance is offered against an ace, and the dealer always checks once it's been decided.
+	if t.sRules.Insurance && upValue == playdeck.ValueAce {
+		t.insuranceOpen = true
+		return t.setPhase(PhaseInsurance)
+	}
+	if !t.sRules.DealerPeek {
+		return t.setPhase(PhasePlayerTurns)
+	}
+	if t.sRules.Surrender == SurrenderEarly {
+		return t.setPhase(PhaseInsurance)
+	}
+	return t.peek()
+}
+
+// peek has the dealer check their hole card, settling any insurance and locking every Player's hand if they have blackjack.
+// The table lock must be held by the caller.
+func (t *Table) peek() (err error) {
+	t.insuranceOpen = false
+	h := t.dealerHand()
+	if h == nil {
+		return ErrDealerNoHand
+	}
+	natural := h.Natural()
+	e := h.event(EventDealerPeek)
+	e.Score = 0
+	if natural {
+		h.RLock()
+		hole := h.Cards[1]
+		h.RUnlock()
+		e.Card = &hole
+		e.Score = 21
+	}
+	t.emit(e)
+	t.settleInsurance(natural)
+	if natural {
+		for _, p := range t.Players {
+			p.RLock()
+			for _, ph := range p.Hands {
+				ph.Lock()
+				ph.locked = true
+				ph.Unlock()
+			}
+			p.RUnlock()
+		}
+	}
+	return t.setPhase(PhasePlayerTurns)
+}
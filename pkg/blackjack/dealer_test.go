@@ -0,0 +1,242 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// Test that the dealer rule can be set and read back, and can't be changed mid-game.
+func TestDealerRule(t *testing.T) {
+	table := NewTable(1)
+	if table.DealerRule() != DealerStandsSoft17 {
+		t.Errorf("new table should default to S17, got %s", table.DealerRule())
+	}
+	err := table.SetDealerRule(DealerHitsSoft17)
+	if err != nil {
+		t.Error(err)
+	}
+	if table.DealerRule() != DealerHitsSoft17 {
+		t.Errorf("dealer rule not set, expected H17 got %s", table.DealerRule())
+	}
+	err = table.SetDealerRule(DealerRule(42))
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error when setting a bad rule, expected InvalidRule got %s", err)
+	}
+	if DealerRule(42).String() != "unknown" {
+		t.Errorf("bad rule returned a name of %s", DealerRule(42).String())
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	err = table.SetDealerRule(DealerStandsSoft17)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing rule mid-game, expected TableInPlay got %s", err)
+	}
+}
+
+// Test that the dealer is dealt an up card, keeps their hole card secret, and can't be played by anyone else.
+func TestDealerDeal(t *testing.T) {
+	table := NewTable(1)
+	_, err := table.DealerUpCard()
+	if !errors.Is(err, ErrDealerNoHand) {
+		t.Errorf("didn't get appropriate error when reading up card before deal, expected DealerNoHand got %s", err)
+	}
+
+	player := NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Error(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	if len(*table.Deck.Cards) != 48 {
+		t.Errorf("expected 4 cards to be dealt, deck has %v remaining", len(*table.Deck.Cards))
+	}
+	upCard, err := table.DealerUpCard()
+	if err != nil {
+		t.Error(err)
+	}
+	if !upCard.Valid() {
+		t.Errorf("dealer up card is invalid: %v", upCard)
+	}
+	_, err = table.DealerHand()
+	if !errors.Is(err, ErrRoundNotEnded) {
+		t.Errorf("didn't get appropriate error when reading dealer hand mid-game, expected RoundNotEnded got %s", err)
+	}
+	err = table.dealerHand().Hit()
+	if !errors.Is(err, ErrHandDealer) {
+		t.Errorf("didn't get appropriate error when hitting dealer hand, expected HandDealer got %s", err)
+	}
+
+	err = player.Hands[0].Stick()
+	if err != nil {
+		t.Error(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Error(err)
+	}
+	dealer, err := table.DealerHand()
+	if err != nil {
+		t.Error(err)
+	}
+	if dealer.Cards[0] != upCard {
+		t.Errorf("dealer up card changed during play, expected %s got %s", upCard.String(), dealer.Cards[0].String())
+	}
+	score, _, locked, valid := dealer.Score()
+	if !locked {
+		t.Errorf("dealer hand should be locked after playing out")
+	}
+	if valid && score < 17 {
+		t.Errorf("dealer stood on %v, should have drawn to at least 17", score)
+	}
+}
+
+// Test that the dealer doesn't bother drawing if every player is bust.
+func TestDealerAllPlayersBust(t *testing.T) {
+	table := NewTable(1)
+	player := NewPlayer()
+	err := table.Join(player)
+	if err != nil {
+		t.Error(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	hand := player.Hands[0]
+	hand.Cards = []playdeck.Card{
+		{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing},
+		{Suit: playdeck.SuitSpade, Value: playdeck.ValueQueen},
+		{Suit: playdeck.SuitSpade, Value: playdeck.ValueJack},
+	}
+	err = hand.EvalScore()
+	if err != nil {
+		t.Error(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Error(err)
+	}
+	dealer, err := table.DealerHand()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(dealer.Cards) != 2 {
+		t.Errorf("dealer drew with nobody left to beat, has %v cards", len(dealer.Cards))
+	}
+}
+
+// Test the dealer's drawing decisions under both soft 17 rules.
+func TestDealerShouldHit(t *testing.T) {
+	table := NewTable(1)
+	soft17 := testHand(t,
+		playdeck.Card{Suit: playdeck.SuitHeart, Value: playdeck.ValueAce},
+		playdeck.Card{Suit: playdeck.SuitClub, Value: playdeck.ValueSix},
+	)
+	hard17 := testHand(t,
+		playdeck.Card{Suit: playdeck.SuitHeart, Value: playdeck.ValueTen},
+		playdeck.Card{Suit: playdeck.SuitClub, Value: playdeck.ValueSeven},
+	)
+	hard16 := testHand(t,
+		playdeck.Card{Suit: playdeck.SuitHeart, Value: playdeck.ValueTen},
+		playdeck.Card{Suit: playdeck.SuitClub, Value: playdeck.ValueSix},
+	)
+
+	if table.dealerShouldHit(soft17) {
+		t.Errorf("S17 dealer should stand on soft 17")
+	}
+	if !table.dealerShouldHit(hard16) {
+		t.Errorf("S17 dealer should hit hard 16")
+	}
+	table.sRules.DealerRule = DealerHitsSoft17
+	if !table.dealerShouldHit(soft17) {
+		t.Errorf("H17 dealer should hit soft 17")
+	}
+	if table.dealerShouldHit(hard17) {
+		t.Errorf("H17 dealer should stand on hard 17")
+	}
+}
+
+// Test that the dealer checks for blackjack when the rules call for it, ending the round early if they have it.
+func TestDealerPeek(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+
+	table := NewTable(1)
+	if table.DealerPeeks() {
+		t.Errorf("new table should not have the dealer peek")
+	}
+	err := table.SetDealerPeek(true)
+	if err != nil {
+		t.Error(err)
+	}
+	if !table.DealerPeeks() {
+		t.Errorf("dealer peek not set")
+	}
+	err = table.DealerPeek()
+	if !errors.Is(err, ErrTableNotInPlay) {
+		t.Errorf("didn't get appropriate error when peeking out of play, expected TableNotInPlay got %s", err)
+	}
+
+	// No blackjack - play carries on.
+	table, player := riggedTable(t, 0, 0, king, six)
+	table.sRules.DealerPeek = true
+	d := table.dealerHand()
+	d.Cards = []playdeck.Card{ace, six}
+	err = d.EvalScore()
+	if err != nil {
+		t.Error(err)
+	}
+	// Back to straight after the deal.
+	table.phase = PhaseDealing
+	err = table.startPeek()
+	if err != nil {
+		t.Error(err)
+	}
+	if table.PeekPending() {
+		t.Errorf("dealer should peek straight away without early surrender")
+	}
+	err = table.DealerPeek()
+	if !errors.Is(err, ErrNoPeekPending) {
+		t.Errorf("didn't get appropriate error when peeking twice, expected NoPeekPending got %s", err)
+	}
+	_, _, locked, _ := player.Hands[0].Score()
+	if locked {
+		t.Errorf("hand locked even though the dealer doesn't have blackjack")
+	}
+
+	// Blackjack - everyone's done.
+	d.Cards = []playdeck.Card{ace, king}
+	err = d.EvalScore()
+	if err != nil {
+		t.Error(err)
+	}
+	// Back to straight after the deal.
+	table.phase = PhaseDealing
+	err = table.startPeek()
+	if err != nil {
+		t.Error(err)
+	}
+	_, _, locked, _ = player.Hands[0].Score()
+	if !locked {
+		t.Errorf("hand not locked even though the dealer has blackjack")
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Error(err)
+	}
+	outcome, err := player.Hands[0].Outcome()
+	if err != nil {
+		t.Error(err)
+	}
+	if outcome != OutcomeLose {
+		t.Errorf("hand should lose to a dealer blackjack, got %s", outcome)
+	}
+}
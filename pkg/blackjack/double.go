@@ -0,0 +1,150 @@
+package blackjack
+
+// DoubleTotals describes which two-card hands may be doubled down on.
+type DoubleTotals uint8
+
+// Doubling is restricted to one of these sets of totals.
+const (
+	// DoubleAnyTwo allows doubling on any two cards.
+	DoubleAnyTwo DoubleTotals = iota
+	// DoubleNineToEleven only allows doubling on totals of 9, 10 or 11 (the "Reno rule").
+	DoubleNineToEleven
+	// DoubleTenToEleven only allows doubling on totals of 10 or 11.
+	DoubleTenToEleven
+)
+
+var doubleTotalsNames = map[DoubleTotals]string{
+	DoubleAnyTwo:       "any two cards",
+	DoubleNineToEleven: "9-11",
+	DoubleTenToEleven:  "10-11",
+}
+
+// String returns a human-readable description of the totals that can be doubled on (e.g. "9-11").
+// Unknown values return "unknown".
+func (d DoubleTotals) String() string {
+	name, exists := doubleTotalsNames[d]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// Allows returns whether a hand with the given total may be doubled on.
+func (d DoubleTotals) Allows(total int) bool {
+	switch d {
+	case DoubleNineToEleven:
+		return total >= 9 && total <= 11
+	case DoubleTenToEleven:
+		return total >= 10 && total <= 11
+	}
+	return true
+}
+
+// DoubleRules describe when a Player may double down on a hand.
+type DoubleRules struct {
+	// Totals restricts which two-card totals may be doubled on.
+	Totals DoubleTotals `json:"totals"`
+	// AfterSplit allows hands made by splitting to be doubled (often shortened to DAS).
+	AfterSplit bool `json:"afterSplit"`
+}
+
+// DefaultDoubleRules are the double down rules new Tables are created with.
+var DefaultDoubleRules = DoubleRules{
+	Totals:     DoubleAnyTwo,
+	AfterSplit: true,
+}
+
+// Valid returns whether these DoubleRules make sense.
+func (r DoubleRules) Valid() bool {
+	_, exists := doubleTotalsNames[r.Totals]
+	return exists
+}
+
+// SetDoubleRules sets when Players may double down. This is a shortcut for changing a single rule with SetRules().
+// The rules cannot be changed while the table is in play.
+func (t *Table) SetDoubleRules(rules DoubleRules) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.Double = rules
+	})
+}
+
+// DoubleRules returns the rules currently governing double downs.
+func (t *Table) DoubleRules() (rules DoubleRules) {
+	return t.Rules().Double
+}
+
+// DoubleDown doubles this Hand's wager (taking the extra from the Player's bankroll), draws exactly one more card, and then
+// locks the hand. It can only be done on the first two cards of a hand, and only if the table's DoubleRules allow it.
+func (h *Hand) DoubleDown() (err error) {
+	defer h.Table.dispatchEvents()
+	defer h.Table.nextTurn()
+	err = h.canPlay()
+	if err != nil {
+		return err
+	}
+	rules := h.Table.DoubleRules()
+	splitRules := h.Table.SplitRules()
+
+	h.RLock()
+	cards := len(h.Cards)
+	score := h.score
+	split := h.split
+	wager := h.wager
+	oneCard := h.oneCardAces(splitRules)
+	h.RUnlock()
+
+	if oneCard {
+		return ErrSplitAcesOneCard
+	}
+	if cards != 2 {
+		return ErrHandNotTwoCards
+	}
+	if split && !rules.AfterSplit {
+		return ErrDoubleAfterSplit
+	}
+	if !rules.Totals.Allows(score) {
+		return ErrDoubleTotal
+	}
+
+	err = h.Player.takeFunds(wager)
+	if err != nil {
+		return err
+	}
+	// Exactly one card, through the same path as Hit(). The hand is only doubled once there's a card for it, so if the
+	// shoe can't give one the stake goes back and the hand is left as it was.
+	h.Table.Lock()
+	card, position, err := h.Table.draw()
+	if err != nil {
+		h.Table.Unlock()
+		h.Player.giveFunds(wager)
+		return err
+	}
+	h.Lock()
+	h.wager += wager
+	h.doubled = true
+	h.Unlock()
+	e := h.event(EventDouble)
+	e.Amount = wager + wager
+	h.Table.emitAction(ActionDouble, e)
+	h.dealCard(card, position)
+	h.Table.Unlock()
+
+	err = h.EvalScore()
+	if err != nil {
+		return err
+	}
+
+	// If the card bust us, the hand's already locked.
+	h.Lock()
+	h.locked = true
+	h.Unlock()
+	h.emitBust()
+	return
+}
+
+// Doubled returns true if this Hand has been doubled down on. Thread-safe.
+func (h *Hand) Doubled() bool {
+	h.RLock()
+	defer h.RUnlock()
+	return h.doubled
+}
@@ -0,0 +1,183 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+func TestDoubleRules(t *testing.T) {
+	table := NewTable(1)
+	if table.DoubleRules() != DefaultDoubleRules {
+		t.Errorf("new table should have default double rules, got %+v", table.DoubleRules())
+	}
+	err := table.SetDoubleRules(DoubleRules{Totals: DoubleTotals(42)})
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error when setting bad rules, expected InvalidRule got %s", err)
+	}
+	if DoubleTotals(42).String() != "unknown" {
+		t.Errorf("bad double totals returned a name of %s", DoubleTotals(42).String())
+	}
+	rules := DoubleRules{Totals: DoubleTenToEleven}
+	err = table.SetDoubleRules(rules)
+	if err != nil {
+		t.Error(err)
+	}
+	if table.DoubleRules() != rules {
+		t.Errorf("double rules not set, got %+v", table.DoubleRules())
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	err = table.SetDoubleRules(DefaultDoubleRules)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing rules mid-game, expected TableInPlay got %s", err)
+	}
+}
+
+// Test that doubling down doubles the wager, draws one card and locks the hand.
+func TestDoubleDown(t *testing.T) {
+	five := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueFive}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+
+	_, player := riggedTable(t, 100, 10, five, six)
+	hand := player.Hands[0]
+	err := hand.DoubleDown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hand.Wager() != 20 || player.Bankroll() != 80 {
+		t.Errorf("double not staked correctly, expected wager 20 and bankroll 80 got %v and %v", hand.Wager(), player.Bankroll())
+	}
+	if len(hand.Cards) != 3 {
+		t.Errorf("double should draw exactly one card, hand has %v", len(hand.Cards))
+	}
+	_, _, locked, _ := hand.Score()
+	if !locked || !hand.Doubled() {
+		t.Errorf("hand should be locked and marked doubled after doubling down")
+	}
+	err = hand.DoubleDown()
+	if !errors.Is(err, ErrHandLocked) {
+		t.Errorf("didn't get appropriate error when doubling twice, expected HandLocked got %s", err)
+	}
+
+	_, player = riggedTable(t, 15, 10, five, six)
+	err = player.Hands[0].DoubleDown()
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("didn't get appropriate error when doubling without funds, expected InsufficientFunds got %s", err)
+	}
+	if player.Hands[0].Wager() != 10 || len(player.Hands[0].Cards) != 2 {
+		t.Errorf("refused double should leave the hand untouched")
+	}
+}
+
+// Test that the table's double rules are honoured.
+func TestDoubleDownRestrictions(t *testing.T) {
+	two := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueTwo}
+	four := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueFour}
+	five := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueFive}
+	eight := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueEight}
+
+	_, player := riggedTable(t, 0, 0, two, two, two)
+	err := player.Hands[0].DoubleDown()
+	if !errors.Is(err, ErrHandNotTwoCards) {
+		t.Errorf("didn't get appropriate error when doubling three cards, expected HandNotTwoCards got %s", err)
+	}
+
+	table, player := riggedTable(t, 0, 0, four, five)
+	table.sRules.Double.Totals = DoubleTenToEleven
+	err = player.Hands[0].DoubleDown()
+	if !errors.Is(err, ErrDoubleTotal) {
+		t.Errorf("didn't get appropriate error when doubling 9 under 10-11, expected DoubleTotal got %s", err)
+	}
+	table.sRules.Double.Totals = DoubleNineToEleven
+	err = player.Hands[0].DoubleDown()
+	if err != nil {
+		t.Errorf("couldn't double 9 under 9-11: %s", err)
+	}
+
+	table, player = riggedTable(t, 0, 0, eight, eight)
+	table.sRules.Double.AfterSplit = false
+	_, err = player.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.Hands[0].DoubleDown()
+	if !errors.Is(err, ErrDoubleAfterSplit) {
+		t.Errorf("didn't get appropriate error when doubling after split, expected DoubleAfterSplit got %s", err)
+	}
+	table.sRules.Double.AfterSplit = true
+	// The second hand's turn comes once the first is done with.
+	err = player.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.Hands[1].DoubleDown()
+	if err != nil {
+		t.Errorf("couldn't double after split with DAS: %s", err)
+	}
+}
+
+// Test that a double that can't be dealt its card (here, because a provably fair shoe can't be refilled from the discards)
+// hands back the extra stake and leaves the hand as it was.
+func TestDoubleDownEmptyShoe(t *testing.T) {
+	five := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueFive}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+
+	rules := DefaultRules
+	rules.Decks = 1
+	table := seatedTable(t, rules, 1, 1)
+	_, err := table.EnableFairShuffle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if table.PeekPending() {
+		err = table.DealerPeek()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	player := table.Players[0]
+	hand := player.Hands[0]
+	hand.Cards = []playdeck.Card{five, six}
+	hand.locked = false
+	err = hand.EvalScore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.Discards.PushCard(six)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.Deck.Cards = &[]playdeck.Card{}
+
+	for i := 0; i < 2; i++ {
+		err = hand.DoubleDown()
+		if !errors.Is(err, playdeck.ErrDeckEmpty) {
+			t.Errorf("didn't get appropriate error when doubling from an empty shoe, expected DeckEmpty got %s", err)
+		}
+		if hand.Wager() != 10 || player.Bankroll() != 90 || hand.Doubled() || len(hand.Cards) != 2 {
+			t.Errorf("failed double should leave the hand untouched, got wager %v, bankroll %v and %s", hand.Wager(), player.Bankroll(), hand)
+		}
+	}
+	_, _, locked, _ := hand.Score()
+	if locked {
+		t.Errorf("failed double shouldn't lock the hand")
+	}
+
+	// Even if a doubled hand were somehow left unlocked, it can't be played on.
+	hand.doubled = true
+	err = hand.Hit()
+	if !errors.Is(err, ErrHandLocked) {
+		t.Errorf("didn't get appropriate error when hitting a doubled hand, expected HandLocked got %s", err)
+	}
+	err = hand.DoubleDown()
+	if !errors.Is(err, ErrHandLocked) {
+		t.Errorf("didn't get appropriate error when doubling twice, expected HandLocked got %s", err)
+	}
+}
@@ -0,0 +1,137 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+)
+
+// Hands can be marshalled to JSON and binary, so they can be stored and sent over the wire. Only a Hand's cards, score,
+// lock and validity state are included; its Table and Player aren't, and are left alone when unmarshalling.
+// These formats are stable; don't change them without a very good reason.
+
+// handJSON is the JSON form of a Hand.
+type handJSON struct {
+	Cards    []playdeck.Card `json:"cards"`
+	Score    int             `json:"score"`
+	MinScore int             `json:"minScore"`
+	Locked   bool            `json:"locked"`
+	Valid    bool            `json:"valid"`
+}
+
+// handBinaryVersion is the first byte of a binary Hand, so the format can change later.
+const handBinaryVersion = 1
+
+// Flags in the second byte of a binary Hand.
+const (
+	handFlagLocked = 1 << iota
+	handFlagValid
+)
+
+// MarshalJSON returns this Hand as a JSON object, e.g. {"cards":["AS","TD"],"score":21,"minScore":11,"locked":true,"valid":true}.
+func (h *Hand) MarshalJSON() (data []byte, err error) {
+	h.RLock()
+	defer h.RUnlock()
+	cards := h.Cards
+	if cards == nil {
+		cards = []playdeck.Card{}
+	}
+	return json.Marshal(handJSON{
+		Cards:    cards,
+		Score:    h.score,
+		MinScore: h.minScore,
+		Locked:   h.locked,
+		Valid:    h.valid,
+	})
+}
+
+// UnmarshalJSON reads this Hand's cards and state from a JSON object written by MarshalJSON().
+// It returns an error wrapping ErrHandInvalid if the score or validity don't match the cards.
+func (h *Hand) UnmarshalJSON(data []byte) (err error) {
+	var j handJSON
+	err = json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+	return h.restore(j.Cards, j.Score, j.MinScore, j.Locked, j.Valid)
+}
+
+// MarshalBinary returns this Hand as bytes: a version byte, a flags byte (locked and valid), the score, the minimum score,
+// and then one byte per card (see playdeck.Card.MarshalBinary()).
+func (h *Hand) MarshalBinary() (data []byte, err error) {
+	h.RLock()
+	defer h.RUnlock()
+	if h.score > 255 || h.minScore > 255 {
+		return nil, fmt.Errorf("%w: score too large to marshal", ErrHandInvalid)
+	}
+	var flags byte
+	if h.locked {
+		flags |= handFlagLocked
+	}
+	if h.valid {
+		flags |= handFlagValid
+	}
+	data = []byte{handBinaryVersion, flags, byte(h.score), byte(h.minScore)}
+	for _, c := range h.Cards {
+		b, err := c.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary reads this Hand's cards and state from bytes written by MarshalBinary().
+// It returns an error wrapping ErrHandInvalid if the data is malformed, or if the score or validity don't match the cards.
+func (h *Hand) UnmarshalBinary(data []byte) (err error) {
+	if len(data) < 4 {
+		return fmt.Errorf("%w: expected at least 4 bytes, got %v", ErrHandInvalid, len(data))
+	}
+	if data[0] != handBinaryVersion {
+		return fmt.Errorf("%w: unknown binary version %v", ErrHandInvalid, data[0])
+	}
+	cards := make([]playdeck.Card, len(data)-4)
+	for i := range cards {
+		err = cards[i].UnmarshalBinary(data[4+i : 5+i])
+		if err != nil {
+			return err
+		}
+	}
+	return h.restore(cards, int(data[2]), int(data[3]), data[1]&handFlagLocked != 0, data[1]&handFlagValid != 0)
+}
+
+// restore replaces this Hand's cards and state, after checking that the state is what the cards would score.
+func (h *Hand) restore(cards []playdeck.Card, score int, minScore int, locked bool, valid bool) (err error) {
+	err = checkScore(cards, score, minScore, valid)
+	if err != nil {
+		return err
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	h.Cards = cards
+	h.score = score
+	h.minScore = minScore
+	h.locked = locked
+	h.valid = valid
+	return
+}
+
+// checkScore returns an error wrapping ErrHandInvalid if the given score and validity aren't what the cards would score.
+// Fewer than two cards (e.g. a dealer with no hole card) aren't scored at all.
+func checkScore(cards []playdeck.Card, score int, minScore int, valid bool) (err error) {
+	// Score the cards on a scratch hand, and make sure they agree.
+	check := Hand{Cards: cards, valid: true}
+	if len(cards) >= 2 {
+		err = check.EvalScore()
+		if err != nil {
+			return err
+		}
+	}
+	if check.score != score || check.minScore != minScore || check.valid != valid {
+		return fmt.Errorf("%w: score %v/%v (valid %v) doesn't match the cards %s", ErrHandInvalid, score, minScore, valid,
+			playdeck.FormatCards(cards))
+	}
+	return
+}
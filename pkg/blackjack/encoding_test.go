@@ -0,0 +1,96 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"reflect"
+	"testing"
+)
+
+// Test that hands survive a round trip through each encoding.
+func TestHandEncoding(t *testing.T) {
+	for _, cards := range []string{"AS KD", "AS 9D AH", "TS 6D KH", "", "7C"} {
+		parsed, err := playdeck.ParseCards(cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Hands with fewer than two cards (e.g. a dealer with no hole card) aren't scored.
+		hand := &Hand{Cards: parsed, valid: true, locked: true}
+		if len(parsed) >= 2 {
+			err = hand.EvalScore()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		data, err := json.Marshal(hand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromJSON := new(Hand)
+		err = json.Unmarshal(data, fromJSON)
+		if err != nil {
+			t.Errorf("%q: %s", cards, err)
+		}
+		bin, err := hand.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromBinary := new(Hand)
+		err = fromBinary.UnmarshalBinary(bin)
+		if err != nil {
+			t.Errorf("%q: %s", cards, err)
+		}
+
+		for _, h := range []*Hand{fromJSON, fromBinary} {
+			score, minScore, locked, valid := hand.Score()
+			s2, m2, l2, v2 := h.Score()
+			if score != s2 || minScore != m2 || locked != l2 || valid != v2 || h.String() != hand.String() {
+				t.Errorf("%q didn't survive encoding, got %q scoring %v/%v locked %v valid %v", cards, h.String(), s2, m2, l2, v2)
+			}
+		}
+	}
+
+	hand, err := ParseHand("AS KD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(hand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"cards":["AS","KD"],"score":21,"minScore":11,"locked":false,"valid":true}`
+	if string(data) != expected {
+		t.Errorf("hand encoded to JSON as %s, expected %s", data, expected)
+	}
+	var decoded map[string]interface{}
+	err = json.Unmarshal(data, &decoded)
+	if err != nil || !reflect.DeepEqual(decoded["cards"], []interface{}{"AS", "KD"}) {
+		t.Errorf("hand JSON isn't readable as plain JSON: %v (%v)", decoded, err)
+	}
+}
+
+// Test that encoded hands that don't add up are refused.
+func TestHandEncodingInvalid(t *testing.T) {
+	h := new(Hand)
+	err := json.Unmarshal([]byte(`{"cards":["AS","KD"],"score":20,"minScore":11,"locked":false,"valid":true}`), h)
+	if !errors.Is(err, ErrHandInvalid) {
+		t.Errorf("didn't get appropriate error with a wrong score, expected HandInvalid got %v", err)
+	}
+	err = json.Unmarshal([]byte(`{"cards":["TS","KD","5H"],"score":25,"minScore":25,"locked":true,"valid":true}`), h)
+	if !errors.Is(err, ErrHandInvalid) {
+		t.Errorf("didn't get appropriate error with a bust hand marked valid, expected HandInvalid got %v", err)
+	}
+	err = h.UnmarshalBinary([]byte{handBinaryVersion, 0})
+	if !errors.Is(err, ErrHandInvalid) {
+		t.Errorf("didn't get appropriate error with truncated data, expected HandInvalid got %v", err)
+	}
+	err = h.UnmarshalBinary([]byte{42, 0, 0, 0})
+	if !errors.Is(err, ErrHandInvalid) {
+		t.Errorf("didn't get appropriate error with an unknown version, expected HandInvalid got %v", err)
+	}
+	if len(h.Cards) != 0 {
+		t.Errorf("refused data shouldn't change the hand, got %s", h.String())
+	}
+}
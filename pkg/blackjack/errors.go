@@ -9,10 +9,44 @@ var (
 	ErrHandNotLocked            = errors.New("hand is not locked")
 	ErrHandInvalid              = errors.New("hand is not correctly instantiated")
 	ErrHandBust                 = errors.New("hand is bust")
+	ErrHandDealer               = errors.New("hand belongs to the dealer")
+	ErrHandNotTwoCards          = errors.New("hand does not have exactly two cards")
+	ErrDoubleTotal              = errors.New("hand total cannot be doubled on by table rules")
+	ErrDoubleAfterSplit         = errors.New("doubling after a split is not allowed by table rules")
+	ErrSurrenderNotAllowed      = errors.New("surrender is not allowed by table rules")
+	ErrSurrenderAfterSplit      = errors.New("split hands cannot be surrendered")
+	ErrHandNotNatural           = errors.New("hand is not a natural")
+	ErrInsuranceClosed          = errors.New("insurance is not on offer")
+	ErrInsuranceDecided         = errors.New("insurance has already been decided for this hand")
+	ErrSplitNotPair             = errors.New("hand is not a pair that can be split")
+	ErrSplitNotAllowed          = errors.New("split is not allowed by table rules")
+	ErrSplitLimit               = errors.New("player cannot split any more hands")
+	ErrSplitAcesOneCard         = errors.New("split aces may only take one card")
 	ErrInvalidCard              = errors.New("card in hand is invalid")
+	ErrInvalidRule              = errors.New("rule is invalid")
+	ErrDealerNoHand             = errors.New("dealer has not been dealt a hand")
+	ErrPeekPending              = errors.New("dealer has not yet checked for blackjack")
+	ErrNoPeekPending            = errors.New("dealer is not waiting to check for blackjack")
 	ErrPlayerNoTable            = errors.New("player has no table assigned")
 	ErrPlayerInvalid            = errors.New("player is invalid")
+	ErrInvalidAmount            = errors.New("amount is invalid")
+	ErrInsufficientFunds        = errors.New("insufficient funds")
+	ErrBetBelowMinimum          = errors.New("bet is below the table minimum")
+	ErrBetAboveMaximum          = errors.New("bet is above the table maximum")
 	ErrTableInPlay              = errors.New("table is in play")
 	ErrTableNotInPlay           = errors.New("table is not in play")
 	ErrTablePlayerAlreadyJoined = errors.New("player already on table")
+	ErrRoundNotEnded            = errors.New("round has not ended")
+	ErrRoundSettled             = errors.New("round has already been settled")
+	ErrFairShuffleOff           = errors.New("provably fair shuffling is not enabled")
+	ErrShoeNotFinished          = errors.New("shoe is still in use")
+	ErrFairCommitment           = errors.New("server seed does not match its commitment")
+	ErrFairMismatch             = errors.New("dealt cards do not match the shuffle")
+	ErrInvalidSnapshot          = errors.New("snapshot is invalid")
+	ErrReplayMismatch           = errors.New("replayed round does not match its log")
+	ErrReplayFinished           = errors.New("no more actions to replay")
+	ErrReplayStart              = errors.New("replay is at the start of the round")
+	ErrWrongPhase               = errors.New("action is not allowed in the table's current phase")
+	ErrPhaseTransition          = errors.New("table cannot move between these phases")
+	ErrNotYourTurn              = errors.New("it is not this hand's turn")
 )
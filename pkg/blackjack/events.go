@@ -0,0 +1,353 @@
+package blackjack
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"sync"
+)
+
+// EventType describes what happened at a Table.
+type EventType uint8
+
+// Tables publish Events of these types.
+const (
+	// EventPlayerJoined is published when a Player sits down at the table.
+	EventPlayerJoined EventType = iota
+	// EventBetPlaced is published when a Player places (or changes) their bet. Amount is the new bet.
+	EventBetPlaced
+	// EventShuffle is published when the shoe is reshuffled.
+	EventShuffle
+	// EventRoundDealt is published once every Hand (and the dealer's) has been dealt.
+	EventRoundDealt
+	// EventCardDealt is published for every card dealt to a Hand. The dealer's hole card is Hidden, and has no Card.
+	EventCardDealt
+	// EventHit is published when a Hand hits, just before its card is dealt.
+	EventHit
+	// EventStick is published when a Hand sticks.
+	EventStick
+	// EventDouble is published when a Hand doubles down, just before its card is dealt. Amount is the new wager.
+	EventDouble
+	// EventSplit is published when a Hand is split, just before both Hands are dealt their second card.
+	// Amount is the wager on the new Hand, which sits directly after the split one.
+	EventSplit
+	// EventSurrender is published when a Hand is surrendered.
+	EventSurrender
+	// EventInsurance is published when a Hand decides on insurance. Amount is the insurance staked (0 if declined).
+	EventInsurance
+	// EventEvenMoney is published when a Hand takes even money.
+	EventEvenMoney
+	// EventBust is published when a Hand (including the dealer's) goes bust.
+	EventBust
+	// EventDealerPeek is published when the dealer checks their hole card for blackjack. If they have it, Card is the hole card.
+	EventDealerPeek
+	// EventDealerTurn is published when the dealer starts playing their hand. Card is the hole card, now turned over.
+	EventDealerTurn
+	// EventDealerStand is published when the dealer finishes playing their hand without going bust.
+	EventDealerStand
+	// EventRoundEnded is published once the dealer has played, and the round is over.
+	EventRoundEnded
+	// EventSettled is published for every Hand when the round is settled. Outcome is its Outcome, and Amount is its Net.
+	EventSettled
+	// EventTurn is published when it becomes a Hand's turn to play (see Table.ActiveHand()). It isn't published under
+	// simultaneous play.
+	EventTurn
+	// EventTimeout is published when a Hand runs out of time to decide (see RuleSet.DecisionTimeout), just before the
+	// table's TimeoutAction is taken for it.
+	EventTimeout
+)
+
+var eventTypeNames = map[EventType]string{
+	EventPlayerJoined: "player joined",
+	EventBetPlaced:    "bet placed",
+	EventShuffle:      "shuffle",
+	EventRoundDealt:   "round dealt",
+	EventCardDealt:    "card dealt",
+	EventHit:          "hit",
+	EventStick:        "stick",
+	EventDouble:       "double",
+	EventSplit:        "split",
+	EventSurrender:    "surrender",
+	EventInsurance:    "insurance",
+	EventEvenMoney:    "even money",
+	EventBust:         "bust",
+	EventDealerPeek:   "dealer peek",
+	EventDealerTurn:   "dealer turn",
+	EventDealerStand:  "dealer stand",
+	EventRoundEnded:   "round ended",
+	EventSettled:      "settled",
+	EventTurn:         "turn",
+	EventTimeout:      "timeout",
+}
+
+// String returns a human-readable name for this event type (e.g. "card dealt"). Unknown values return "unknown".
+func (e EventType) String() string {
+	name, exists := eventTypeNames[e]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// SeatDealer is the Seat of Events about the dealer, and of Events about the whole Table.
+const SeatDealer = -1
+
+// An Event is something that happened at a Table. See Table.Subscribe().
+// Only the fields relevant to the Event's Type are set.
+type Event struct {
+	// Seq numbers every Event published by a Table, starting from 1. Events are always delivered in Seq order.
+	Seq uint64 `json:"seq"`
+	// Type is what happened.
+	Type EventType `json:"type"`
+
+	// Seat is the index of the Player in Table.Players, or SeatDealer.
+	Seat int `json:"seat"`
+	// HandIndex is the index of the Hand in Player.Hands at the time, or -1 if the Event isn't about a Hand.
+	HandIndex int `json:"hand"`
+	// Player and Hand point to the Player and Hand the Event is about, if any. They're never set for the dealer, whose
+	// Hand (and hole card) stays out of reach until the round's over; see Table.DealerHand().
+	Player *Player `json:"-"`
+	Hand   *Hand   `json:"-"`
+
+	// Card is the card dealt or revealed.
+	Card *playdeck.Card `json:"card,omitempty"`
+	// Hidden is true if a card was dealt face down.
+	Hidden bool `json:"hidden,omitempty"`
+	// Score is the Hand's score at the time (except for EventCardDealt, where the new card hasn't been scored yet).
+	Score int `json:"score,omitempty"`
+	// Amount is the money involved, if any (see each EventType).
+	Amount int `json:"amount,omitempty"`
+	// Outcome is the Hand's Outcome, for EventSettled.
+	Outcome Outcome `json:"outcome,omitempty"`
+}
+
+// eventBus queues a Table's Events, and delivers them to its subscribers in order.
+// It has its own lock, so Events can be published whether or not the table lock is held.
+type eventBus struct {
+	sync.Mutex
+	seq         uint64
+	queue       []queued
+	subscribers []subscriber
+	nextID      uint64
+	dispatching bool
+}
+
+// queued is an entry in an eventBus's queue: either an Event, or an Action marking the start of the Events it caused.
+type queued struct {
+	event  Event
+	action *Action
+}
+
+// subscriber is anything listening to a Table. Only Recorders listen for Actions.
+type subscriber struct {
+	id     uint64
+	fn     func(e Event)
+	action func(a Action)
+}
+
+// Subscribe registers a function to be called with every Event this Table publishes, returning a function that unsubscribes it.
+// Events are delivered one at a time, in order, to every subscriber, and never while any of the table's locks are held - so
+// subscribers are free to call back into the Table (even to play), though they should be quick about it, as they hold up
+// every other subscriber.
+func (t *Table) Subscribe(fn func(e Event)) (unsubscribe func()) {
+	return t.subscribe(subscriber{fn: fn})
+}
+
+// subscribe is the implementation behind Subscribe(), which also allows for listening to Actions.
+func (t *Table) subscribe(s subscriber) (unsubscribe func()) {
+	b := &t.events
+	b.Lock()
+	defer b.Unlock()
+	b.nextID++
+	id := b.nextID
+	s.id = id
+	b.subscribers = append(b.subscribers, s)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.Lock()
+			defer b.Unlock()
+			for i, s := range b.subscribers {
+				if s.id == id {
+					b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// Events returns a channel that receives every Event this Table publishes, and a function that unsubscribes it (and closes
+// the channel). If the channel's buffer fills up, delivery waits for it to be read, holding up the Table's other subscribers.
+func (t *Table) Events(buffer int) (events <-chan Event, unsubscribe func()) {
+	ch := make(chan Event, buffer)
+	done := make(chan struct{})
+	// sending is held for reading while an Event is being sent, so that the channel isn't closed mid-send.
+	var sending sync.RWMutex
+	closed := false
+
+	stop := t.Subscribe(func(e Event) {
+		sending.RLock()
+		defer sending.RUnlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- e:
+		case <-done:
+		}
+	})
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			stop()
+			close(done)
+			sending.Lock()
+			defer sending.Unlock()
+			closed = true
+			close(ch)
+		})
+	}
+}
+
+// emit queues an Event to be delivered by the next dispatchEvents().
+func (t *Table) emit(e Event) {
+	b := &t.events
+	b.Lock()
+	defer b.Unlock()
+	b.seq++
+	e.Seq = b.seq
+	b.queue = append(b.queue, queued{event: e})
+}
+
+// record queues an Action for any Recorders, to be delivered just before the Events it causes. It returns the queued
+// Action, so that it can be filled in before it's delivered (while the table lock is still held), or nil if nothing is recording.
+func (t *Table) record(a Action) (queuedAction *Action) {
+	b := &t.events
+	b.Lock()
+	defer b.Unlock()
+	if !b.recording() {
+		return nil
+	}
+	queuedAction = &a
+	b.queue = append(b.queue, queued{action: queuedAction})
+	return queuedAction
+}
+
+// emitAction queues an Action for any Recorders, followed straight away by the Event that describes it.
+func (t *Table) emitAction(typ ActionType, e Event) {
+	b := &t.events
+	b.Lock()
+	defer b.Unlock()
+	if b.recording() {
+		b.queue = append(b.queue, queued{action: &Action{Type: typ, Seat: e.Seat, HandIndex: e.HandIndex, Amount: e.Amount}})
+	}
+	b.seq++
+	e.Seq = b.seq
+	b.queue = append(b.queue, queued{event: e})
+}
+
+// recording returns true if any of this bus's subscribers are Recorders. The bus lock must be held by the caller.
+func (b *eventBus) recording() bool {
+	for _, s := range b.subscribers {
+		if s.action != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// emitTable queues an Event about the whole Table.
+func (t *Table) emitTable(typ EventType) {
+	t.emit(Event{Type: typ, Seat: SeatDealer, HandIndex: -1})
+}
+
+// dispatchEvents delivers every queued Event to the Table's subscribers.
+// It must be called without any of the table's locks held, so public methods defer it before taking them.
+// If Events are already being delivered (e.g. a subscriber has called back into the Table), the queued Events are left for
+// that delivery to pick up, so that they stay in order.
+func (t *Table) dispatchEvents() {
+	if t == nil {
+		return
+	}
+	b := &t.events
+	b.Lock()
+	if b.dispatching {
+		b.Unlock()
+		return
+	}
+	b.dispatching = true
+	for len(b.queue) > 0 {
+		queue := b.queue
+		b.queue = nil
+		subscribers := append([]subscriber(nil), b.subscribers...)
+		b.Unlock()
+		for _, q := range queue {
+			for _, s := range subscribers {
+				switch {
+				case q.action != nil && s.action != nil:
+					s.action(*q.action)
+				case q.action == nil && s.fn != nil:
+					s.fn(q.event)
+				}
+			}
+		}
+		b.Lock()
+	}
+	b.dispatching = false
+	b.Unlock()
+}
+
+// event returns an Event of the given type about this Hand.
+// Neither the Player's lock nor this Hand's lock may be held by the caller.
+func (h *Hand) event(typ EventType) (e Event) {
+	p := h.Player
+	if p == nil {
+		return Event{Type: typ, Seat: SeatDealer, HandIndex: -1, Hand: h}
+	}
+	p.RLock()
+	defer p.RUnlock()
+	return h.lockedEvent(typ)
+}
+
+// lockedEvent is event() for when the Player's lock is already held by the caller. This Hand's lock may not be held.
+func (h *Hand) lockedEvent(typ EventType) (e Event) {
+	p := h.Player
+	e = Event{Type: typ, Seat: SeatDealer, HandIndex: -1}
+	if p != nil {
+		if !p.dealer {
+			e.Player, e.Hand = p, h
+		}
+		e.Seat = p.seat
+		for i, ph := range p.Hands {
+			if ph == h {
+				e.HandIndex = i
+				break
+			}
+		}
+	}
+	h.RLock()
+	e.Score = h.score
+	h.RUnlock()
+	return e
+}
+
+// emitHand queues an Event of the given type about this Hand, recording it as the given Action.
+// Neither the Player's lock nor this Hand's lock may be held by the caller.
+func (h *Hand) emitHand(typ EventType, action ActionType) {
+	if h.Table == nil {
+		return
+	}
+	h.Table.emitAction(action, h.event(typ))
+}
+
+// emitBust queues an EventBust if this Hand has gone bust.
+// Neither the Player's lock nor this Hand's lock may be held by the caller.
+func (h *Hand) emitBust() {
+	h.RLock()
+	bust := !h.valid
+	h.RUnlock()
+	if bust && h.Table != nil {
+		h.Table.emit(h.event(EventBust))
+	}
+}
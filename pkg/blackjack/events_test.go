@@ -0,0 +1,279 @@
+package blackjack
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"slices"
+	"testing"
+)
+
+// eventsTable is a helper that returns a table with a single seated player who has placed a bet of 10.
+func eventsTable(t *testing.T) (table *Table, player *Player) {
+	t.Helper()
+	rules := DefaultRules
+	rules.Decks = 1
+	table = seatedTable(t, rules, 7, 1)
+	return table, table.Players[0]
+}
+
+// Test that a whole round publishes the events we'd expect, in order.
+func TestEventsRound(t *testing.T) {
+	table := NewTable(1)
+	err := table.SetSource(playdeck.NewSeededSource(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []Event
+	unsubscribe := table.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+	defer unsubscribe()
+
+	player := NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.Deposit(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.PlaceBet(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	err = player.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = table.Settle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, e := range events {
+		if e.Seq != uint64(i+1) {
+			t.Fatalf("event %v (%s) has seq %v, expected %v", i, e.Type, e.Seq, i+1)
+		}
+	}
+
+	// The first few events are always the same.
+	expected := []EventType{EventPlayerJoined, EventBetPlaced, EventShuffle, EventCardDealt, EventCardDealt, EventCardDealt,
+		EventCardDealt, EventRoundDealt, EventTurn, EventStick, EventDealerTurn}
+	if len(events) < len(expected) {
+		t.Fatalf("expected at least %v events, got %v", len(expected), len(events))
+	}
+	for i, typ := range expected {
+		if events[i].Type != typ {
+			t.Errorf("expected event %v to be %s, got %s", i, typ, events[i].Type)
+		}
+	}
+	if events[1].Amount != 10 || events[1].Seat != 0 || events[1].Player != player {
+		t.Errorf("bet placed event has the wrong details: %+v", events[1])
+	}
+
+	// Cards are dealt to the player, then to the dealer; the dealer's second card is face down.
+	for i, seat := range []int{0, 0, SeatDealer, SeatDealer} {
+		e := events[3+i]
+		if e.Seat != seat {
+			t.Errorf("expected card %v to be dealt to seat %v, got %v", i, seat, e.Seat)
+		}
+	}
+	if e := events[6]; !e.Hidden || e.Card != nil {
+		t.Errorf("expected the dealer's hole card to be hidden, got %+v", e)
+	}
+	if e := events[3]; e.Hidden || e.Card == nil || *e.Card != player.Hands[0].Cards[0] || e.HandIndex != 0 {
+		t.Errorf("expected the player's first card to be shown, got %+v", e)
+	}
+	dealer := table.dealerHand()
+	if e := events[8]; e.Hand != player.Hands[0] {
+		t.Errorf("expected it to be the player's turn, got %+v", e)
+	}
+	if e := events[10]; e.Card == nil || *e.Card != dealer.Cards[1] {
+		t.Errorf("expected the dealer's turn to show the hole card, got %+v", e)
+	}
+
+	// The round ends with the dealer standing (or bust), the round ending, and the hand being settled.
+	last := events[len(events)-3:]
+	if last[0].Type != EventDealerStand && last[0].Type != EventBust {
+		t.Errorf("expected the dealer to stand or go bust, got %s", last[0].Type)
+	}
+	if last[1].Type != EventRoundEnded {
+		t.Errorf("expected the round to end, got %s", last[1].Type)
+	}
+	if last[2].Type != EventSettled || last[2].Hand != player.Hands[0] || last[2].Outcome == OutcomePending {
+		t.Errorf("expected the player's hand to be settled, got %+v", last[2])
+	}
+}
+
+// Test that no subscriber can get at the dealer's hole card before the dealer's turn, whatever's dealt.
+func TestEventsHoleCardHidden(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		table, player := eventsTable(t)
+		err := table.SetSource(playdeck.NewSeededSource(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var before []Event
+		turned := false
+		unsubscribe := table.Subscribe(func(e Event) {
+			if e.Type == EventDealerTurn {
+				turned = true
+			}
+			if !turned {
+				before = append(before, e)
+			}
+		})
+		errs := table.Deal()
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if !player.Hands[0].locked {
+			err = player.Hands[0].Stick()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		err = table.EndRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+		unsubscribe()
+
+		hole := table.dealerHand().Cards[1]
+		for _, e := range before {
+			if e.Seat == SeatDealer && (e.Hand != nil || e.Player != nil) {
+				t.Errorf("seed %v: %s event hands out the dealer's hand", seed, e.Type)
+			}
+			if e.Card != nil && *e.Card == hole {
+				t.Errorf("seed %v: %s event shows the hole card before the dealer's turn", seed, e.Type)
+			}
+			if e.Hand != nil && slices.Contains(e.Hand.Cards, hole) {
+				t.Errorf("seed %v: %s event's hand holds the hole card", seed, e.Type)
+			}
+		}
+	}
+}
+
+// Test that hits publish the card, and a bust.
+func TestEventsBust(t *testing.T) {
+	table, player := eventsTable(t)
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	var events []Event
+	unsubscribe := table.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+	defer unsubscribe()
+
+	hand := player.Hands[0]
+	hand.Cards = []playdeck.Card{{Suit: playdeck.SuitHeart, Value: playdeck.ValueKing}, {Suit: playdeck.SuitSpade, Value: playdeck.ValueQueen}}
+	err := hand.EvalScore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.Deck = &playdeck.Deck{Cards: &[]playdeck.Card{{Suit: playdeck.SuitClub, Value: playdeck.ValueFive}}}
+	err = hand.Hit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EventType{EventHit, EventCardDealt, EventBust}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %v events, got %v", len(expected), len(events))
+	}
+	for i, typ := range expected {
+		if events[i].Type != typ {
+			t.Errorf("expected event %v to be %s, got %s", i, typ, events[i].Type)
+		}
+	}
+	if events[1].Card == nil || events[1].Card.Value != playdeck.ValueFive {
+		t.Errorf("expected the five to be dealt, got %+v", events[1])
+	}
+	if events[2].Score != 25 {
+		t.Errorf("expected the bust hand to score 25, got %v", events[2].Score)
+	}
+}
+
+// Test that the channel form delivers events, and is closed when unsubscribed.
+func TestEventsChannel(t *testing.T) {
+	table, player := eventsTable(t)
+	events, unsubscribe := table.Events(64)
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	err := player.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsubscribe()
+	// Unsubscribing twice is harmless.
+	unsubscribe()
+
+	var last uint64
+	count := 0
+	for e := range events {
+		if e.Seq <= last {
+			t.Errorf("events out of order: %v after %v", e.Seq, last)
+		}
+		last = e.Seq
+		count++
+	}
+	if count == 0 {
+		t.Error("expected some events from the channel")
+	}
+	if len(table.events.subscribers) != 0 {
+		t.Errorf("expected no subscribers left, got %v", len(table.events.subscribers))
+	}
+}
+
+// Test that subscribers can call back into the table without deadlocking, and that events stay in order when they do.
+func TestEventsReentrant(t *testing.T) {
+	table, player := eventsTable(t)
+	var events []Event
+	unsubscribe := table.Subscribe(func(e Event) {
+		events = append(events, e)
+		// Stick as soon as the round's dealt.
+		if e.Type == EventRoundDealt {
+			err := player.Hands[0].Stick()
+			if err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	defer unsubscribe()
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if len(events) == 0 || events[len(events)-1].Type != EventStick {
+		t.Fatalf("expected the subscriber's stick to be the last event")
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Seq != events[i-1].Seq+1 {
+			t.Errorf("events out of order: %v after %v", events[i].Seq, events[i-1].Seq)
+		}
+	}
+}
+
+// Test that event types have names.
+func TestEventTypeString(t *testing.T) {
+	if EventCardDealt.String() != "card dealt" {
+		t.Errorf("expected \"card dealt\", got %q", EventCardDealt.String())
+	}
+	if EventType(200).String() != "unknown" {
+		t.Errorf("expected \"unknown\", got %q", EventType(200).String())
+	}
+}
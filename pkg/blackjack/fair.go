@@ -0,0 +1,169 @@
+package blackjack
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+)
+
+// A FairShoe describes a provably fair shoe: the commitment published before it was shuffled, the client seed that was
+// mixed in, and (once the shoe is finished with) the server seed it was shuffled with.
+type FairShoe struct {
+	// Commitment is the hash of ServerSeed, published before the shoe was shuffled.
+	Commitment string
+	// ClientSeed is the seed chosen by the players, mixed into the shuffle.
+	ClientSeed string
+	// ServerSeed is the table's secret seed. It's blank until the shoe is revealed.
+	ServerSeed string
+	// Decks is the number of decks in the shoe.
+	Decks int
+}
+
+// fairShuffle holds a Table's provably fair shuffling state.
+type fairShuffle struct {
+	// clientSeed is mixed into the next shoe.
+	clientSeed string
+	// nextSeed is the server seed the next shoe will be shuffled with.
+	nextSeed string
+	// shoe describes the current shoe. Its ServerSeed stays blank until it's revealed.
+	shoe FairShoe
+	// serverSeed is the current shoe's server seed.
+	serverSeed string
+	// revealed is true once the current shoe's server seed has been revealed.
+	revealed bool
+}
+
+// nextShoe sets the given (unshuffled) deck up to be shuffled provably fairly, and commits to the seed for the shoe after.
+func (f *fairShuffle) nextShoe(deck *playdeck.Deck, decks int) (err error) {
+	next, err := playdeck.NewServerSeed()
+	if err != nil {
+		return err
+	}
+	deck.Source = playdeck.NewFairSource(f.nextSeed, f.clientSeed)
+	f.shoe = FairShoe{
+		Commitment: playdeck.CommitSeed(f.nextSeed),
+		ClientSeed: f.clientSeed,
+		Decks:      decks,
+	}
+	f.serverSeed = f.nextSeed
+	f.revealed = false
+	f.nextSeed = next
+	return
+}
+
+// EnableFairShuffle turns on provably fair shuffling, returning the commitment to the server seed the next shoe will be
+// shuffled with. Players should set a client seed of their own (with SetClientSeed()) once they've seen it.
+// The next Deal() always starts a new shoe. Provably fair shoes ignore the table's Source.
+// It cannot be used while the table is in play.
+func (t *Table) EnableFairShuffle() (commitment string, err error) {
+	t.Lock()
+	defer t.Unlock()
+	err = t.checkPhase("enable fair shuffling", ErrTableInPlay, betweenRounds...)
+	if err != nil {
+		return "", err
+	}
+	if t.fair == nil {
+		seed, err := playdeck.NewServerSeed()
+		if err != nil {
+			return "", err
+		}
+		t.fair = &fairShuffle{nextSeed: seed}
+		// Whatever's in the shoe now wasn't shuffled fairly.
+		t.shoeSize = 0
+	}
+	return playdeck.CommitSeed(t.fair.nextSeed), nil
+}
+
+// FairCommitment returns the commitment to the server seed that the next shoe will be shuffled with.
+func (t *Table) FairCommitment() (commitment string, err error) {
+	t.Lock()
+	defer t.Unlock()
+	if t.fair == nil {
+		return "", ErrFairShuffleOff
+	}
+	return playdeck.CommitSeed(t.fair.nextSeed), nil
+}
+
+// SetClientSeed sets the seed that's mixed into the next provably fair shoe.
+// It cannot be changed while the table is in play.
+func (t *Table) SetClientSeed(seed string) (err error) {
+	t.Lock()
+	defer t.Unlock()
+	if t.fair == nil {
+		return ErrFairShuffleOff
+	}
+	err = t.checkPhase("set the client seed", ErrTableInPlay, betweenRounds...)
+	if err != nil {
+		return err
+	}
+	t.fair.clientSeed = seed
+	return
+}
+
+// FairShoe returns the details of the current provably fair shoe. The server seed is left blank until it's revealed.
+func (t *Table) FairShoe() (shoe FairShoe, err error) {
+	t.Lock()
+	defer t.Unlock()
+	if t.fair == nil {
+		return shoe, ErrFairShuffleOff
+	}
+	return t.fair.shoe, nil
+}
+
+// RevealFairShoe reveals the server seed of the current provably fair shoe, so that it can be checked with VerifyFairShoe().
+// This can only be done between rounds once the cut card has been reached, and the shoe is always replaced at the next Deal().
+func (t *Table) RevealFairShoe() (shoe FairShoe, err error) {
+	t.Lock()
+	defer t.Unlock()
+	if t.fair == nil {
+		return shoe, ErrFairShuffleOff
+	}
+	if t.fair.shoe.Commitment == "" || !t.phase.in(betweenRounds) || !t.cutCardReached() {
+		return shoe, ErrShoeNotFinished
+	}
+	t.fair.revealed = true
+	shoe = t.fair.shoe
+	shoe.ServerSeed = t.fair.serverSeed
+	return shoe, nil
+}
+
+// ShoePositions returns where in the shoe each of this Hand's cards was drawn from, counting from 0 at the last shuffle.
+// Thread-safe.
+func (h *Hand) ShoePositions() (positions []int) {
+	h.RLock()
+	defer h.RUnlock()
+	return append(positions, h.positions...)
+}
+
+// VerifyFairShoe checks a revealed FairShoe against its commitment, and then checks that every card in the given Hands
+// (including the dealer's) is the card that was in that position in the shoe.
+// It returns an error wrapping ErrFairCommitment or ErrFairMismatch if anything doesn't add up.
+func VerifyFairShoe(shoe FairShoe, hands ...*Hand) (err error) {
+	if !playdeck.VerifyCommitment(shoe.ServerSeed, shoe.Commitment) {
+		return ErrFairCommitment
+	}
+	order, err := playdeck.FairOrder(shoe.Decks, false, shoe.ServerSeed, shoe.ClientSeed)
+	if err != nil {
+		return err
+	}
+	for i, h := range hands {
+		h.RLock()
+		cards := h.Cards
+		positions := h.positions
+		commitment := h.shoe
+		h.RUnlock()
+
+		if commitment != shoe.Commitment {
+			return fmt.Errorf("%w: hand %v wasn't dealt from this shoe", ErrFairMismatch, i)
+		}
+		if len(positions) != len(cards) {
+			return fmt.Errorf("%w: hand %v has cards that weren't dealt from the shoe", ErrFairMismatch, i)
+		}
+		for j, c := range cards {
+			p := positions[j]
+			if p < 0 || p >= len(order) || order[p] != c {
+				return fmt.Errorf("%w: hand %v card %v (%s) isn't the card in position %v", ErrFairMismatch, i, j, c.String(), p)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,133 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+func TestFairShuffleOff(t *testing.T) {
+	table := NewTable(1)
+	_, err := table.FairCommitment()
+	if !errors.Is(err, ErrFairShuffleOff) {
+		t.Errorf("didn't get appropriate error fetching a commitment, expected FairShuffleOff got %s", err)
+	}
+	err = table.SetClientSeed("seed")
+	if !errors.Is(err, ErrFairShuffleOff) {
+		t.Errorf("didn't get appropriate error setting a client seed, expected FairShuffleOff got %s", err)
+	}
+	_, err = table.RevealFairShoe()
+	if !errors.Is(err, ErrFairShuffleOff) {
+		t.Errorf("didn't get appropriate error revealing a shoe, expected FairShuffleOff got %s", err)
+	}
+}
+
+// Test a whole provably fair shoe, from commitment to verification.
+func TestFairShoe(t *testing.T) {
+	rules := DefaultRules
+	rules.Penetration = 0.5
+	table, err := NewTableWithRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment, err := table.EnableFairShuffle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.SetClientSeed("player's lucky seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = table.RevealFairShoe()
+	if !errors.Is(err, ErrShoeNotFinished) {
+		t.Errorf("didn't get appropriate error revealing before dealing, expected ShoeNotFinished got %s", err)
+	}
+
+	// Play rounds until the cut card comes out, keeping hold of every hand.
+	var hands []*Hand
+	for !table.CutCardReached() || len(hands) == 0 {
+		errs := table.Deal()
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		shoe, err := table.FairShoe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if shoe.Commitment != commitment || shoe.ServerSeed != "" {
+			t.Fatalf("shoe doesn't match the commitment, or has leaked its seed: %+v", shoe)
+		}
+		_, err = table.RevealFairShoe()
+		if !errors.Is(err, ErrShoeNotFinished) {
+			t.Errorf("didn't get appropriate error revealing mid-round, expected ShoeNotFinished got %s", err)
+		}
+		hand := player.Hands[0]
+		for hand.Hit() == nil {
+			// Take cards until bust, to get through the shoe.
+		}
+		err = table.EndRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealer, err := table.DealerHand()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hands = append(hands, hand, dealer)
+	}
+	next, err := table.FairCommitment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == commitment {
+		t.Errorf("next shoe should be committed to a new seed")
+	}
+
+	shoe, err := table.RevealFairShoe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyFairShoe(shoe, hands...)
+	if err != nil {
+		t.Errorf("fair shoe didn't verify: %s", err)
+	}
+
+	// Tampering with anything should be caught.
+	bad := shoe
+	bad.ServerSeed += "0"
+	err = VerifyFairShoe(bad, hands...)
+	if !errors.Is(err, ErrFairCommitment) {
+		t.Errorf("didn't get appropriate error with the wrong seed, expected FairCommitment got %s", err)
+	}
+	bad = shoe
+	bad.ClientSeed = "somebody else's seed"
+	err = VerifyFairShoe(bad, hands...)
+	if !errors.Is(err, ErrFairMismatch) {
+		t.Errorf("didn't get appropriate error with the wrong client seed, expected FairMismatch got %s", err)
+	}
+	card := hands[0].Cards[0]
+	hands[0].Cards[0] = playdeck.Card{Suit: playdeck.SuitJoker, Value: playdeck.ValueJoker}
+	err = VerifyFairShoe(shoe, hands...)
+	if !errors.Is(err, ErrFairMismatch) {
+		t.Errorf("didn't get appropriate error with a swapped card, expected FairMismatch got %s", err)
+	}
+	hands[0].Cards[0] = card
+
+	// The revealed shoe must not be dealt from again.
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if !table.Shuffled() {
+		t.Errorf("revealed shoe wasn't replaced")
+	}
+	err = VerifyFairShoe(shoe, player.Hands[0])
+	if !errors.Is(err, ErrFairMismatch) {
+		t.Errorf("didn't get appropriate error with a hand from another shoe, expected FairMismatch got %s", err)
+	}
+}
@@ -29,6 +29,30 @@ type Hand struct {
 	locked bool
 	// valid is true if the hand is not bust.
 	valid bool
+	// wager is the amount staked on this hand.
+	wager int
+	// split is true if this hand was made by splitting a pair. A split hand can't be a natural.
+	split bool
+	// splitAces is true if this hand was made by splitting a pair of aces.
+	splitAces bool
+	// doubled is true if this hand has been doubled down on.
+	doubled bool
+	// surrendered is true if this hand has been given up. This is separate to valid, as a surrendered hand isn't bust.
+	surrendered bool
+	// earlySurrender is true if this hand was surrendered before the dealer checked for blackjack, so it stands regardless.
+	earlySurrender bool
+	// insurance is the amount staked on insurance against a dealer blackjack.
+	insurance int
+	// insurancePayout is the amount the insurance bet paid out, including its stake.
+	insurancePayout int
+	// insuranceDecided is true once the Player has accepted or declined insurance on this hand.
+	insuranceDecided bool
+	// evenMoney is true if this hand was a natural that took a guaranteed 1:1 payout instead of risking a push.
+	evenMoney bool
+	// positions records where in the shoe each of Cards was drawn from, counting from 0 at the last shuffle.
+	positions []int
+	// shoe is the commitment of the provably fair shoe this hand was dealt from, if there was one.
+	shoe string
 }
 
 // newHand creates a new Hand object. For internal use.
@@ -48,6 +72,28 @@ func newHand(player *Player) (hand Hand, err error) {
 	}, nil
 }
 
+// ParseHand returns a scored Hand holding the cards in the given string (see playdeck.ParseCards(), e.g. "AS Td").
+// The Hand isn't on any Table, so it can't be played, but it can be scored and compared. Handy for tests and tooling.
+func ParseHand(s string) (hand *Hand, err error) {
+	cards, err := playdeck.ParseCards(s)
+	if err != nil {
+		return nil, err
+	}
+	hand = &Hand{Cards: cards, valid: true}
+	err = hand.EvalScore()
+	if err != nil {
+		return nil, err
+	}
+	return hand, nil
+}
+
+// String returns the cards in this Hand in compact notation (e.g. "AS TD"). Thread-safe.
+func (h *Hand) String() string {
+	h.RLock()
+	defer h.RUnlock()
+	return playdeck.FormatCards(h.Cards)
+}
+
 // Score returns the current state of this Hand. Thread-safe.
 // score represents the maximum score of the hand, taking into account aces being reduced in value to attempt to avoid a bust (possibly in vain)
 // minScore represents the minimum possible score of the hand, taking into account all aces being reduced in value.
@@ -59,32 +105,92 @@ func (h *Hand) Score() (score int, minScore int, locked bool, valid bool) {
 	return h.score, h.minScore, h.locked, h.valid
 }
 
+// Wager returns the amount staked on this Hand. Thread-safe.
+func (h *Hand) Wager() (wager int) {
+	h.RLock()
+	defer h.RUnlock()
+	return h.wager
+}
+
+// Natural returns true if this Hand is a natural blackjack (an ace and a ten-value card as the first two cards). Thread-safe.
+// Hands made by splitting are never naturals, even if they make 21 with two cards.
+func (h *Hand) Natural() bool {
+	h.RLock()
+	defer h.RUnlock()
+	return h.isNatural()
+}
+
+// isNatural is the lock-free implementation of Natural(). The hand must already have been scored.
+func (h *Hand) isNatural() bool {
+	return len(h.Cards) == 2 && h.score == 21 && !h.split
+}
+
 // Hit adds a card to this Hand, if possible. Automatically re-evaluates score, ending play on the hand if Bust occurs.
 func (h *Hand) Hit() (err error) {
+	defer h.Table.dispatchEvents()
+	defer h.Table.nextTurn()
 	err = h.canPlay()
 	if err != nil {
 		return err
 	}
+	rules := h.Table.SplitRules()
+	h.RLock()
+	oneCard := h.oneCardAces(rules)
+	h.RUnlock()
+	if oneCard {
+		return ErrSplitAcesOneCard
+	}
+	h.emitHand(EventHit, ActionHit)
 
-	err = h.addCard()
+	err = h.drawCard()
 	if err != nil {
 		return err
 	}
 	err = h.EvalScore()
-	return err
+	if err != nil {
+		return err
+	}
+	h.emitBust()
+	return
 }
 
 // Stick ends play on this hand. Locks the hand for further play.
 func (h *Hand) Stick() (err error) {
+	defer h.Table.dispatchEvents()
+	defer h.Table.nextTurn()
+	err = h.canPlay()
+	if err != nil {
+		return err
+	}
 	err = h.lockHand()
 	if err != nil {
 		return err
 	}
-	return h.EvalScore()
+	err = h.EvalScore()
+	if err != nil {
+		return err
+	}
+	h.emitHand(EventStick, ActionStick)
+	return
 }
 
-// canPlay returns an error if the hand cannot be played further, otherwise nil.
+// canPlay returns an error if the hand cannot be played further (including if it's not its turn), otherwise nil.
 func (h *Hand) canPlay() (err error) {
+	return h.checkPlay(false)
+}
+
+// checkPlay is the implementation of canPlay(). If beforePeek is set, the action being checked is one that may be made
+// while the dealer is still waiting to check for blackjack (e.g. early surrender).
+func (h *Hand) checkPlay(beforePeek bool) (err error) {
+	err = h.checkHand(beforePeek)
+	if err != nil {
+		return err
+	}
+	return h.checkTurn()
+}
+
+// checkHand checks everything checkPlay() does, other than whose turn it is.
+func (h *Hand) checkHand(beforePeek bool) (err error) {
 	h.RLock()
 	defer h.RUnlock()
 	// Check to ensure we may proceed with the hit
@@ -94,7 +200,8 @@ func (h *Hand) canPlay() (err error) {
 	if !h.valid {
 		return ErrHandBust
 	}
-	if h.locked {
+	// A doubled hand has had its one card, even if it somehow wasn't locked.
+	if h.locked || h.doubled {
 		return ErrHandLocked
 	}
 
@@ -106,25 +213,62 @@ func (h *Hand) canPlay() (err error) {
 	if h.Player.Table == nil {
 		return ErrPlayerNoTable
 	}
+	// The dealer's hand plays itself.
+	if h.Player.dealer {
+		return ErrHandDealer
+	}
 	// SwEng: discussion point, should this be handled elsewhere / not as a direct check?
-	if h.Player.Table.playState != 1 {
-		return ErrTableNotInPlay
+	t := h.Player.Table
+	if beforePeek {
+		return t.checkPhase("play a hand", ErrTableNotInPlay, PhaseInsurance, PhasePlayerTurns)
 	}
-	return
+	notInPlay := ErrTableNotInPlay
+	if t.phase == PhaseInsurance {
+		notInPlay = ErrPeekPending
+	}
+	return t.checkPhase("play a hand", notInPlay, PhasePlayerTurns)
 }
 
-// addCard is an internal function for adding a card to the hand. Prefer Hit().
+// addCard is an internal function for adding a card from the shoe to the hand. Prefer Hit().
+// The table lock must be held by the caller; see drawCard() if it isn't.
 func (h *Hand) addCard() (err error) {
-	h.Lock()
-	defer h.Unlock()
-
-	newCard, err := h.Table.Deck.PullRandomCard()
+	newCard, position, err := h.Table.draw()
 	if err != nil {
 		return err
 	}
+	h.dealCard(newCard, position)
+	return nil
+}
 
+// dealCard adds a card already drawn from the given position in the shoe to the hand, and announces it.
+// The table lock must be held by the caller.
+func (h *Hand) dealCard(newCard playdeck.Card, position int) {
+	h.Lock()
 	h.Cards = append(h.Cards, newCard)
-	return nil
+	h.positions = append(h.positions, position)
+	if h.Table.fair != nil {
+		h.shoe = h.Table.fair.shoe.Commitment
+	}
+	// The dealer's second card, dealt before play starts, is the hole card.
+	hidden := h.Player != nil && h.Player.dealer && len(h.Cards) == 2 && h.Table.phase == PhaseDealing
+	h.Unlock()
+
+	// The hand hasn't been scored with its new card yet, so there's no score to report.
+	e := h.event(EventCardDealt)
+	e.Score = 0
+	if hidden {
+		e.Hidden = true
+	} else {
+		e.Card = &newCard
+	}
+	h.Table.emit(e)
+}
+
+// drawCard is addCard() for Player actions during play, which don't otherwise hold the table lock.
+func (h *Hand) drawCard() (err error) {
+	h.Table.Lock()
+	defer h.Table.Unlock()
+	return h.addCard()
 }
 
 // lockHand is an internal function that locks the hand from being played further. Prefer Stick().
@@ -144,6 +144,44 @@ func TestHandStick(t *testing.T) {
 	}
 }
 
+// Test that sticking is refused in the same ways as any other play: out of phase, before the peek, and on the dealer's hand.
+func TestHandStickChecks(t *testing.T) {
+	table := NewTable(1)
+	table.sRules.SimultaneousPlay = true
+	player := NewPlayer()
+	err := table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hand, err := newHand(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = hand.Stick()
+	var phaseErr *PhaseError
+	if !errors.As(err, &phaseErr) || !errors.Is(err, ErrTableNotInPlay) {
+		t.Errorf("didn't get appropriate error when sticking before the deal, expected a PhaseError wrapping TableNotInPlay got %s", err)
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	err = table.dealerHand().Stick()
+	if !errors.Is(err, ErrHandDealer) {
+		t.Errorf("didn't get appropriate error when sticking the dealer's hand, expected HandDealer got %s", err)
+	}
+
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+	table = insuranceTable(t, six, []playdeck.Card{king, nine})
+	err = table.Players[0].Hands[0].Stick()
+	if !errors.As(err, &phaseErr) || !errors.Is(err, ErrPeekPending) {
+		t.Errorf("didn't get appropriate error when sticking before the peek, expected a PhaseError wrapping PeekPending got %s", err)
+	}
+}
+
 // Test that busting the hand out works as intended.
 func TestHandIntentionalBust(t *testing.T) {
 	table := NewTable(1)
@@ -168,7 +206,7 @@ func TestHandIntentionalBust(t *testing.T) {
 		t.Error(err)
 	}
 	// Force state to play
-	table.playState = 1
+	table.phase = PhasePlayerTurns
 	t.Logf("score: %v, hand: %v", hand.score, hand.Cards)
 	// we should bust within 21 cards, surely
 	for i := 0; i < 21; i++ {
@@ -224,3 +262,33 @@ func TestHandEvalScore(t *testing.T) {
 		t.Errorf("hand with face cards should be worth 20, got %v", hand.score)
 	}
 }
+
+func TestParseHand(t *testing.T) {
+	hand, err := ParseHand("AS 9d Ah")
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, minScore, locked, valid := hand.Score()
+	if score != 21 || minScore != 11 || locked || !valid {
+		t.Errorf("parsed hand scored wrongly, got score %v, min %v, locked %v, valid %v", score, minScore, locked, valid)
+	}
+	if hand.String() != "AS 9D AH" {
+		t.Errorf("hand returned the wrong notation %s", hand.String())
+	}
+	natural, err := ParseHand("🂡🂮")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !natural.Natural() {
+		t.Errorf("ace and king should be a natural")
+	}
+
+	_, err = ParseHand("AS 1S")
+	if !errors.Is(err, playdeck.ErrInvalidNotation) {
+		t.Errorf("didn't get appropriate error with bad notation, expected InvalidNotation got %s", err)
+	}
+	_, err = ParseHand("AS Jkr")
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("didn't get appropriate error with a joker, expected InvalidCard got %s", err)
+	}
+}
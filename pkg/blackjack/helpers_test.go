@@ -0,0 +1,36 @@
+package blackjack
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// seatedTable is a helper that returns a table playing by the given rules and shuffling from the given seed, with the
+// given number of players seated, each with a bankroll of 100 and a bet of 10, ready to be dealt.
+func seatedTable(t *testing.T, rules RuleSet, seed int64, players int) (table *Table) {
+	t.Helper()
+	table, err := NewTableWithRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.SetSource(playdeck.NewSeededSource(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range players {
+		p := NewPlayer()
+		err = table.Join(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.Deposit(100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.PlaceBet(10)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return table
+}
@@ -0,0 +1,386 @@
+package blackjack
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ActionType describes something done at a Table during a round, which a Replayer can do again.
+type ActionType uint8
+
+// Rounds are recorded as Actions of these types.
+const (
+	// ActionDeal is Table.Deal().
+	ActionDeal ActionType = iota
+	// ActionHit is Hand.Hit().
+	ActionHit
+	// ActionStick is Hand.Stick().
+	ActionStick
+	// ActionDouble is Hand.DoubleDown().
+	ActionDouble
+	// ActionSplit is Hand.Split().
+	ActionSplit
+	// ActionSurrender is Hand.Surrender().
+	ActionSurrender
+	// ActionInsure is Hand.Insure(), or Hand.DeclineInsurance() if the Amount is 0.
+	ActionInsure
+	// ActionEvenMoney is Hand.TakeEvenMoney().
+	ActionEvenMoney
+	// ActionDealerPeek is Table.DealerPeek().
+	ActionDealerPeek
+	// ActionEndRound is Table.EndRound().
+	ActionEndRound
+	// ActionSettle is Table.Settle(), or the settlement done by Table.Reset() if the round wasn't settled.
+	ActionSettle
+	// ActionTimeout is a Hand running out of time to decide. The TimeoutAction taken for it follows as its own Action.
+	ActionTimeout
+)
+
+var actionTypeNames = map[ActionType]string{
+	ActionDeal:       "deal",
+	ActionHit:        "hit",
+	ActionStick:      "stick",
+	ActionDouble:     "double",
+	ActionSplit:      "split",
+	ActionSurrender:  "surrender",
+	ActionInsure:     "insure",
+	ActionEvenMoney:  "even money",
+	ActionDealerPeek: "dealer peek",
+	ActionEndRound:   "end round",
+	ActionSettle:     "settle",
+	ActionTimeout:    "timeout",
+}
+
+// String returns a human-readable name for this action type (e.g. "hit"). Unknown values return "unknown".
+func (a ActionType) String() string {
+	name, exists := actionTypeNames[a]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// An Action is something done at a Table during a round, along with everything that happened because of it.
+type Action struct {
+	// Type is what was done.
+	Type ActionType `json:"type"`
+	// Seat and HandIndex are the Player and Hand that did it, as in Event. Actions done by the Table have a Seat of SeatDealer.
+	Seat      int `json:"seat"`
+	HandIndex int `json:"hand"`
+	// Amount is the money involved, as in the Action's Event (e.g. the insurance staked).
+	Amount int `json:"amount,omitempty"`
+	// Events are the Events the Table published because of this Action, in order. Their Player and Hand aren't kept.
+	Events []Event `json:"events"`
+
+	// start is the Table as it was before an ActionDeal was dealt.
+	start *Snapshot
+}
+
+// A RoundLog is the complete record of a single round at a Table: the Table as it was when the round was dealt (including
+// the shoe, in order, and any provably fair seeds), and every Action taken until the next round.
+// RoundLogs can be marshalled (e.g. to JSON) and stored, and played back with a Replayer. Like a Snapshot, a RoundLog
+// holds any secret server seeds, so keep it somewhere safe.
+type RoundLog struct {
+	// Start is the Table just before the round was dealt, with its shoe already shuffled.
+	Start Snapshot `json:"start"`
+	// Actions is everything done during the round, starting with the deal.
+	Actions []Action `json:"actions"`
+}
+
+// A Recorder keeps a RoundLog of every round dealt at a Table while it's recording. See Table.Record().
+type Recorder struct {
+	sync.Mutex
+	rounds []RoundLog
+	stop   func()
+}
+
+// Record starts recording every round dealt at this Table from now on, returning the Recorder keeping the log.
+// Rounds already under way aren't recorded. Call Stop() on the Recorder when done with it.
+func (t *Table) Record() (r *Recorder) {
+	r = &Recorder{}
+	r.stop = t.subscribe(subscriber{fn: r.event, action: r.action})
+	return r
+}
+
+// Stop stops recording. The rounds recorded so far are kept.
+func (r *Recorder) Stop() {
+	r.stop()
+}
+
+// Rounds returns a copy of the log of every round recorded so far, oldest first. The last round may still be under way.
+func (r *Recorder) Rounds() (rounds []RoundLog) {
+	r.Lock()
+	defer r.Unlock()
+	rounds = make([]RoundLog, len(r.rounds))
+	for i, round := range r.rounds {
+		rounds[i] = RoundLog{Start: round.Start, Actions: append([]Action(nil), round.Actions...)}
+	}
+	return rounds
+}
+
+// action starts a new entry in the log for an Action (and a new round for a deal).
+func (r *Recorder) action(a Action) {
+	r.Lock()
+	defer r.Unlock()
+	if a.Type == ActionDeal && a.start != nil {
+		r.rounds = append(r.rounds, RoundLog{Start: *a.start})
+	}
+	// Anything before the first deal is part of a round that's not being recorded.
+	if len(r.rounds) == 0 {
+		return
+	}
+	a.start = nil
+	a.Events = []Event{}
+	round := &r.rounds[len(r.rounds)-1]
+	round.Actions = append(round.Actions, a)
+}
+
+// event adds an Event to the log, under the Action that caused it.
+func (r *Recorder) event(e Event) {
+	// Seating and betting happen between rounds, and are part of the next round's Start.
+	if e.Type == EventPlayerJoined || e.Type == EventBetPlaced {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if len(r.rounds) == 0 {
+		return
+	}
+	round := &r.rounds[len(r.rounds)-1]
+	if len(round.Actions) == 0 {
+		return
+	}
+	e.Player = nil
+	e.Hand = nil
+	a := &round.Actions[len(round.Actions)-1]
+	a.Events = append(a.Events, e)
+}
+
+// A Replayer rebuilds a Table from a RoundLog, one Action at a time, checking as it goes that the Table does exactly what
+// it did when the round was recorded. It can step backwards as well as forwards, e.g. to look into a disputed hand.
+// Rounds where the shoe ran dry and was reshuffled mid-round can't be replayed past the reshuffle, unless they used a
+// provably fair shoe (which never does).
+type Replayer struct {
+	round RoundLog
+	table *Table
+	// step is the number of Actions that have been replayed.
+	step int
+	// snapshots[i] is the Table after i Actions have been replayed.
+	snapshots []Snapshot
+}
+
+// NewReplayer returns a Replayer for the given round, with its Table as it was just before the round was dealt.
+// It returns an error wrapping ErrInvalidSnapshot if the round's Start can't be restored.
+func NewReplayer(round RoundLog) (r *Replayer, err error) {
+	table, err := restore(round.Start, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{round: round, table: table, snapshots: []Snapshot{table.Snapshot()}}, nil
+}
+
+// Table returns the Table as it stands after the Actions replayed so far. Stepping backwards replaces the Table with a
+// new one, so don't hold on to it (or its Players and Hands) across steps.
+func (r *Replayer) Table() *Table {
+	return r.table
+}
+
+// Step returns the number of Actions replayed so far.
+func (r *Replayer) Step() int {
+	return r.step
+}
+
+// Len returns the number of Actions in the round.
+func (r *Replayer) Len() int {
+	return len(r.round.Actions)
+}
+
+// Next returns the next Action to be replayed, and false if there are none left.
+func (r *Replayer) Next() (action Action, ok bool) {
+	if r.step >= len(r.round.Actions) {
+		return action, false
+	}
+	return r.round.Actions[r.step], true
+}
+
+// Forward replays the next Action on the Table.
+// It returns ErrReplayFinished if there are no Actions left, and an error wrapping ErrReplayMismatch if the Table didn't
+// do exactly what was recorded (in which case the Table is put back as it was, and the Replayer doesn't move on).
+func (r *Replayer) Forward() (err error) {
+	a, ok := r.Next()
+	if !ok {
+		return ErrReplayFinished
+	}
+
+	var events []Event
+	unsubscribe := r.table.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+	err = r.apply(a)
+	unsubscribe()
+	if err != nil {
+		err = fmt.Errorf("%w: step %v (%s) failed: %w", ErrReplayMismatch, r.step+1, a.Type, err)
+	} else if e := compareEvents(a.Events, events); e != nil {
+		err = fmt.Errorf("%w: step %v (%s): %w", ErrReplayMismatch, r.step+1, a.Type, e)
+	}
+	if err != nil {
+		table, e := restore(r.snapshots[r.step], true)
+		if e != nil {
+			return e
+		}
+		r.table = table
+		return err
+	}
+
+	r.step++
+	r.snapshots = append(r.snapshots[:r.step], r.table.Snapshot())
+	return
+}
+
+// Back undoes the last Action replayed, returning the Table to how it was before it.
+// It returns ErrReplayStart if no Actions have been replayed.
+func (r *Replayer) Back() (err error) {
+	if r.step == 0 {
+		return ErrReplayStart
+	}
+	table, err := restore(r.snapshots[r.step-1], true)
+	if err != nil {
+		return err
+	}
+	r.table = table
+	r.step--
+	return
+}
+
+// Seek replays (or undoes) Actions until exactly the given number have been replayed.
+// It returns ErrReplayStart or ErrReplayFinished if the step is outside the round.
+func (r *Replayer) Seek(step int) (err error) {
+	if step < 0 {
+		return ErrReplayStart
+	}
+	if step > len(r.round.Actions) {
+		return ErrReplayFinished
+	}
+	// Going backwards can skip straight to the right snapshot.
+	if step < r.step {
+		table, err := restore(r.snapshots[step], true)
+		if err != nil {
+			return err
+		}
+		r.table = table
+		r.step = step
+	}
+	for r.step < step {
+		err = r.Forward()
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// apply does an Action on the Replayer's Table.
+func (r *Replayer) apply(a Action) (err error) {
+	t := r.table
+	switch a.Type {
+	case ActionDeal:
+		errs := t.deal(true)
+		if len(errs) != 0 {
+			return errs[0]
+		}
+		return
+	case ActionDealerPeek:
+		return t.DealerPeek()
+	case ActionEndRound:
+		return t.EndRound()
+	case ActionSettle:
+		_, err = t.Settle()
+		return err
+	}
+
+	h, err := r.hand(a)
+	if err != nil {
+		return err
+	}
+	switch a.Type {
+	case ActionHit:
+		return h.Hit()
+	case ActionStick:
+		return h.Stick()
+	case ActionDouble:
+		return h.DoubleDown()
+	case ActionSplit:
+		_, err = h.Split()
+		return err
+	case ActionSurrender:
+		return h.Surrender()
+	case ActionInsure:
+		if a.Amount == 0 {
+			return h.DeclineInsurance()
+		}
+		return h.Insure(a.Amount)
+	case ActionEvenMoney:
+		return h.TakeEvenMoney()
+	case ActionTimeout:
+		// The clock can't be rerun, so just announce the timeout again.
+		defer t.dispatchEvents()
+		h.emitHand(EventTimeout, ActionTimeout)
+		return
+	}
+	return fmt.Errorf("unknown action type %v", a.Type)
+}
+
+// hand finds the Hand an Action was taken on.
+func (r *Replayer) hand(a Action) (h *Hand, err error) {
+	t := r.table
+	t.Lock()
+	defer t.Unlock()
+	if a.Seat < 0 || a.Seat >= len(t.Players) {
+		return nil, fmt.Errorf("no player in seat %v", a.Seat)
+	}
+	p := t.Players[a.Seat]
+	p.RLock()
+	defer p.RUnlock()
+	if a.HandIndex < 0 || a.HandIndex >= len(p.Hands) {
+		return nil, fmt.Errorf("player in seat %v has no hand %v", a.Seat, a.HandIndex)
+	}
+	return p.Hands[a.HandIndex], nil
+}
+
+// compareEvents returns an error describing the first difference between the Events recorded for an Action and those
+// published when it was replayed. Sequence numbers, Players and Hands aren't compared.
+func compareEvents(recorded []Event, replayed []Event) (err error) {
+	for i := 0; i < len(recorded) || i < len(replayed); i++ {
+		if i >= len(recorded) {
+			return fmt.Errorf("unexpected %s event", replayed[i].Type)
+		}
+		if i >= len(replayed) {
+			return fmt.Errorf("missing %s event", recorded[i].Type)
+		}
+		if !sameEvent(recorded[i], replayed[i]) {
+			return fmt.Errorf("event %v: expected %s, got %s", i, describeEvent(recorded[i]), describeEvent(replayed[i]))
+		}
+	}
+	return
+}
+
+// sameEvent returns true if two Events describe the same thing, regardless of their sequence numbers.
+func sameEvent(a Event, b Event) bool {
+	if a.Type != b.Type || a.Seat != b.Seat || a.HandIndex != b.HandIndex || a.Hidden != b.Hidden || a.Score != b.Score ||
+		a.Amount != b.Amount || a.Outcome != b.Outcome {
+		return false
+	}
+	if a.Card == nil || b.Card == nil {
+		return a.Card == nil && b.Card == nil
+	}
+	return *a.Card == *b.Card
+}
+
+// describeEvent returns a short description of an Event for error messages.
+func describeEvent(e Event) string {
+	card := "-"
+	if e.Card != nil {
+		card = e.Card.Notation()
+	}
+	return fmt.Sprintf("%s (seat %v, hand %v, card %s, score %v, amount %v)", e.Type, e.Seat, e.HandIndex, card, e.Score, e.Amount)
+}
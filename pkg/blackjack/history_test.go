@@ -0,0 +1,264 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"reflect"
+	"testing"
+)
+
+// historyTable is a helper that returns a table with two players, playing a six-deck shoe with insurance, surrender and
+// splits on offer.
+func historyTable(t *testing.T) (table *Table) {
+	t.Helper()
+	rules := VegasStripRules
+	rules.Penetration = 0.75
+	table = seatedTable(t, rules, 1234, 2)
+	// Enough to see out plenty of rounds.
+	for _, p := range table.Players {
+		err := p.Deposit(9900)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return table
+}
+
+// historyRound is a helper that deals and plays out a round with a simple strategy, without settling it.
+func historyRound(t *testing.T, table *Table) {
+	t.Helper()
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if table.InsuranceOpen() {
+		for _, p := range table.Players {
+			err := p.Hands[0].DeclineInsurance()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if table.PeekPending() {
+		err := table.DealerPeek()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, p := range table.Players {
+		for i := 0; i < len(p.Hands); i++ {
+			h := p.Hands[i]
+			// These are allowed to fail; only what actually happens is recorded.
+			_, _ = h.Split()
+			score, _, _, _ := h.Score()
+			if score == 11 {
+				_ = h.DoubleDown()
+			}
+			if score == 16 {
+				_ = h.Surrender()
+			}
+			for {
+				score, _, locked, _ := h.Score()
+				if locked {
+					break
+				}
+				var err error
+				if score < 17 {
+					err = h.Hit()
+				} else {
+					err = h.Stick()
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+	err := table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that recorded rounds survive being marshalled, and replay to exactly the same Table.
+func TestHistoryReplay(t *testing.T) {
+	table := historyTable(t)
+	recorder := table.Record()
+	defer recorder.Stop()
+
+	var ends []Snapshot
+	for i := 0; i < 20; i++ {
+		historyRound(t, table)
+		// Leave every fifth round for the next deal to settle.
+		if i%5 == 4 {
+			ends = append(ends, Snapshot{})
+			continue
+		}
+		_, err := table.Settle()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ends = append(ends, table.Snapshot())
+	}
+
+	data, err := json.Marshal(recorder.Rounds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rounds []RoundLog
+	err = json.Unmarshal(data, &rounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rounds) != 20 {
+		t.Fatalf("expected 20 rounds to be recorded, got %v", len(rounds))
+	}
+
+	seen := map[ActionType]bool{}
+	for i, round := range rounds {
+		for _, a := range round.Actions {
+			seen[a.Type] = true
+		}
+		if round.Actions[0].Type != ActionDeal {
+			t.Errorf("round %v: expected the first action to be a deal, got %s", i, round.Actions[0].Type)
+		}
+		// The last round hasn't been settled yet.
+		last := round.Actions[len(round.Actions)-1]
+		if i < len(rounds)-1 && last.Type != ActionSettle {
+			t.Errorf("round %v: expected the last action to be a settle, got %s", i, last.Type)
+		}
+
+		r, err := NewReplayer(round)
+		if err != nil {
+			t.Fatalf("round %v: %s", i, err)
+		}
+		err = r.Seek(r.Len())
+		if err != nil {
+			t.Fatalf("round %v: %s", i, err)
+		}
+		if i%5 == 4 {
+			continue
+		}
+		if snap := r.Table().Snapshot(); !reflect.DeepEqual(snap, ends[i]) {
+			t.Errorf("round %v: replayed table doesn't match the original\nexpected %+v\ngot      %+v", i, ends[i], snap)
+		}
+	}
+	// With this seed, everything but even money comes up at least once.
+	for _, a := range []ActionType{ActionHit, ActionStick, ActionDouble, ActionSplit, ActionSurrender, ActionInsure, ActionDealerPeek} {
+		if !seen[a] {
+			t.Errorf("expected a %s to have been replayed", a)
+		}
+	}
+}
+
+// Test stepping backwards and forwards through a round.
+func TestHistoryReplayerSteps(t *testing.T) {
+	table := historyTable(t)
+	recorder := table.Record()
+	historyRound(t, table)
+	_, err := table.Settle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Stop()
+	round := recorder.Rounds()[0]
+
+	r, err := NewReplayer(round)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Table().CurrentPhase() != PhaseBetting {
+		t.Errorf("expected the replay to start before the deal")
+	}
+	err = r.Back()
+	if !errors.Is(err, ErrReplayStart) {
+		t.Errorf("didn't get appropriate error when stepping back from the start, expected ErrReplayStart got %s", err)
+	}
+
+	var snaps []Snapshot
+	for r.Step() < r.Len() {
+		err = r.Forward()
+		if err != nil {
+			t.Fatal(err)
+		}
+		snaps = append(snaps, r.Table().Snapshot())
+	}
+	err = r.Forward()
+	if !errors.Is(err, ErrReplayFinished) {
+		t.Errorf("didn't get appropriate error when stepping past the end, expected ErrReplayFinished got %s", err)
+	}
+
+	// Step all the way back again, checking the table at every step.
+	for r.Step() > 1 {
+		err = r.Back()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snap := r.Table().Snapshot(); !reflect.DeepEqual(snap, snaps[r.Step()-1]) {
+			t.Errorf("table after stepping back to step %v doesn't match", r.Step())
+		}
+	}
+	err = r.Seek(r.Len())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap := r.Table().Snapshot(); !reflect.DeepEqual(snap, snaps[len(snaps)-1]) {
+		t.Errorf("table after seeking to the end doesn't match")
+	}
+	err = r.Seek(r.Len() + 1)
+	if !errors.Is(err, ErrReplayFinished) {
+		t.Errorf("didn't get appropriate error when seeking past the end, expected ErrReplayFinished got %s", err)
+	}
+}
+
+// Test that a log that's been tampered with doesn't replay.
+func TestHistoryReplayMismatch(t *testing.T) {
+	table := historyTable(t)
+	recorder := table.Record()
+	defer recorder.Stop()
+	historyRound(t, table)
+	round := recorder.Rounds()[0]
+
+	// Swap the first card dealt for one that wasn't.
+	deal := round.Actions[0]
+	deal.Events = append([]Event(nil), deal.Events...)
+	for i, e := range deal.Events {
+		if e.Type == EventCardDealt && e.Card != nil {
+			card := *e.Card
+			card.Value = card.Value%playdeck.ValueKing + 1
+			deal.Events[i].Card = &card
+			break
+		}
+	}
+	round.Actions = append([]Action{deal}, round.Actions[1:]...)
+
+	r, err := NewReplayer(round)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Forward()
+	if !errors.Is(err, ErrReplayMismatch) {
+		t.Errorf("didn't get appropriate error when replaying a tampered log, expected ErrReplayMismatch got %s", err)
+	}
+	if r.Step() != 0 || r.Table().CurrentPhase() != PhaseBetting {
+		t.Errorf("expected a failed step to leave the replay where it was")
+	}
+}
+
+// Test that rounds already under way when recording starts aren't recorded.
+func TestHistoryRecordMidRound(t *testing.T) {
+	table := historyTable(t)
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	recorder := table.Record()
+	defer recorder.Stop()
+	for _, p := range table.Players {
+		_ = p.Hands[0].Stick()
+	}
+	if rounds := recorder.Rounds(); len(rounds) != 0 {
+		t.Errorf("expected no rounds to be recorded, got %v", len(rounds))
+	}
+}
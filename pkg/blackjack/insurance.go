@@ -0,0 +1,150 @@
+package blackjack
+
+// SetInsurance sets whether insurance (and even money) is offered when the dealer shows an ace.
+// Offering insurance means the dealer will always check their hole card once the insurance window closes.
+// This is a shortcut for changing a single rule with SetRules(), and cannot be changed while the table is in play.
+func (t *Table) SetInsurance(offered bool) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.Insurance = offered
+	})
+}
+
+// InsuranceOffered returns whether insurance is offered when the dealer shows an ace.
+func (t *Table) InsuranceOffered() bool {
+	return t.Rules().Insurance
+}
+
+// InsuranceOpen returns true while Players may take insurance (or even money) on their hands.
+// The window is closed by Table.DealerPeek(), and until then Players can't Hit or Stick.
+func (t *Table) InsuranceOpen() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.insuranceOpen
+}
+
+// Insure places an insurance side bet of up to half of this Hand's wager, taken from the Player's bankroll.
+// Insurance pays 2:1 if the dealer turns out to have blackjack when they check their hole card, and is lost otherwise.
+// It can only be taken while the table's insurance window is open, and only once per hand.
+func (h *Hand) Insure(amount int) (err error) {
+	return h.decideInsurance(amount, false)
+}
+
+// DeclineInsurance records that this Hand doesn't want insurance.
+// It can only be done while the table's insurance window is open, and only once per hand.
+func (h *Hand) DeclineInsurance() (err error) {
+	return h.decideInsurance(0, false)
+}
+
+// TakeEvenMoney settles a natural for 1:1 straight away, rather than risking a push against a dealer blackjack.
+// It can only be taken on a natural while the table's insurance window is open, and locks the hand.
+func (h *Hand) TakeEvenMoney() (err error) {
+	return h.decideInsurance(0, true)
+}
+
+// Insurance returns the amount of insurance staked on this Hand. Thread-safe.
+func (h *Hand) Insurance() (insurance int) {
+	h.RLock()
+	defer h.RUnlock()
+	return h.insurance
+}
+
+// EvenMoney returns true if this Hand took even money on a natural. Thread-safe.
+func (h *Hand) EvenMoney() bool {
+	h.RLock()
+	defer h.RUnlock()
+	return h.evenMoney
+}
+
+// decideInsurance is the implementation behind Insure(), DeclineInsurance() and TakeEvenMoney().
+// An amount of 0 declines insurance, unless evenMoney is set.
+func (h *Hand) decideInsurance(amount int, evenMoney bool) (err error) {
+	if amount < 0 {
+		return ErrInvalidAmount
+	}
+	t := h.Table
+	p := h.Player
+	if t == nil {
+		return ErrHandInvalid
+	}
+	if p == nil {
+		return ErrHandNoPlayer
+	}
+
+	defer t.dispatchEvents()
+	// Take locks in table, player, hand order.
+	t.Lock()
+	defer t.Unlock()
+	err = t.checkPhase("decide on insurance", ErrInsuranceClosed, PhaseInsurance)
+	if err != nil {
+		return err
+	}
+	if !t.insuranceOpen {
+		return ErrInsuranceClosed
+	}
+	p.Lock()
+	defer p.Unlock()
+	if p.dealer {
+		return ErrHandDealer
+	}
+	err = h.takeInsurance(amount, evenMoney)
+	if err != nil {
+		return err
+	}
+
+	if evenMoney {
+		t.emitAction(ActionEvenMoney, h.lockedEvent(EventEvenMoney))
+		return
+	}
+	e := h.lockedEvent(EventInsurance)
+	e.Amount = amount
+	t.emitAction(ActionInsure, e)
+	return
+}
+
+// takeInsurance records the insurance decision on this Hand, taking any insurance staked from the Player's bankroll.
+// The table and player locks must be held by the caller.
+func (h *Hand) takeInsurance(amount int, evenMoney bool) (err error) {
+	p := h.Player
+	h.Lock()
+	defer h.Unlock()
+
+	if h.insuranceDecided {
+		return ErrInsuranceDecided
+	}
+	if evenMoney {
+		if !h.isNatural() {
+			return ErrHandNotNatural
+		}
+		h.evenMoney = true
+		h.locked = true
+	} else if amount > 0 {
+		if amount > h.wager/2 {
+			return ErrInvalidAmount
+		}
+		if p.bankroll < amount {
+			return ErrInsufficientFunds
+		}
+		p.bankroll -= amount
+		h.insurance = amount
+	}
+	h.insuranceDecided = true
+	return
+}
+
+// settleInsurance pays out (or collects) every insurance bet once the dealer has checked their hole card.
+// The table lock must be held by the caller.
+func (t *Table) settleInsurance(dealerNatural bool) {
+	for _, p := range t.Players {
+		p.Lock()
+		for _, h := range p.Hands {
+			h.Lock()
+			if h.insurance > 0 && dealerNatural {
+				// Insurance pays 2:1, plus the stake back.
+				h.insurancePayout = h.insurance * 3
+				p.bankroll += h.insurancePayout
+			}
+			h.Unlock()
+		}
+		p.Unlock()
+	}
+}
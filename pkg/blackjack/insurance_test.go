@@ -0,0 +1,222 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// insuranceTable is a helper that returns a round where the dealer shows an ace with the given hole card,
+// and insurance is on offer. Each player has a bankroll of 100 and a bet of 10.
+func insuranceTable(t *testing.T, hole playdeck.Card, players ...[]playdeck.Card) (table *Table) {
+	t.Helper()
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	rules := DefaultRules
+	rules.Decks = 1
+	rules.Insurance = true
+	table = seatedTable(t, rules, 1, len(players))
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	// Throw away anything the real deal did, and rig it.
+	table.phase = PhaseDealing
+	table.insuranceOpen = false
+	for i, cards := range players {
+		h := table.Players[i].Hands[0]
+		h.Cards = cards
+		h.locked = false
+		err := h.EvalScore()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := table.dealerHand()
+	d.Cards = []playdeck.Card{ace, hole}
+	err := d.EvalScore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.startPeek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestInsuranceSetting(t *testing.T) {
+	table := NewTable(1)
+	if table.InsuranceOffered() {
+		t.Errorf("new table should not offer insurance")
+	}
+	err := table.SetInsurance(true)
+	if err != nil {
+		t.Error(err)
+	}
+	if !table.InsuranceOffered() {
+		t.Errorf("insurance setting not set")
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	err = table.SetInsurance(false)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing insurance mid-game, expected TableInPlay got %s", err)
+	}
+}
+
+// Test that insurance pays 2:1 when the dealer has blackjack, and blocks play until the dealer peeks.
+func TestInsuranceDealerBlackjack(t *testing.T) {
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+
+	table := insuranceTable(t, king, []playdeck.Card{king, nine}, []playdeck.Card{king, nine})
+	insured := table.Players[0].Hands[0]
+	declined := table.Players[1].Hands[0]
+	if !table.InsuranceOpen() || !table.PeekPending() {
+		t.Fatalf("insurance window should be open when the dealer shows an ace")
+	}
+	err := insured.Hit()
+	if !errors.Is(err, ErrPeekPending) {
+		t.Errorf("didn't get appropriate error when hitting during insurance, expected PeekPending got %s", err)
+	}
+	err = insured.Stick()
+	if !errors.Is(err, ErrPeekPending) {
+		t.Errorf("didn't get appropriate error when sticking during insurance, expected PeekPending got %s", err)
+	}
+
+	err = insured.Insure(6)
+	if !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("didn't get appropriate error when insuring over half the wager, expected InvalidAmount got %s", err)
+	}
+	err = insured.Insure(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if insured.Insurance() != 5 || table.Players[0].Bankroll() != 85 {
+		t.Errorf("insurance not staked, expected 5 staked and bankroll 85 got %v and %v", insured.Insurance(), table.Players[0].Bankroll())
+	}
+	err = insured.Insure(5)
+	if !errors.Is(err, ErrInsuranceDecided) {
+		t.Errorf("didn't get appropriate error when insuring twice, expected InsuranceDecided got %s", err)
+	}
+	err = declined.TakeEvenMoney()
+	if !errors.Is(err, ErrHandNotNatural) {
+		t.Errorf("didn't get appropriate error when taking even money without a natural, expected HandNotNatural got %s", err)
+	}
+	err = declined.DeclineInsurance()
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = table.DealerPeek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.InsuranceOpen() {
+		t.Errorf("insurance window should close when the dealer peeks")
+	}
+	err = declined.Insure(5)
+	if !errors.Is(err, ErrInsuranceClosed) {
+		t.Errorf("didn't get appropriate error when insuring after the peek, expected InsuranceClosed got %s", err)
+	}
+	// Insurance is paid as soon as the dealer peeks.
+	if table.Players[0].Bankroll() != 100 {
+		t.Errorf("insurance not paid 2:1, expected bankroll of 100 got %v", table.Players[0].Bankroll())
+	}
+
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := table.Settle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Outcome != OutcomeLose || results[0].InsurancePayout != 15 || results[0].Net != 0 {
+		t.Errorf("insured hand should break even against a dealer blackjack, got %+v", results[0])
+	}
+	if results[1].Net != -10 || table.Players[1].Bankroll() != 90 {
+		t.Errorf("uninsured hand should lose its wager, got %+v", results[1])
+	}
+}
+
+// Test that insurance is lost when the dealer doesn't have blackjack, and play carries on.
+func TestInsuranceNoBlackjack(t *testing.T) {
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+
+	table := insuranceTable(t, six, []playdeck.Card{king, nine})
+	hand := table.Players[0].Hands[0]
+	err := hand.Insure(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.DealerPeek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Players[0].Bankroll() != 86 {
+		t.Errorf("insurance should be lost, expected bankroll of 86 got %v", table.Players[0].Bankroll())
+	}
+	err = hand.Stick()
+	if err != nil {
+		t.Errorf("couldn't stick after the insurance window closed: %s", err)
+	}
+}
+
+// Test that even money pays 1:1 on a natural, even against a dealer blackjack.
+func TestEvenMoney(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitHeart, Value: playdeck.ValueAce}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+
+	table := insuranceTable(t, king, []playdeck.Card{ace, king})
+	hand := table.Players[0].Hands[0]
+	err := hand.TakeEvenMoney()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hand.EvenMoney() {
+		t.Errorf("hand not marked as taking even money")
+	}
+	err = table.DealerPeek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := table.Settle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Outcome != OutcomeEvenMoney || results[0].Net != 10 {
+		t.Errorf("even money should pay 1:1, got %+v", results[0])
+	}
+}
+
+// Test that no insurance window is opened when the dealer doesn't show an ace.
+func TestInsuranceNotOffered(t *testing.T) {
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+
+	table := insuranceTable(t, king, []playdeck.Card{king, nine})
+	d := table.dealerHand()
+	d.Cards = []playdeck.Card{king, nine}
+	table.phase = PhaseDealing
+	table.insuranceOpen = false
+	err := table.startPeek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.InsuranceOpen() {
+		t.Errorf("insurance should only be offered against an ace")
+	}
+	err = table.Players[0].Hands[0].Insure(5)
+	if !errors.Is(err, ErrInsuranceClosed) {
+		t.Errorf("didn't get appropriate error when insuring against a king, expected InsuranceClosed got %s", err)
+	}
+}
@@ -0,0 +1,104 @@
+package blackjack
+
+// Outcome represents the result of a Player's Hand once it has been compared against the dealer's.
+type Outcome uint8
+
+// A Hand's Outcome is one of these tokens.
+const (
+	// OutcomePending means the hand has not yet been compared against the dealer (i.e. the round hasn't ended).
+	OutcomePending Outcome = iota
+	// OutcomeLose means the hand is bust, or the dealer beat it.
+	OutcomeLose
+	// OutcomePush means the hand tied with the dealer.
+	OutcomePush
+	// OutcomeWin means the hand beat the dealer, or the dealer went bust.
+	OutcomeWin
+	// OutcomeBlackjack means the hand was a natural (an ace and a ten-value card) that the dealer didn't match.
+	OutcomeBlackjack
+	// OutcomeSurrender means the hand was given up, for half of its wager back.
+	OutcomeSurrender
+	// OutcomeEvenMoney means the hand was a natural that took a 1:1 payout against a dealer ace.
+	OutcomeEvenMoney
+)
+
+var outcomeNames = map[Outcome]string{
+	OutcomePending:   "pending",
+	OutcomeLose:      "lose",
+	OutcomePush:      "push",
+	OutcomeWin:       "win",
+	OutcomeBlackjack: "blackjack",
+	OutcomeSurrender: "surrender",
+	OutcomeEvenMoney: "even money",
+}
+
+// String returns a human-readable name for this outcome (e.g. "push").
+// Unknown outcomes return "unknown".
+func (o Outcome) String() string {
+	name, exists := outcomeNames[o]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// Outcome compares this Hand against the dealer's, returning whether it won, lost, or pushed.
+// It can only be called once the round has ended (see Table.EndRound()), otherwise ErrRoundNotEnded is returned.
+func (h *Hand) Outcome() (outcome Outcome, err error) {
+	if h.Table == nil {
+		return OutcomePending, ErrHandInvalid
+	}
+	dealer, err := h.Table.DealerHand()
+	if err != nil {
+		return OutcomePending, err
+	}
+	if dealer == h {
+		return OutcomePending, ErrHandDealer
+	}
+	return compareHands(h, dealer), nil
+}
+
+// compareHands works out the Outcome of the player's hand against the dealer's.
+// Both hands must already have been scored.
+func compareHands(player *Hand, dealer *Hand) (outcome Outcome) {
+	player.RLock()
+	defer player.RUnlock()
+	dealer.RLock()
+	defer dealer.RUnlock()
+
+	// A bust player always loses, even if the dealer busts too. This is the house's edge!
+	if !player.valid {
+		return OutcomeLose
+	}
+
+	playerNatural := player.isNatural()
+	dealerNatural := dealer.isNatural()
+
+	// Even money is paid whatever the dealer has.
+	if player.evenMoney {
+		return OutcomeEvenMoney
+	}
+
+	// A late surrender only stands if the dealer didn't have blackjack.
+	if player.surrendered {
+		if dealerNatural && !player.earlySurrender {
+			return OutcomeLose
+		}
+		return OutcomeSurrender
+	}
+
+	switch {
+	case playerNatural && dealerNatural:
+		return OutcomePush
+	case playerNatural:
+		return OutcomeBlackjack
+	case dealerNatural:
+		return OutcomeLose
+	case !dealer.valid:
+		return OutcomeWin
+	case player.score > dealer.score:
+		return OutcomeWin
+	case player.score < dealer.score:
+		return OutcomeLose
+	}
+	return OutcomePush
+}
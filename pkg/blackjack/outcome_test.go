@@ -0,0 +1,102 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// testHand is a helper that returns a scored Hand holding the given cards.
+func testHand(t *testing.T, cards ...playdeck.Card) (hand *Hand) {
+	t.Helper()
+	hand = &Hand{Cards: cards, valid: true}
+	err := hand.EvalScore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hand
+}
+
+// Test that hands are compared against the dealer correctly.
+func TestCompareHands(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+	five := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueFive}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+
+	cases := []struct {
+		name     string
+		player   []playdeck.Card
+		dealer   []playdeck.Card
+		expected Outcome
+	}{
+		{"higher score wins", []playdeck.Card{king, king}, []playdeck.Card{king, nine}, OutcomeWin},
+		{"lower score loses", []playdeck.Card{king, nine}, []playdeck.Card{king, king}, OutcomeLose},
+		{"equal score pushes", []playdeck.Card{king, nine}, []playdeck.Card{nine, king}, OutcomePush},
+		{"player bust loses", []playdeck.Card{king, king, five}, []playdeck.Card{king, six, king}, OutcomeLose},
+		{"dealer bust wins", []playdeck.Card{king, five}, []playdeck.Card{king, six, king}, OutcomeWin},
+		{"natural wins", []playdeck.Card{ace, king}, []playdeck.Card{king, king}, OutcomeBlackjack},
+		{"natural beats three card 21", []playdeck.Card{ace, king}, []playdeck.Card{king, six, five}, OutcomeBlackjack},
+		{"natural against natural pushes", []playdeck.Card{ace, king}, []playdeck.Card{king, ace}, OutcomePush},
+		{"dealer natural beats three card 21", []playdeck.Card{king, six, five}, []playdeck.Card{ace, king}, OutcomeLose},
+	}
+	for _, c := range cases {
+		outcome := compareHands(testHand(t, c.player...), testHand(t, c.dealer...))
+		if outcome != c.expected {
+			t.Errorf("%s: expected %s got %s", c.name, c.expected, outcome)
+		}
+	}
+}
+
+// Test that outcomes are only available once the round has ended.
+func TestHandOutcome(t *testing.T) {
+	table := NewTable(1)
+	player := NewPlayer()
+	err := table.Join(player)
+	if err != nil {
+		t.Error(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	hand := player.Hands[0]
+	_, err = hand.Outcome()
+	if !errors.Is(err, ErrRoundNotEnded) {
+		t.Errorf("didn't get appropriate error when fetching outcome mid-game, expected RoundNotEnded got %s", err)
+	}
+	err = hand.Stick()
+	if err != nil {
+		t.Error(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Error(err)
+	}
+	outcome, err := hand.Outcome()
+	if err != nil {
+		t.Error(err)
+	}
+	if outcome == OutcomePending {
+		t.Errorf("hand outcome still pending after round end")
+	}
+	t.Logf("hand finished with outcome %s", outcome)
+
+	dealer, err := table.DealerHand()
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = dealer.Outcome()
+	if !errors.Is(err, ErrHandDealer) {
+		t.Errorf("didn't get appropriate error when fetching dealer outcome, expected HandDealer got %s", err)
+	}
+
+	_, err = new(Hand).Outcome()
+	if !errors.Is(err, ErrHandInvalid) {
+		t.Errorf("didn't get appropriate error with a blank hand, expected HandInvalid got %s", err)
+	}
+	if Outcome(42).String() != "unknown" {
+		t.Errorf("bad outcome returned a name of %s", Outcome(42).String())
+	}
+}
@@ -0,0 +1,149 @@
+package blackjack
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Phase is the stage a Table's round has reached. Every action at a Table can only be taken in certain Phases, and
+// Tables only ever move between Phases as laid out in the transition table (see CanMoveTo()).
+type Phase uint8
+
+// A round moves through these Phases, in this order.
+const (
+	// PhaseBetting is before a round has been dealt. Players may join the table and place their bets.
+	PhaseBetting Phase = iota
+	// PhaseDealing is while the cards are being dealt.
+	PhaseDealing
+	// PhaseInsurance is while the dealer is waiting to check their hole card for blackjack, so that insurance, even money
+	// and early surrender can be decided first. It ends with Table.DealerPeek().
+	PhaseInsurance
+	// PhasePlayerTurns is while Players are playing their hands. It ends with Table.EndRound().
+	PhasePlayerTurns
+	// PhaseDealerTurn is while the dealer is playing their hand.
+	PhaseDealerTurn
+	// PhaseSettlement is once the round is over, until its wagers are paid out with Table.Settle().
+	PhaseSettlement
+	// PhaseIdle is once the round has been settled, until the next one is dealt. Players may place their next bets.
+	PhaseIdle
+)
+
+var phaseNames = map[Phase]string{
+	PhaseBetting:     "betting",
+	PhaseDealing:     "dealing",
+	PhaseInsurance:   "insurance",
+	PhasePlayerTurns: "player turns",
+	PhaseDealerTurn:  "dealer turn",
+	PhaseSettlement:  "settlement",
+	PhaseIdle:        "idle",
+}
+
+// String returns a human-readable name for this phase (e.g. "player turns"). Unknown values return "unknown".
+func (p Phase) String() string {
+	name, exists := phaseNames[p]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// phaseTransitions lists the Phases a Table may move to from each Phase.
+var phaseTransitions = map[Phase][]Phase{
+	PhaseBetting:     {PhaseBetting, PhaseDealing},
+	PhaseDealing:     {PhaseInsurance, PhasePlayerTurns},
+	PhaseInsurance:   {PhasePlayerTurns},
+	PhasePlayerTurns: {PhaseDealerTurn},
+	// A dealer's turn that goes wrong (e.g. the shoe runs out) is abandoned, leaving the round to be ended again.
+	PhaseDealerTurn: {PhaseSettlement, PhasePlayerTurns},
+	PhaseSettlement: {PhaseIdle},
+	PhaseIdle:       {PhaseBetting},
+}
+
+// betweenRounds are the Phases in which no round is in play.
+var betweenRounds = []Phase{PhaseBetting, PhaseSettlement, PhaseIdle}
+
+// CanMoveTo returns true if a Table in this Phase may move straight to the next one.
+func (p Phase) CanMoveTo(next Phase) bool {
+	return next.in(phaseTransitions[p])
+}
+
+// in returns true if this Phase is one of the given Phases.
+func (p Phase) in(phases []Phase) bool {
+	for _, phase := range phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// A PhaseError is returned when something is tried at a Table in the wrong Phase. It matches ErrWrongPhase with
+// errors.Is(), as well as a more specific error (e.g. ErrTableInPlay) saying what's wrong.
+type PhaseError struct {
+	// Action is what was tried, e.g. "deal".
+	Action string
+	// Current is the Phase the Table was in.
+	Current Phase
+	// Required is the Phases it could have been done in.
+	Required []Phase
+	// Err is the more specific error.
+	Err error
+}
+
+// Error describes what was tried, and in which Phases it could have been done, e.g.
+// "table is in play: cannot deal in the player turns phase (only in betting, settlement or idle)".
+func (e *PhaseError) Error() string {
+	names := make([]string, len(e.Required))
+	for i, p := range e.Required {
+		names[i] = p.String()
+	}
+	required := strings.Join(names, ", ")
+	if len(names) > 1 {
+		required = strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+	}
+	return fmt.Sprintf("%s: cannot %s in the %s phase (only in %s)", e.Err, e.Action, e.Current, required)
+}
+
+// Unwrap returns ErrWrongPhase and the more specific error.
+func (e *PhaseError) Unwrap() []error {
+	return []error{ErrWrongPhase, e.Err}
+}
+
+// CurrentPhase returns the Phase this Table's round has reached.
+func (t *Table) CurrentPhase() Phase {
+	t.Lock()
+	defer t.Unlock()
+	return t.phase
+}
+
+// checkPhase returns a PhaseError wrapping err if the table isn't in one of the given Phases.
+// The table lock must be held by the caller.
+func (t *Table) checkPhase(action string, err error, phases ...Phase) error {
+	if t.phase.in(phases) {
+		return nil
+	}
+	return &PhaseError{Action: action, Current: t.phase, Required: phases, Err: err}
+}
+
+// setPhase moves the table to the given Phase, returning a PhaseError wrapping ErrPhaseTransition if the transition
+// table doesn't allow it.
+// The table lock must be held by the caller.
+func (t *Table) setPhase(next Phase) (err error) {
+	if !t.phase.CanMoveTo(next) {
+		return &PhaseError{Action: "move to the " + next.String() + " phase", Current: t.phase,
+			Required: phasesBefore(next), Err: ErrPhaseTransition}
+	}
+	t.phase = next
+	t.updateTurn()
+	return
+}
+
+// phasesBefore returns the Phases that may move to the given Phase.
+func phasesBefore(next Phase) (phases []Phase) {
+	for p := PhaseBetting; p <= PhaseIdle; p++ {
+		if p.CanMoveTo(next) {
+			phases = append(phases, p)
+		}
+	}
+	return phases
+}
@@ -0,0 +1,177 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"reflect"
+	"testing"
+)
+
+// Test that a round moves through the phases we'd expect.
+func TestPhaseRound(t *testing.T) {
+	table := NewTable(1)
+	if table.CurrentPhase() != PhaseBetting {
+		t.Errorf("expected a new table to be betting, got %s", table.CurrentPhase())
+	}
+	player := NewPlayer()
+	err := table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if table.CurrentPhase() != PhasePlayerTurns {
+		t.Errorf("expected player turns after the deal, got %s", table.CurrentPhase())
+	}
+	err = player.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.CurrentPhase() != PhaseSettlement {
+		t.Errorf("expected settlement after the round ended, got %s", table.CurrentPhase())
+	}
+	_, err = table.Settle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.CurrentPhase() != PhaseIdle {
+		t.Errorf("expected idle after settling, got %s", table.CurrentPhase())
+	}
+	err = table.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.CurrentPhase() != PhaseBetting {
+		t.Errorf("expected betting after a reset, got %s", table.CurrentPhase())
+	}
+}
+
+// Test that the insurance phase comes between the deal and the players' turns.
+func TestPhaseInsurance(t *testing.T) {
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+
+	// The dealer shows an ace, with a six in the hole.
+	table := insuranceTable(t, six, []playdeck.Card{king, six})
+	if table.CurrentPhase() != PhaseInsurance {
+		t.Fatalf("expected insurance after dealing an ace, got %s", table.CurrentPhase())
+	}
+	err := table.DealerPeek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.CurrentPhase() != PhasePlayerTurns {
+		t.Errorf("expected player turns after the peek, got %s", table.CurrentPhase())
+	}
+}
+
+// Test that actions in the wrong phase are rejected with a PhaseError that still matches the older errors.
+func TestPhaseErrors(t *testing.T) {
+	table := NewTable(1)
+	player := NewPlayer()
+	err := table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = table.EndRound()
+	var phaseErr *PhaseError
+	if !errors.As(err, &phaseErr) {
+		t.Fatalf("expected a PhaseError when ending a round that hasn't been dealt, got %s", err)
+	}
+	if !errors.Is(err, ErrWrongPhase) || !errors.Is(err, ErrTableNotInPlay) {
+		t.Errorf("didn't get appropriate error when ending a round that hasn't been dealt, expected WrongPhase and TableNotInPlay got %s", err)
+	}
+	if phaseErr.Current != PhaseBetting || !reflect.DeepEqual(phaseErr.Required, []Phase{PhaseInsurance, PhasePlayerTurns}) {
+		t.Errorf("PhaseError has the wrong phases: %+v", phaseErr)
+	}
+	expected := "table is not in play: cannot end the round in the betting phase (only in insurance or player turns)"
+	if err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err.Error())
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	errs = table.Deal()
+	if len(errs) != 1 || !errors.Is(errs[0], ErrWrongPhase) || !errors.Is(errs[0], ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when dealing twice, expected WrongPhase and TableInPlay got %v", errs)
+	}
+	err = player.PlaceBet(10)
+	if !errors.Is(err, ErrWrongPhase) {
+		t.Errorf("didn't get appropriate error when betting mid-round, expected WrongPhase got %s", err)
+	}
+	_, err = table.Settle()
+	if !errors.Is(err, ErrWrongPhase) || !errors.Is(err, ErrRoundNotEnded) {
+		t.Errorf("didn't get appropriate error when settling mid-round, expected WrongPhase and RoundNotEnded got %s", err)
+	}
+
+	err = player.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = table.Settle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = table.Settle()
+	if !errors.Is(err, ErrWrongPhase) || !errors.Is(err, ErrRoundSettled) {
+		t.Errorf("didn't get appropriate error when settling twice, expected WrongPhase and RoundSettled got %s", err)
+	}
+}
+
+// Test the transition table.
+func TestPhaseTransitions(t *testing.T) {
+	valid := [][2]Phase{
+		{PhaseBetting, PhaseDealing},
+		{PhaseDealing, PhaseInsurance},
+		{PhaseDealing, PhasePlayerTurns},
+		{PhaseInsurance, PhasePlayerTurns},
+		{PhasePlayerTurns, PhaseDealerTurn},
+		{PhaseDealerTurn, PhaseSettlement},
+		{PhaseSettlement, PhaseIdle},
+		{PhaseIdle, PhaseBetting},
+	}
+	for _, v := range valid {
+		if !v[0].CanMoveTo(v[1]) {
+			t.Errorf("expected %s to be able to move to %s", v[0], v[1])
+		}
+	}
+	invalid := [][2]Phase{
+		{PhaseBetting, PhasePlayerTurns},
+		{PhaseInsurance, PhaseDealerTurn},
+		{PhasePlayerTurns, PhaseSettlement},
+		{PhaseSettlement, PhaseDealing},
+		{PhaseIdle, PhaseDealing},
+		{Phase(42), PhaseBetting},
+	}
+	for _, v := range invalid {
+		if v[0].CanMoveTo(v[1]) {
+			t.Errorf("expected %s not to be able to move to %s", v[0], v[1])
+		}
+	}
+
+	table := NewTable(1)
+	err := table.setPhase(PhaseSettlement)
+	if !errors.Is(err, ErrPhaseTransition) {
+		t.Errorf("didn't get appropriate error when skipping phases, expected PhaseTransition got %s", err)
+	}
+	if table.phase != PhaseBetting {
+		t.Errorf("expected a failed transition to leave the phase alone, got %s", table.phase)
+	}
+	if Phase(42).String() != "unknown" {
+		t.Errorf("expected \"unknown\", got %q", Phase(42).String())
+	}
+}
@@ -10,6 +10,16 @@ type Player struct {
 
 	// A Player has one (or possibly two) Hands.
 	Hands []*Hand
+
+	// dealer is true if this Player is the house, playing the dealer's hand on its Table.
+	dealer bool
+	// seat is this Player's index in Table.Players, or SeatDealer for the dealer.
+	seat int
+
+	// bankroll is the amount of money this Player has available to wager.
+	bankroll int
+	// bet is the amount this Player has wagered on their next Hand. It has already been taken from the bankroll.
+	bet int
 }
 
 // NewPlayer returns a new Player instance.
@@ -18,15 +28,116 @@ func NewPlayer() (player *Player) {
 	return new(Player)
 }
 
+// Deposit adds the given amount to this Player's bankroll.
+func (p *Player) Deposit(amount int) (err error) {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	p.Lock()
+	defer p.Unlock()
+	p.bankroll += amount
+	return
+}
+
+// Bankroll returns the amount of money this Player has available to wager. Thread-safe.
+// This does not include any bet currently placed, or money riding on Hands in play.
+func (p *Player) Bankroll() (bankroll int) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.bankroll
+}
+
+// Bet returns the amount this Player has wagered on their next Hand. Thread-safe.
+func (p *Player) Bet() (bet int) {
+	p.RLock()
+	defer p.RUnlock()
+	return p.bet
+}
+
+// PlaceBet wagers the given amount on this Player's next Hand, taking it from their bankroll.
+// Any bet already placed is returned to the bankroll first, so this can be used to change (or, with 0, withdraw) a bet.
+// Bets can only be placed while the Player's Table is not in play, and must be within the Table's limits.
+func (p *Player) PlaceBet(amount int) (err error) {
+	if amount < 0 {
+		return ErrInvalidAmount
+	}
+
+	// Take the table lock first (if we have one), to keep lock ordering consistent with the rest of the package.
+	p.RLock()
+	t := p.Table
+	p.RUnlock()
+	defer t.dispatchEvents()
+	if t != nil {
+		t.Lock()
+		defer t.Unlock()
+		err = t.checkPhase("place a bet", ErrTableInPlay, betweenRounds...)
+		if err != nil {
+			return err
+		}
+		err = t.checkBet(amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if p.dealer {
+		return ErrPlayerInvalid
+	}
+	if p.bankroll+p.bet < amount {
+		return ErrInsufficientFunds
+	}
+	p.bankroll += p.bet - amount
+	p.bet = amount
+	if t != nil {
+		t.emit(Event{Type: EventBetPlaced, Seat: p.seat, HandIndex: -1, Player: p, Amount: amount})
+	}
+	return
+}
+
+// takeFunds removes the given amount from this Player's bankroll, so that it can be staked on a Hand.
+// Returns ErrInsufficientFunds if the Player can't afford it.
+func (p *Player) takeFunds(amount int) (err error) {
+	p.Lock()
+	defer p.Unlock()
+	if p.bankroll < amount {
+		return ErrInsufficientFunds
+	}
+	p.bankroll -= amount
+	return
+}
+
+// giveFunds returns the given amount to this Player's bankroll, e.g. when a stake was taken for an action that then failed.
+func (p *Player) giveFunds(amount int) {
+	p.Lock()
+	defer p.Unlock()
+	p.bankroll += amount
+}
+
+// takeBet removes this Player's placed bet, returning it so that it can be staked on a Hand.
+func (p *Player) takeBet() (bet int) {
+	p.Lock()
+	defer p.Unlock()
+	bet = p.bet
+	p.bet = 0
+	return bet
+}
+
 // clearHands empties out this Player's hands.
+// The old hands are locked and detached from the Player and Table, so they can't be played any further, but their cards
+// can still be read (e.g. to verify a provably fair shoe).
 // All hands must be unlocked for write, or this will stall.
 // SWEng: Should this stall? Should we return an error if we can't get the lock?
 func (p *Player) clearHands() {
 	p.Lock()
 	defer p.Unlock()
 	for _, h := range p.Hands {
-		// Ensure write lock is available (no need to worry about releasing, we'll be destroying it shortly)
 		h.Lock()
+		h.locked = true
+		h.Player = nil
+		h.Table = nil
+		h.Unlock()
 	}
 	// Create empty hands table (garbage collector should take care of the orphans)
 	p.Hands = []*Hand{}
@@ -41,3 +152,37 @@ func (p *Player) newHand() (hand *Hand, err error) {
 	p.Hands = append(p.Hands, &h)
 	return &h, err
 }
+
+// splitHand instantiates a new Hand on the given Player for a split from the given Hand, placing it directly after it.
+// The new Hand is staked with the given wager, taken from the Player's bankroll.
+// Returns an error if the Player already holds maxHands Hands, or can't afford the wager.
+func (p *Player) splitHand(from *Hand, maxHands int, wager int) (hand *Hand, err error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(p.Hands) >= maxHands {
+		return nil, ErrSplitLimit
+	}
+	if p.bankroll < wager {
+		return nil, ErrInsufficientFunds
+	}
+	h, err := newHand(p)
+	if err != nil {
+		return nil, err
+	}
+	h.wager = wager
+	p.bankroll -= wager
+
+	// Slot the new hand in after the one it was split from, so that hands are played in order.
+	index := len(p.Hands)
+	for i, ph := range p.Hands {
+		if ph == from {
+			index = i + 1
+			break
+		}
+	}
+	p.Hands = append(p.Hands, nil)
+	copy(p.Hands[index+1:], p.Hands[index:])
+	p.Hands[index] = &h
+	return &h, nil
+}
@@ -1,6 +1,9 @@
 package blackjack
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestPlayerClearHands(t *testing.T) {
 	table := NewTable(1)
@@ -19,3 +22,63 @@ func TestPlayerClearHands(t *testing.T) {
 }
 
 // newHand is tested by other files.
+
+// Test that bankrolls and bets are tracked correctly.
+func TestPlayerBets(t *testing.T) {
+	table := NewTable(1)
+	player := NewPlayer()
+	err := table.Join(player)
+	if err != nil {
+		t.Error(err)
+	}
+	err = player.Deposit(0)
+	if !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("didn't get appropriate error when depositing nothing, expected InvalidAmount got %s", err)
+	}
+	err = player.Deposit(100)
+	if err != nil {
+		t.Error(err)
+	}
+	err = player.PlaceBet(-1)
+	if !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("didn't get appropriate error when betting a negative amount, expected InvalidAmount got %s", err)
+	}
+	err = player.PlaceBet(101)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("didn't get appropriate error when overbetting, expected InsufficientFunds got %s", err)
+	}
+	err = player.PlaceBet(40)
+	if err != nil {
+		t.Error(err)
+	}
+	if player.Bankroll() != 60 || player.Bet() != 40 {
+		t.Errorf("bet not taken from bankroll, expected 60/40 got %v/%v", player.Bankroll(), player.Bet())
+	}
+	// Changing the bet should refund the old one first, so we can bet the whole lot.
+	err = player.PlaceBet(100)
+	if err != nil {
+		t.Error(err)
+	}
+	if player.Bankroll() != 0 || player.Bet() != 100 {
+		t.Errorf("bet not changed correctly, expected 0/100 got %v/%v", player.Bankroll(), player.Bet())
+	}
+	err = player.PlaceBet(10)
+	if err != nil {
+		t.Error(err)
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	if player.Hands[0].Wager() != 10 {
+		t.Errorf("bet not staked on hand, expected 10 got %v", player.Hands[0].Wager())
+	}
+	if player.Bet() != 0 {
+		t.Errorf("bet not cleared after deal, got %v", player.Bet())
+	}
+	err = player.PlaceBet(10)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when betting mid-game, expected TableInPlay got %s", err)
+	}
+}
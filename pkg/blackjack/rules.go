@@ -0,0 +1,201 @@
+package blackjack
+
+import (
+	"fmt"
+	"time"
+)
+
+// A RuleSet describes every house rule a Table plays by.
+// Tables are created with a RuleSet (see NewTableWithRules()), and it can be queried with Table.Rules().
+type RuleSet struct {
+	// Decks is the number of decks in the shoe.
+	Decks int `json:"decks"`
+	// Penetration is how far through the shoe (from 0 to 1) the cut card sits. Once that much of the shoe has been dealt,
+	// it's reshuffled before the next round. 0 reshuffles before every round.
+	Penetration float64 `json:"penetration"`
+
+	// DealerRule is whether the dealer stands or hits on a soft 17.
+	DealerRule DealerRule `json:"dealerRule"`
+	// DealerPeek is whether the dealer checks their hole card for blackjack straight after the deal when showing an ace or
+	// ten-value card.
+	DealerPeek bool `json:"dealerPeek"`
+	// NoHoleCard means the dealer only takes their second card once every Player has finished (the European style).
+	// A dealer blackjack then takes everything staked, including doubles and splits.
+	NoHoleCard bool `json:"noHoleCard"`
+
+	// BlackjackPayout is the ratio winning naturals are paid at.
+	BlackjackPayout Payout `json:"blackjackPayout"`
+	// Insurance is whether insurance and even money are offered when the dealer shows an ace.
+	Insurance bool `json:"insurance"`
+
+	// Double governs when Players may double down.
+	Double DoubleRules `json:"double"`
+	// Split governs when Players may split pairs.
+	Split SplitRules `json:"split"`
+	// Surrender governs whether, and when, Players may surrender.
+	Surrender SurrenderRule `json:"surrender"`
+
+	// SimultaneousPlay lets Players play their hands in any order, rather than strictly in seat order.
+	SimultaneousPlay bool `json:"simultaneousPlay"`
+	// DecisionTimeout is how long each Hand has to make its decisions once it becomes its turn. 0 means there is no limit.
+	DecisionTimeout time.Duration `json:"decisionTimeout"`
+	// TimeoutAction is what's done for a Hand that runs out of time.
+	TimeoutAction TimeoutAction `json:"timeoutAction"`
+
+	// MinBet is the smallest bet a Player may place. 0 means there is no minimum.
+	MinBet int `json:"minBet"`
+	// MaxBet is the largest bet a Player may place. 0 means there is no maximum.
+	MaxBet int `json:"maxBet"`
+}
+
+// DefaultRules are the rules that NewTable() plays by (with its own number of decks): S17, 3:2 blackjack, double on any two
+// (including after splits), split to four hands, no surrender, no peek, no insurance, and a fresh shoe every round.
+var DefaultRules = RuleSet{
+	Decks:           1,
+	Penetration:     0,
+	DealerRule:      DealerStandsSoft17,
+	BlackjackPayout: PayoutThreeToTwo,
+	Double:          DefaultDoubleRules,
+	Split:           DefaultSplitRules,
+	Surrender:       SurrenderNone,
+}
+
+// VegasStripRules are the classic Las Vegas Strip rules: four decks, S17, peek, double any two with DAS,
+// split to four hands, late surrender, and insurance.
+var VegasStripRules = RuleSet{
+	Decks:           4,
+	Penetration:     0.75,
+	DealerRule:      DealerStandsSoft17,
+	DealerPeek:      true,
+	BlackjackPayout: PayoutThreeToTwo,
+	Insurance:       true,
+	Double:          DoubleRules{Totals: DoubleAnyTwo, AfterSplit: true},
+	Split:           SplitRules{AnyTens: true, MaxHands: 4, AcesOneCard: true},
+	Surrender:       SurrenderLate,
+}
+
+// AtlanticCityRules are the typical Atlantic City rules: as VegasStripRules, but dealt from an eight deck shoe.
+var AtlanticCityRules = RuleSet{
+	Decks:           8,
+	Penetration:     0.75,
+	DealerRule:      DealerStandsSoft17,
+	DealerPeek:      true,
+	BlackjackPayout: PayoutThreeToTwo,
+	Insurance:       true,
+	Double:          DoubleRules{Totals: DoubleAnyTwo, AfterSplit: true},
+	Split:           SplitRules{AnyTens: true, MaxHands: 4, AcesOneCard: true},
+	Surrender:       SurrenderLate,
+}
+
+// EuropeanNoHoleCardRules are the typical European rules: six decks, S17, no hole card (so no peek or insurance),
+// double on 9-11 only, a single split, and no surrender.
+var EuropeanNoHoleCardRules = RuleSet{
+	Decks:           6,
+	Penetration:     0.7,
+	DealerRule:      DealerStandsSoft17,
+	NoHoleCard:      true,
+	BlackjackPayout: PayoutThreeToTwo,
+	Double:          DoubleRules{Totals: DoubleNineToEleven, AfterSplit: true},
+	Split:           SplitRules{AnyTens: false, MaxHands: 2, AcesOneCard: true},
+	Surrender:       SurrenderNone,
+}
+
+// Validate checks that this RuleSet makes sense, returning an error wrapping ErrInvalidRule that describes the first problem found.
+func (r RuleSet) Validate() (err error) {
+	if r.Decks < 1 {
+		return fmt.Errorf("%w: decks must be at least 1, got %v", ErrInvalidRule, r.Decks)
+	}
+	if r.Penetration < 0 || r.Penetration > 1 {
+		return fmt.Errorf("%w: penetration must be between 0 and 1, got %v", ErrInvalidRule, r.Penetration)
+	}
+	if _, exists := dealerRuleNames[r.DealerRule]; !exists {
+		return fmt.Errorf("%w: unknown dealer rule %v", ErrInvalidRule, uint8(r.DealerRule))
+	}
+	if r.NoHoleCard && (r.DealerPeek || r.Insurance) {
+		return fmt.Errorf("%w: the dealer can't peek or offer insurance without a hole card", ErrInvalidRule)
+	}
+	if !r.BlackjackPayout.Valid() {
+		return fmt.Errorf("%w: invalid blackjack payout %s", ErrInvalidRule, r.BlackjackPayout)
+	}
+	if !r.Double.Valid() {
+		return fmt.Errorf("%w: unknown double totals %v", ErrInvalidRule, uint8(r.Double.Totals))
+	}
+	if !r.Split.Valid() {
+		return fmt.Errorf("%w: split hands must not be negative, got %v", ErrInvalidRule, r.Split.MaxHands)
+	}
+	if _, exists := surrenderRuleNames[r.Surrender]; !exists {
+		return fmt.Errorf("%w: unknown surrender rule %v", ErrInvalidRule, uint8(r.Surrender))
+	}
+	if r.DecisionTimeout < 0 {
+		return fmt.Errorf("%w: decision timeout must not be negative, got %v", ErrInvalidRule, r.DecisionTimeout)
+	}
+	if _, exists := timeoutActionNames[r.TimeoutAction]; !exists {
+		return fmt.Errorf("%w: unknown timeout action %v", ErrInvalidRule, uint8(r.TimeoutAction))
+	}
+	if r.MinBet < 0 || r.MaxBet < 0 {
+		return fmt.Errorf("%w: bet limits must not be negative", ErrInvalidRule)
+	}
+	if r.MaxBet > 0 && r.MinBet > r.MaxBet {
+		return fmt.Errorf("%w: minimum bet %v is above the maximum %v", ErrInvalidRule, r.MinBet, r.MaxBet)
+	}
+	return
+}
+
+// NewTableWithRules initializes a new Table that plays by the given RuleSet, validating it first.
+func NewTableWithRules(rules RuleSet) (table *Table, err error) {
+	err = rules.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return newTable(rules), nil
+}
+
+// Rules returns the RuleSet this Table is currently playing by.
+func (t *Table) Rules() (rules RuleSet) {
+	t.Lock()
+	defer t.Unlock()
+	return t.sRules
+}
+
+// SetRules replaces the RuleSet this Table plays by, validating it first.
+// The rules cannot be changed while the table is in play.
+func (t *Table) SetRules(rules RuleSet) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		*r = rules
+	})
+}
+
+// updateRules applies the given change to a copy of the table's RuleSet, and then swaps it in if it's still valid
+// and the table isn't in play.
+func (t *Table) updateRules(change func(r *RuleSet)) (err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	rules := t.sRules
+	change(&rules)
+	err = rules.Validate()
+	if err != nil {
+		return err
+	}
+	err = t.checkPhase("change the rules", ErrTableInPlay, betweenRounds...)
+	if err != nil {
+		return err
+	}
+	t.sRules = rules
+	return
+}
+
+// checkBet returns an error if the given bet is outside of the table's limits. A bet of 0 (no bet) is always allowed.
+// The table lock must be held by the caller.
+func (t *Table) checkBet(amount int) (err error) {
+	if amount == 0 {
+		return
+	}
+	if amount < t.sRules.MinBet {
+		return ErrBetBelowMinimum
+	}
+	if t.sRules.MaxBet > 0 && amount > t.sRules.MaxBet {
+		return ErrBetAboveMaximum
+	}
+	return
+}
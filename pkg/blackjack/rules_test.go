@@ -0,0 +1,189 @@
+package blackjack
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test that the preset rule sets are valid, and that broken ones are refused.
+func TestRuleSetValidate(t *testing.T) {
+	presets := map[string]RuleSet{
+		"default":       DefaultRules,
+		"vegas strip":   VegasStripRules,
+		"atlantic city": AtlanticCityRules,
+		"european":      EuropeanNoHoleCardRules,
+	}
+	for name, rules := range presets {
+		err := rules.Validate()
+		if err != nil {
+			t.Errorf("%s rules should be valid, got %s", name, err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		change func(r *RuleSet)
+	}{
+		{"no decks", func(r *RuleSet) { r.Decks = 0 }},
+		{"negative penetration", func(r *RuleSet) { r.Penetration = -0.5 }},
+		{"penetration past the shoe", func(r *RuleSet) { r.Penetration = 1.5 }},
+		{"unknown dealer rule", func(r *RuleSet) { r.DealerRule = DealerRule(42) }},
+		{"peek without hole card", func(r *RuleSet) { r.NoHoleCard = true; r.DealerPeek = true }},
+		{"insurance without hole card", func(r *RuleSet) { r.NoHoleCard = true; r.Insurance = true }},
+		{"bad payout", func(r *RuleSet) { r.BlackjackPayout = Payout{Pays: 3, Per: 0} }},
+		{"unknown double totals", func(r *RuleSet) { r.Double.Totals = DoubleTotals(42) }},
+		{"negative split hands", func(r *RuleSet) { r.Split.MaxHands = -1 }},
+		{"unknown surrender rule", func(r *RuleSet) { r.Surrender = SurrenderRule(42) }},
+		{"negative minimum bet", func(r *RuleSet) { r.MinBet = -1 }},
+		{"minimum above maximum", func(r *RuleSet) { r.MinBet = 50; r.MaxBet = 10 }},
+	}
+	for _, c := range cases {
+		rules := DefaultRules
+		c.change(&rules)
+		err := rules.Validate()
+		if !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("%s: expected InvalidRule got %s", c.name, err)
+		}
+	}
+}
+
+func TestNewTableWithRules(t *testing.T) {
+	table, err := NewTableWithRules(VegasStripRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Rules() != VegasStripRules {
+		t.Errorf("table rules don't match, got %+v", table.Rules())
+	}
+	if !table.DealerPeeks() || table.SurrenderRule() != SurrenderLate {
+		t.Errorf("single rule getters don't reflect the table's rules")
+	}
+
+	broken := DefaultRules
+	broken.Decks = 0
+	_, err = NewTableWithRules(broken)
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error with invalid rules, expected InvalidRule got %s", err)
+	}
+}
+
+func TestSetRules(t *testing.T) {
+	table := NewTable(1)
+	err := table.SetRules(AtlanticCityRules)
+	if err != nil {
+		t.Error(err)
+	}
+	if table.Rules() != AtlanticCityRules {
+		t.Errorf("table rules not replaced")
+	}
+	err = table.SetDealerRule(DealerHitsSoft17)
+	if err != nil {
+		t.Error(err)
+	}
+	if table.Rules().DealerRule != DealerHitsSoft17 || table.Rules().Decks != 8 {
+		t.Errorf("single rule setter should only change its own rule, got %+v", table.Rules())
+	}
+
+	broken := DefaultRules
+	broken.NoHoleCard = true
+	broken.DealerPeek = true
+	before := table.Rules()
+	err = table.SetRules(broken)
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error with invalid rules, expected InvalidRule got %s", err)
+	}
+	if table.Rules() != before {
+		t.Errorf("invalid rules shouldn't change anything")
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	err = table.SetRules(DefaultRules)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing rules mid-game, expected TableInPlay got %s", err)
+	}
+}
+
+// Test that bets outside the table's limits are refused.
+func TestBetLimits(t *testing.T) {
+	rules := DefaultRules
+	rules.MinBet = 5
+	rules.MaxBet = 50
+	table, err := NewTableWithRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.Deposit(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.PlaceBet(4)
+	if !errors.Is(err, ErrBetBelowMinimum) {
+		t.Errorf("didn't get appropriate error when betting under the minimum, expected BetBelowMinimum got %s", err)
+	}
+	err = player.PlaceBet(51)
+	if !errors.Is(err, ErrBetAboveMaximum) {
+		t.Errorf("didn't get appropriate error when betting over the maximum, expected BetAboveMaximum got %s", err)
+	}
+	err = player.PlaceBet(50)
+	if err != nil {
+		t.Error(err)
+	}
+	// Sitting out is always allowed.
+	err = player.PlaceBet(0)
+	if err != nil {
+		t.Error(err)
+	}
+	if player.Bankroll() != 100 {
+		t.Errorf("refused bets shouldn't touch the bankroll, expected 100 got %v", player.Bankroll())
+	}
+}
+
+// Test that a dealer with no hole card only takes their second card when they play.
+func TestNoHoleCard(t *testing.T) {
+	table, err := NewTableWithRules(EuropeanNoHoleCardRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if len(table.dealerHand().Cards) != 1 {
+		t.Errorf("dealer should only have an up card, got %v cards", len(table.dealerHand().Cards))
+	}
+	_, err = table.DealerUpCard()
+	if err != nil {
+		t.Error(err)
+	}
+	hand := player.Hands[0]
+	if !hand.locked {
+		err = hand.Stick()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dealer, err := table.DealerHand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dealer.Cards) < 2 {
+		t.Errorf("dealer should have drawn their second card, got %v cards", len(dealer.Cards))
+	}
+}
@@ -0,0 +1,151 @@
+package blackjack
+
+import "fmt"
+
+// A Payout is the ratio that a winning natural blackjack is paid at, e.g. 3:2 pays 3 for every 2 wagered.
+type Payout struct {
+	// Pays is the amount won for every Per wagered.
+	Pays int `json:"pays"`
+	// Per is the amount wagered to win Pays.
+	Per int `json:"per"`
+}
+
+// Common blackjack payouts.
+//
+//goland:noinspection GoUnusedGlobalVariable
+var (
+	PayoutThreeToTwo = Payout{Pays: 3, Per: 2}
+	PayoutSixToFive  = Payout{Pays: 6, Per: 5}
+	PayoutEvenMoney  = Payout{Pays: 1, Per: 1}
+)
+
+// String returns the payout in the usual notation (e.g. "3:2").
+func (p Payout) String() string {
+	return fmt.Sprintf("%v:%v", p.Pays, p.Per)
+}
+
+// Valid returns whether this Payout can be used to pay out a wager.
+func (p Payout) Valid() bool {
+	return p.Pays > 0 && p.Per > 0
+}
+
+// Winnings returns the amount won on the given wager at this payout, not including the wager itself.
+// Fractional amounts are rounded down, in the house's favour.
+func (p Payout) Winnings(wager int) int {
+	if !p.Valid() {
+		return 0
+	}
+	return wager * p.Pays / p.Per
+}
+
+// A Result is the record of a single Hand being settled at the end of a round.
+type Result struct {
+	// Player is the Player that owns the settled Hand.
+	Player *Player
+	// Hand is the Hand that was settled.
+	Hand *Hand
+	// Outcome is how the Hand fared against the dealer.
+	Outcome Outcome
+	// Wager is the amount that was staked on the Hand.
+	Wager int
+	// Payout is the total amount returned to the Player's bankroll, including their original wager.
+	Payout int
+	// Insurance is the amount that was staked on insurance for the Hand.
+	Insurance int
+	// InsurancePayout is the amount the insurance returned to the Player's bankroll (already paid when the dealer peeked).
+	InsurancePayout int
+	// Net is the Player's overall gain (or loss, if negative) on the Hand, including any insurance.
+	Net int
+}
+
+// SetBlackjackPayout sets the ratio that winning naturals are paid at. This is a shortcut for changing a single rule with SetRules().
+// The payout cannot be changed while the table is in play.
+func (t *Table) SetBlackjackPayout(payout Payout) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.BlackjackPayout = payout
+	})
+}
+
+// BlackjackPayout returns the ratio that winning naturals are currently paid at.
+func (t *Table) BlackjackPayout() (payout Payout) {
+	return t.Rules().BlackjackPayout
+}
+
+// Settle pays out every Player's Hands against the dealer's: wins are paid 1:1, naturals at the table's
+// BlackjackPayout (or 1:1 if even money was taken), pushes are returned, surrenders get half their wager back,
+// and losses are collected by the house. Insurance has already been paid when the dealer peeked, but is included in each Result.
+// It returns a Result for every Hand settled, in seat order.
+// It can only be called once per round, after EndRound().
+func (t *Table) Settle() (results []Result, err error) {
+	defer t.dispatchEvents()
+	t.Lock()
+	defer t.Unlock()
+
+	notEnded := ErrRoundNotEnded
+	if t.phase == PhaseIdle {
+		notEnded = ErrRoundSettled
+	}
+	err = t.checkPhase("settle", notEnded, PhaseSettlement)
+	if err != nil {
+		return nil, err
+	}
+	return t.settle()
+}
+
+// settle is the lock-free implementation of Settle().
+// The table lock must be held by the caller.
+func (t *Table) settle() (results []Result, err error) {
+	dealer := t.dealerHand()
+	if dealer == nil {
+		return nil, ErrDealerNoHand
+	}
+	t.record(Action{Type: ActionSettle, Seat: SeatDealer, HandIndex: -1})
+
+	for _, p := range t.Players {
+		p.Lock()
+		for _, h := range p.Hands {
+			r := t.settleHand(h, dealer)
+			p.bankroll += r.Payout
+			results = append(results, r)
+			e := h.lockedEvent(EventSettled)
+			e.Outcome = r.Outcome
+			e.Amount = r.Net
+			t.emit(e)
+		}
+		p.Unlock()
+	}
+	return results, t.setPhase(PhaseIdle)
+}
+
+// settleHand works out the Result of a single Hand against the dealer's.
+// It doesn't touch the Player's bankroll; that's left to the caller.
+func (t *Table) settleHand(h *Hand, dealer *Hand) (r Result) {
+	r.Outcome = compareHands(h, dealer)
+
+	h.RLock()
+	defer h.RUnlock()
+
+	r.Player = h.Player
+	r.Hand = h
+	r.Wager = h.wager
+	r.Insurance = h.insurance
+	r.InsurancePayout = h.insurancePayout
+
+	switch r.Outcome {
+	case OutcomeBlackjack:
+		payout := t.sRules.BlackjackPayout
+		if !payout.Valid() {
+			payout = PayoutThreeToTwo
+		}
+		r.Payout = h.wager + payout.Winnings(h.wager)
+	case OutcomeWin, OutcomeEvenMoney:
+		r.Payout = h.wager * 2
+	case OutcomePush:
+		r.Payout = h.wager
+	case OutcomeSurrender:
+		// Odd chips go to the house.
+		r.Payout = h.wager / 2
+	}
+	r.Net = r.Payout - r.Wager + r.InsurancePayout - r.Insurance
+	return r
+}
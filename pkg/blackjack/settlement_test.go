@@ -0,0 +1,152 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+func TestPayout(t *testing.T) {
+	if PayoutThreeToTwo.String() != "3:2" {
+		t.Errorf("payout returned a name of %s", PayoutThreeToTwo.String())
+	}
+	if PayoutThreeToTwo.Winnings(10) != 15 {
+		t.Errorf("3:2 on 10 should win 15, got %v", PayoutThreeToTwo.Winnings(10))
+	}
+	// Fractions go to the house.
+	if PayoutSixToFive.Winnings(12) != 14 {
+		t.Errorf("6:5 on 12 should win 14, got %v", PayoutSixToFive.Winnings(12))
+	}
+	if PayoutEvenMoney.Winnings(7) != 7 {
+		t.Errorf("1:1 on 7 should win 7, got %v", PayoutEvenMoney.Winnings(7))
+	}
+	if (Payout{}).Winnings(10) != 0 {
+		t.Errorf("invalid payout shouldn't pay anything, got %v", (Payout{}).Winnings(10))
+	}
+
+	table := NewTable(1)
+	if table.BlackjackPayout() != PayoutThreeToTwo {
+		t.Errorf("new table should default to 3:2, got %s", table.BlackjackPayout())
+	}
+	err := table.SetBlackjackPayout(Payout{Pays: 1})
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error when setting bad payout, expected InvalidRule got %s", err)
+	}
+	err = table.SetBlackjackPayout(PayoutSixToFive)
+	if err != nil {
+		t.Error(err)
+	}
+	if table.BlackjackPayout() != PayoutSixToFive {
+		t.Errorf("payout not set, expected 6:5 got %s", table.BlackjackPayout())
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	err = table.SetBlackjackPayout(PayoutThreeToTwo)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing payout mid-game, expected TableInPlay got %s", err)
+	}
+}
+
+// settlementTable is a helper that returns an ended round with one 10-chip hand per given set of player cards,
+// played against the given dealer cards.
+func settlementTable(t *testing.T, dealer []playdeck.Card, players ...[]playdeck.Card) (table *Table) {
+	t.Helper()
+	rules := DefaultRules
+	rules.Decks = 1
+	table = seatedTable(t, rules, 1, len(players))
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	for i, cards := range players {
+		h := table.Players[i].Hands[0]
+		h.Cards = cards
+		h.locked = true
+		err := h.EvalScore()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := table.dealerHand()
+	d.Cards = dealer
+	d.locked = true
+	err := d.EvalScore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.phase = PhaseSettlement
+	return table
+}
+
+// Test that every outcome is paid correctly.
+func TestSettle(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+	eight := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueEight}
+	seven := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSeven}
+
+	table := NewTable(1)
+	_, err := table.Settle()
+	if !errors.Is(err, ErrRoundNotEnded) {
+		t.Errorf("didn't get appropriate error when settling early, expected RoundNotEnded got %s", err)
+	}
+
+	table = settlementTable(t, []playdeck.Card{king, eight},
+		[]playdeck.Card{ace, king},
+		[]playdeck.Card{king, nine},
+		[]playdeck.Card{king, eight},
+		[]playdeck.Card{king, seven},
+	)
+	results, err := table.Settle()
+	if err != nil {
+		t.Error(err)
+	}
+	expected := []struct {
+		outcome  Outcome
+		payout   int
+		net      int
+		bankroll int
+	}{
+		{OutcomeBlackjack, 25, 15, 115},
+		{OutcomeWin, 20, 10, 110},
+		{OutcomePush, 10, 0, 100},
+		{OutcomeLose, 0, -10, 90},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %v results, got %v", len(expected), len(results))
+	}
+	for i, e := range expected {
+		r := results[i]
+		if r.Player != table.Players[i] || r.Hand != table.Players[i].Hands[0] {
+			t.Errorf("result %v not attributed to the right player and hand", i)
+		}
+		if r.Outcome != e.outcome || r.Wager != 10 || r.Payout != e.payout || r.Net != e.net {
+			t.Errorf("result %v: expected %s paying %v (net %v), got %+v", i, e.outcome, e.payout, e.net, r)
+		}
+		if table.Players[i].Bankroll() != e.bankroll {
+			t.Errorf("result %v: expected bankroll of %v, got %v", i, e.bankroll, table.Players[i].Bankroll())
+		}
+	}
+
+	_, err = table.Settle()
+	if !errors.Is(err, ErrRoundSettled) {
+		t.Errorf("didn't get appropriate error when settling twice, expected RoundSettled got %s", err)
+	}
+}
+
+// Test that resetting an unsettled round pays everyone out anyway.
+func TestResetSettles(t *testing.T) {
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	eight := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueEight}
+	table := settlementTable(t, []playdeck.Card{king, eight}, []playdeck.Card{king, king})
+	err := table.Reset()
+	if err != nil {
+		t.Error(err)
+	}
+	if table.Players[0].Bankroll() != 110 {
+		t.Errorf("winning hand not paid on reset, expected bankroll of 110 got %v", table.Players[0].Bankroll())
+	}
+}
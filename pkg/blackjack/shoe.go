@@ -0,0 +1,159 @@
+package blackjack
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+)
+
+// Shuffled returns true if the shoe was reshuffled during the current round, either when it was dealt (because the
+// cut card had been reached) or mid-round (because the shoe ran dry and the discard tray was shuffled back in).
+// Card counters should start their count again whenever this is true.
+func (t *Table) Shuffled() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.shuffled
+}
+
+// SetSource sets where the table's shoe gets its randomness from when it's shuffled. nil goes back to
+// playdeck.DefaultSource. Use playdeck.NewSeededSource() to make games reproducible, or playdeck.NewCryptoSource() if
+// shuffles mustn't be predictable.
+// The source cannot be changed while the table is in play.
+func (t *Table) SetSource(src playdeck.Source) (err error) {
+	t.Lock()
+	defer t.Unlock()
+	err = t.checkPhase("change the source", ErrTableInPlay, betweenRounds...)
+	if err != nil {
+		return err
+	}
+	t.source = src
+	if t.Deck != nil {
+		t.Deck.Lock()
+		t.Deck.Source = src
+		t.Deck.Unlock()
+	}
+	return
+}
+
+// Source returns where the table's shoe gets its randomness from, or nil if it's using playdeck.DefaultSource.
+func (t *Table) Source() playdeck.Source {
+	t.Lock()
+	defer t.Unlock()
+	return t.source
+}
+
+// CardsRemaining returns the number of cards left in the shoe.
+func (t *Table) CardsRemaining() int {
+	t.Lock()
+	defer t.Unlock()
+	if t.Deck == nil {
+		return 0
+	}
+	return t.Deck.Len()
+}
+
+// CutCardReached returns true if enough of the shoe has been dealt that it will be reshuffled before the next round.
+func (t *Table) CutCardReached() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.cutCardReached()
+}
+
+// cutCardReached is the implementation behind CutCardReached().
+// The table lock must be held by the caller.
+func (t *Table) cutCardReached() bool {
+	// A shoe of the wrong size (e.g. a brand new table, or the number of decks has changed) always needs replacing.
+	if t.shoeSize == 0 || t.shoeSize != t.sRules.Decks*52 {
+		return true
+	}
+	if t.sRules.Penetration == 0 {
+		return true
+	}
+	// Once a provably fair shoe's seed has been revealed, its order is public.
+	if t.fair != nil && t.fair.revealed {
+		return true
+	}
+	dealt := t.shoeSize - t.Deck.Len()
+	return float64(dealt) >= t.sRules.Penetration*float64(t.shoeSize)
+}
+
+// reshuffle replaces the shoe with a freshly shuffled one of the table's number of decks, and empties the discard tray.
+// The table lock must be held by the caller.
+func (t *Table) reshuffle() (err error) {
+	t.Deck = playdeck.NewDeckOfDecks(t.sRules.Decks, false)
+	t.Deck.Source = t.source
+	if t.fair != nil {
+		err = t.fair.nextShoe(t.Deck, t.sRules.Decks)
+		if err != nil {
+			return err
+		}
+	}
+	t.Discards = playdeck.NewDeckOfDecks(0, false)
+	t.shoeSize = t.sRules.Decks * 52
+	t.shoePos = 0
+	t.shuffled = true
+	return t.Deck.Shuffle()
+}
+
+// discardHands moves every card in every Player's (and the dealer's) hands into the discard tray, and clears the hands.
+// The table lock must be held by the caller.
+func (t *Table) discardHands() (err error) {
+	if t.Discards == nil {
+		t.Discards = playdeck.NewDeckOfDecks(0, false)
+	}
+	players := t.Players
+	if t.dealer != nil {
+		players = append(players[:len(players):len(players)], t.dealer)
+	}
+	for _, p := range players {
+		p.RLock()
+		for _, h := range p.Hands {
+			h.RLock()
+			for _, c := range h.Cards {
+				err = t.Discards.PushCard(c)
+				if err != nil {
+					h.RUnlock()
+					p.RUnlock()
+					return err
+				}
+			}
+			h.RUnlock()
+		}
+		p.RUnlock()
+		p.clearHands()
+	}
+	return
+}
+
+// draw takes the top card from the shoe, returning where in the shoe it was.
+// If the shoe has run dry mid-round, the discard tray is shuffled back into it first. Provably fair shoes can't be refilled,
+// as there'd be no way to verify the new order.
+// The table lock must be held by the caller.
+func (t *Table) draw() (card playdeck.Card, position int, err error) {
+	card, err = t.Deck.PullCard()
+	if err != playdeck.ErrDeckEmpty || t.fair != nil || t.Discards == nil || t.Discards.Len() == 0 {
+		if err == nil {
+			position = t.shoePos
+			t.shoePos++
+		}
+		return card, position, err
+	}
+
+	for t.Discards.Len() > 0 {
+		c, e := t.Discards.PullCard()
+		if e != nil {
+			return card, 0, e
+		}
+		e = t.Deck.PushCard(c)
+		if e != nil {
+			return card, 0, e
+		}
+	}
+	err = t.Deck.Shuffle()
+	if err != nil {
+		return card, 0, err
+	}
+	t.shuffled = true
+	t.shoePos = 1
+	t.emitTable(EventShuffle)
+	card, err = t.Deck.PullCard()
+	return card, 0, err
+}
@@ -0,0 +1,173 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"reflect"
+	"testing"
+)
+
+// cardsInPlay is a helper that counts every card in the shoe, the discard tray and every hand at the table.
+func cardsInPlay(table *Table) (count int) {
+	count = table.Deck.Len() + table.Discards.Len()
+	for _, p := range append(table.Players, table.dealer) {
+		for _, h := range p.Hands {
+			count += len(h.Cards)
+		}
+	}
+	return count
+}
+
+// Test that the shoe persists between rounds, and is only reshuffled once the cut card is reached.
+func TestShoePenetration(t *testing.T) {
+	rules := DefaultRules
+	rules.Penetration = 0.5
+	table, err := NewTableWithRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if !table.Shuffled() {
+		t.Errorf("a new table's shoe should be shuffled on the first deal")
+	}
+
+	shuffles := 0
+	for round := 0; round < 20; round++ {
+		if cardsInPlay(table) != 52 {
+			t.Fatalf("cards have gone missing, expected 52 got %v", cardsInPlay(table))
+		}
+		hand := player.Hands[0]
+		if !hand.locked {
+			err = hand.Stick()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		err = table.EndRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cutCard := table.CutCardReached()
+		if cutCard != (table.Deck.Len() <= 26) {
+			t.Errorf("cut card reached is %v with %v cards left in the shoe", cutCard, table.Deck.Len())
+		}
+
+		errs = table.Deal()
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if table.Shuffled() != cutCard {
+			t.Errorf("shoe shuffled is %v, but the cut card reached was %v", table.Shuffled(), cutCard)
+		}
+		if table.Shuffled() {
+			shuffles++
+			if table.Discards.Len() != 0 {
+				t.Errorf("discard tray should be emptied by a shuffle, got %v cards", table.Discards.Len())
+			}
+		} else if table.Discards.Len() == 0 {
+			t.Errorf("discard tray should hold the last round's cards")
+		}
+	}
+	if shuffles == 0 {
+		t.Errorf("shoe was never reshuffled")
+	}
+}
+
+// Test that a penetration of 0 gives a fresh shoe every round, like it always used to.
+func TestShoeReshuffleEveryRound(t *testing.T) {
+	table := NewTable(2)
+	for i := 0; i < 3; i++ {
+		errs := table.Deal()
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if !table.Shuffled() {
+			t.Errorf("round %v wasn't dealt from a fresh shoe", i)
+		}
+		if table.CardsRemaining() != 102 {
+			t.Errorf("expected 102 cards left in the shoe, got %v", table.CardsRemaining())
+		}
+		err := table.EndRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test that a shoe that runs dry mid-round has the discard tray shuffled back into it.
+func TestShoeRunsDry(t *testing.T) {
+	table := NewTable(1)
+	table.sRules.Penetration = 1
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	table.shuffled = false
+	table.Discards = playdeck.NewDeckOfDecks(1, false)
+	table.Deck.Cards = &[]playdeck.Card{}
+
+	table.Lock()
+	_, _, err := table.draw()
+	table.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !table.Shuffled() {
+		t.Errorf("refilling the shoe should count as a shuffle")
+	}
+	if table.Deck.Len() != 51 || table.Discards.Len() != 0 {
+		t.Errorf("discard tray not moved into the shoe, got %v in the shoe and %v discarded", table.Deck.Len(), table.Discards.Len())
+	}
+}
+
+// dealtCards is a helper that returns every card dealt to the table's players and dealer, in seat order.
+func dealtCards(table *Table) (cards []playdeck.Card) {
+	for _, p := range append(table.Players, table.dealer) {
+		for _, h := range p.Hands {
+			cards = append(cards, h.Cards...)
+		}
+	}
+	return cards
+}
+
+// Test that tables with the same seeded source deal the same cards.
+func TestTableSource(t *testing.T) {
+	var deals [2][]playdeck.Card
+	for i := range deals {
+		table := NewTable(6)
+		if table.Source() != nil {
+			t.Errorf("new table should use the default source")
+		}
+		err := table.SetSource(playdeck.NewSeededSource(1234))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 3; j++ {
+			err = table.Join(NewPlayer())
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		errs := table.Deal()
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		err = table.SetSource(nil)
+		if !errors.Is(err, ErrTableInPlay) {
+			t.Errorf("didn't get appropriate error when changing source mid-game, expected TableInPlay got %s", err)
+		}
+		deals[i] = dealtCards(table)
+	}
+	if !reflect.DeepEqual(deals[0], deals[1]) {
+		t.Errorf("tables with the same seed dealt different cards: %v and %v", deals[0], deals[1])
+	}
+}
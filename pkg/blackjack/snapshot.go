@@ -0,0 +1,307 @@
+package blackjack
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+)
+
+// A Snapshot is a copy of the entire state of a Table, which can be marshalled (e.g. to JSON), stored, and turned back into
+// a working Table with Restore() - for instance to resume a game after a restart.
+// If provably fair shuffling is on, a Snapshot holds the table's secret server seeds, so keep it somewhere safe.
+// A Table's random Source isn't included; restored Tables use playdeck.DefaultSource until told otherwise.
+type Snapshot struct {
+	Rules RuleSet `json:"rules"`
+	// Deck is the shoe, top card first.
+	Deck     []playdeck.Card `json:"deck"`
+	Discards []playdeck.Card `json:"discards"`
+
+	Phase         Phase `json:"phase"`
+	InsuranceOpen bool  `json:"insuranceOpen"`
+
+	ShoeSize int           `json:"shoeSize"`
+	ShoePos  int           `json:"shoePos"`
+	Shuffled bool          `json:"shuffled"`
+	Fair     *FairSnapshot `json:"fair,omitempty"`
+
+	// Players are in seat order.
+	Players []PlayerSnapshot `json:"players"`
+	// Dealer is the dealer's hands (there's at most one).
+	Dealer []HandSnapshot `json:"dealer"`
+}
+
+// A PlayerSnapshot is a copy of the state of a Player, as part of a Snapshot.
+type PlayerSnapshot struct {
+	Bankroll int            `json:"bankroll"`
+	Bet      int            `json:"bet"`
+	Hands    []HandSnapshot `json:"hands"`
+}
+
+// A HandSnapshot is a copy of the entire state of a Hand, as part of a Snapshot.
+type HandSnapshot struct {
+	Cards     []playdeck.Card `json:"cards"`
+	Positions []int           `json:"positions,omitempty"`
+	Shoe      string          `json:"shoe,omitempty"`
+
+	Score    int  `json:"score"`
+	MinScore int  `json:"minScore"`
+	Locked   bool `json:"locked"`
+	Valid    bool `json:"valid"`
+
+	Wager          int  `json:"wager"`
+	Split          bool `json:"split"`
+	SplitAces      bool `json:"splitAces"`
+	Doubled        bool `json:"doubled"`
+	Surrendered    bool `json:"surrendered"`
+	EarlySurrender bool `json:"earlySurrender"`
+
+	Insurance        int  `json:"insurance"`
+	InsurancePayout  int  `json:"insurancePayout"`
+	InsuranceDecided bool `json:"insuranceDecided"`
+	EvenMoney        bool `json:"evenMoney"`
+}
+
+// A FairSnapshot is a copy of a Table's provably fair shuffling state, as part of a Snapshot. It includes secret seeds.
+type FairSnapshot struct {
+	ClientSeed string   `json:"clientSeed"`
+	NextSeed   string   `json:"nextSeed"`
+	Shoe       FairShoe `json:"shoe"`
+	ServerSeed string   `json:"serverSeed"`
+	Revealed   bool     `json:"revealed"`
+}
+
+// Snapshot returns a copy of the entire state of this Table. See Restore() to turn it back into a Table.
+func (t *Table) Snapshot() (snap Snapshot) {
+	t.Lock()
+	defer t.Unlock()
+	return t.snapshot()
+}
+
+// snapshot is the implementation behind Snapshot().
+// The table lock must be held by the caller.
+func (t *Table) snapshot() (snap Snapshot) {
+	snap = Snapshot{
+		Rules:         t.sRules,
+		Deck:          deckCards(t.Deck),
+		Discards:      deckCards(t.Discards),
+		Phase:         t.phase,
+		InsuranceOpen: t.insuranceOpen,
+		ShoeSize:      t.shoeSize,
+		ShoePos:       t.shoePos,
+		Shuffled:      t.shuffled,
+	}
+	if t.fair != nil {
+		snap.Fair = &FairSnapshot{
+			ClientSeed: t.fair.clientSeed,
+			NextSeed:   t.fair.nextSeed,
+			Shoe:       t.fair.shoe,
+			ServerSeed: t.fair.serverSeed,
+			Revealed:   t.fair.revealed,
+		}
+	}
+	for _, p := range t.Players {
+		snap.Players = append(snap.Players, p.snapshot())
+	}
+	if t.dealer != nil {
+		snap.Dealer = t.dealer.snapshot().Hands
+	}
+	return snap
+}
+
+// snapshot returns a copy of the state of this Player. The table lock must be held by the caller.
+func (p *Player) snapshot() (snap PlayerSnapshot) {
+	p.RLock()
+	defer p.RUnlock()
+	snap = PlayerSnapshot{Bankroll: p.bankroll, Bet: p.bet}
+	for _, h := range p.Hands {
+		snap.Hands = append(snap.Hands, h.snapshot())
+	}
+	return snap
+}
+
+// snapshot returns a copy of the state of this Hand.
+func (h *Hand) snapshot() (snap HandSnapshot) {
+	h.RLock()
+	defer h.RUnlock()
+	return HandSnapshot{
+		Cards:            append([]playdeck.Card{}, h.Cards...),
+		Positions:        append([]int(nil), h.positions...),
+		Shoe:             h.shoe,
+		Score:            h.score,
+		MinScore:         h.minScore,
+		Locked:           h.locked,
+		Valid:            h.valid,
+		Wager:            h.wager,
+		Split:            h.split,
+		SplitAces:        h.splitAces,
+		Doubled:          h.doubled,
+		Surrendered:      h.surrendered,
+		EarlySurrender:   h.earlySurrender,
+		Insurance:        h.insurance,
+		InsurancePayout:  h.insurancePayout,
+		InsuranceDecided: h.insuranceDecided,
+		EvenMoney:        h.evenMoney,
+	}
+}
+
+// deckCards returns a copy of the cards in the given Deck, top card first.
+func deckCards(d *playdeck.Deck) (cards []playdeck.Card) {
+	cards = []playdeck.Card{}
+	if d == nil {
+		return cards
+	}
+	d.Lock()
+	defer d.Unlock()
+	if d.Cards == nil {
+		return cards
+	}
+	return append(cards, *d.Cards...)
+}
+
+// Restore rebuilds a working Table (and its Players and Hands) from a Snapshot, after checking that the Snapshot makes sense.
+// It returns an error wrapping ErrInvalidSnapshot (or ErrInvalidRule) if it doesn't.
+// Players are new Player instances, in the same seats as they were in the Snapshot. If it's a Hand's turn to play, its
+// decision clock (see RuleSet.DecisionTimeout) starts again from the beginning.
+func Restore(snap Snapshot) (table *Table, err error) {
+	return restore(snap, false)
+}
+
+// restore is the implementation of Restore(). Tables restored for a Replayer never run a decision clock.
+func restore(snap Snapshot, replaying bool) (table *Table, err error) {
+	err = snap.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	table = newTable(snap.Rules)
+	table.replaying = replaying
+	deck := append([]playdeck.Card{}, snap.Deck...)
+	discards := append([]playdeck.Card{}, snap.Discards...)
+	table.Deck = &playdeck.Deck{Cards: &deck}
+	table.Discards = &playdeck.Deck{Cards: &discards}
+	table.phase = snap.Phase
+	table.insuranceOpen = snap.InsuranceOpen
+	table.shoeSize = snap.ShoeSize
+	table.shoePos = snap.ShoePos
+	table.shuffled = snap.Shuffled
+	if snap.Fair != nil {
+		table.fair = &fairShuffle{
+			clientSeed: snap.Fair.ClientSeed,
+			nextSeed:   snap.Fair.NextSeed,
+			shoe:       snap.Fair.Shoe,
+			serverSeed: snap.Fair.ServerSeed,
+			revealed:   snap.Fair.Revealed,
+		}
+	}
+
+	for i, ps := range snap.Players {
+		p := &Player{Table: table, seat: i, bankroll: ps.Bankroll, bet: ps.Bet}
+		p.Hands = restoreHands(table, p, ps.Hands)
+		table.Players = append(table.Players, p)
+	}
+	table.dealer.Hands = restoreHands(table, table.dealer, snap.Dealer)
+	table.turn = table.activeHand()
+	table.updateDeadline()
+	return table, nil
+}
+
+// restoreHands rebuilds the given Player's Hands from their snapshots.
+func restoreHands(t *Table, p *Player, snaps []HandSnapshot) (hands []*Hand) {
+	hands = []*Hand{}
+	for _, hs := range snaps {
+		hands = append(hands, &Hand{
+			Cards:            append([]playdeck.Card{}, hs.Cards...),
+			Table:            t,
+			Player:           p,
+			score:            hs.Score,
+			minScore:         hs.MinScore,
+			locked:           hs.Locked,
+			valid:            hs.Valid,
+			wager:            hs.Wager,
+			split:            hs.Split,
+			splitAces:        hs.SplitAces,
+			doubled:          hs.Doubled,
+			surrendered:      hs.Surrendered,
+			earlySurrender:   hs.EarlySurrender,
+			insurance:        hs.Insurance,
+			insurancePayout:  hs.InsurancePayout,
+			insuranceDecided: hs.InsuranceDecided,
+			evenMoney:        hs.EvenMoney,
+			positions:        append([]int(nil), hs.Positions...),
+			shoe:             hs.Shoe,
+		})
+	}
+	return hands
+}
+
+// validate returns an error if this Snapshot doesn't describe a Table that could exist.
+func (snap Snapshot) validate() (err error) {
+	err = snap.Rules.Validate()
+	if err != nil {
+		return err
+	}
+	if snap.Phase > PhaseIdle {
+		return fmt.Errorf("%w: unknown phase %v", ErrInvalidSnapshot, snap.Phase)
+	}
+	// Nothing outside the table can see it while it's dealing, or while the dealer's playing.
+	if snap.Phase == PhaseDealing || snap.Phase == PhaseDealerTurn {
+		return fmt.Errorf("%w: table can't be restored to the %s phase", ErrInvalidSnapshot, snap.Phase)
+	}
+	if snap.ShoeSize < 0 || snap.ShoePos < 0 {
+		return fmt.Errorf("%w: negative shoe size or position", ErrInvalidSnapshot)
+	}
+	if len(snap.Dealer) > 1 {
+		return fmt.Errorf("%w: dealer has %v hands", ErrInvalidSnapshot, len(snap.Dealer))
+	}
+	if snap.Phase != PhaseBetting && len(snap.Dealer) == 0 {
+		return fmt.Errorf("%w: round in play without a dealer hand", ErrInvalidSnapshot)
+	}
+
+	// No card can be in play more times than there are decks in the shoe.
+	counts := map[playdeck.Card]int{}
+	count := func(where string, cards []playdeck.Card) error {
+		for _, c := range cards {
+			if !c.Valid() || c.Suit == playdeck.SuitJoker || c.Value == playdeck.ValueJoker {
+				return fmt.Errorf("%w: invalid card in %s", ErrInvalidSnapshot, where)
+			}
+			counts[c]++
+			if counts[c] > snap.Rules.Decks {
+				return fmt.Errorf("%w: %s appears more than %v times", ErrInvalidSnapshot, c.Notation(), snap.Rules.Decks)
+			}
+		}
+		return nil
+	}
+	err = count("deck", snap.Deck)
+	if err != nil {
+		return err
+	}
+	err = count("discards", snap.Discards)
+	if err != nil {
+		return err
+	}
+
+	hands := snap.Dealer
+	for i, ps := range snap.Players {
+		if ps.Bankroll < 0 || ps.Bet < 0 {
+			return fmt.Errorf("%w: player %v has a negative bankroll or bet", ErrInvalidSnapshot, i)
+		}
+		hands = append(hands[:len(hands):len(hands)], ps.Hands...)
+	}
+	for i, hs := range hands {
+		where := fmt.Sprintf("hand %v", i)
+		err = count(where, hs.Cards)
+		if err != nil {
+			return err
+		}
+		if len(hs.Positions) != 0 && len(hs.Positions) != len(hs.Cards) {
+			return fmt.Errorf("%w: %s has %v shoe positions for %v cards", ErrInvalidSnapshot, where, len(hs.Positions), len(hs.Cards))
+		}
+		if hs.Wager < 0 || hs.Insurance < 0 || hs.InsurancePayout < 0 {
+			return fmt.Errorf("%w: %s has a negative wager", ErrInvalidSnapshot, where)
+		}
+		err = checkScore(hs.Cards, hs.Score, hs.MinScore, hs.Valid)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidSnapshot, where, err)
+		}
+	}
+	return
+}
@@ -0,0 +1,136 @@
+package blackjack
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"reflect"
+	"testing"
+)
+
+// snapshotTable is a helper that returns a table with two players part way through a round.
+func snapshotTable(t *testing.T) (table *Table) {
+	t.Helper()
+	rules := VegasStripRules
+	rules.Penetration = 0.5
+	table = seatedTable(t, rules, 99, 2)
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if table.PeekPending() {
+		err := table.DealerPeek()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return table
+}
+
+// Test that a table in play survives being snapshotted, marshalled and restored, and that the game carries on.
+func TestSnapshotRestore(t *testing.T) {
+	table := snapshotTable(t)
+	snap := table.Snapshot()
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Snapshot
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, snap) {
+		t.Errorf("snapshot didn't survive JSON:\n%+v\n%+v", snap, decoded)
+	}
+
+	restored, err := Restore(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored.Snapshot(), snap) {
+		t.Errorf("restored table doesn't match the snapshot")
+	}
+	if restored.Rules() != table.Rules() {
+		t.Errorf("restored table has different rules")
+	}
+
+	// Everything should be wired back up, so play carries on the same at both tables.
+	for _, tb := range []*Table{table, restored} {
+		for _, p := range tb.Players {
+			if p.Table != tb {
+				t.Fatalf("player not wired to its table")
+			}
+			for _, h := range p.Hands {
+				if h.Player != p || h.Table != tb {
+					t.Fatalf("hand not wired to its player and table")
+				}
+				for !h.locked {
+					if h.score < 17 {
+						err = h.Hit()
+					} else {
+						err = h.Stick()
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+		err = tb.EndRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = tb.Settle()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(restored.Snapshot(), table.Snapshot()) {
+		t.Errorf("restored table played out differently to the original")
+	}
+}
+
+// Test that snapshots that couldn't have come from a real table are refused.
+func TestRestoreInvalid(t *testing.T) {
+	table := snapshotTable(t)
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+
+	cases := []struct {
+		name   string
+		change func(s *Snapshot)
+	}{
+		{"bad rules", func(s *Snapshot) { s.Rules.Decks = 0 }},
+		{"bad phase", func(s *Snapshot) { s.Phase = 42 }},
+		{"dealer's turn", func(s *Snapshot) { s.Phase = PhaseDealerTurn }},
+		{"no dealer hand", func(s *Snapshot) { s.Dealer = nil }},
+		{"too many of a card", func(s *Snapshot) {
+			for i := 0; i < 5; i++ {
+				s.Discards = append(s.Discards, ace)
+			}
+		}},
+		{"fewer decks than cards", func(s *Snapshot) { s.Rules.Decks = 1 }},
+		{"joker", func(s *Snapshot) { s.Deck = append(s.Deck, playdeck.Card{}) }},
+		{"wrong score", func(s *Snapshot) { s.Players[0].Hands[0].Score++ }},
+		{"negative bankroll", func(s *Snapshot) { s.Players[1].Bankroll = -1 }},
+		{"missing positions", func(s *Snapshot) { s.Players[0].Hands[0].Positions = []int{0} }},
+	}
+	for _, c := range cases {
+		// Take a fresh copy each time, so cases don't leak into each other.
+		data, err := json.Marshal(table.Snapshot())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var snap Snapshot
+		err = json.Unmarshal(data, &snap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.change(&snap)
+		_, err = Restore(snap)
+		if !errors.Is(err, ErrInvalidSnapshot) && !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("%s: expected InvalidSnapshot got %v", c.name, err)
+		}
+	}
+}
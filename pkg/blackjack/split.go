@@ -0,0 +1,152 @@
+package blackjack
+
+// SplitRules describe when a Player may split a pair into two Hands.
+type SplitRules struct {
+	// AnyTens allows any two ten-value cards to be split (e.g. a king and a jack), rather than just true pairs.
+	AnyTens bool `json:"anyTens"`
+	// MaxHands is the most Hands a Player may hold through splitting and re-splitting.
+	// 2 allows a single split, 4 allows re-splitting up to four hands. Less than 2 disables splitting entirely.
+	MaxHands int `json:"maxHands"`
+	// ResplitAces allows a Hand made by splitting aces to be split again.
+	ResplitAces bool `json:"resplitAces"`
+	// AcesOneCard means split aces are only dealt a single card each, and are then locked out of play.
+	AcesOneCard bool `json:"acesOneCard"`
+}
+
+// DefaultSplitRules are the split rules new Tables are created with.
+var DefaultSplitRules = SplitRules{
+	AnyTens:     true,
+	MaxHands:    4,
+	ResplitAces: false,
+	AcesOneCard: true,
+}
+
+// Valid returns whether these SplitRules make sense.
+func (r SplitRules) Valid() bool {
+	return r.MaxHands >= 0
+}
+
+// SetSplitRules sets when Players may split their hands. This is a shortcut for changing a single rule with SetRules().
+// The rules cannot be changed while the table is in play.
+func (t *Table) SetSplitRules(rules SplitRules) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.Split = rules
+	})
+}
+
+// SplitRules returns the rules currently governing splits.
+func (t *Table) SplitRules() (rules SplitRules) {
+	return t.Rules().Split
+}
+
+// Split splits a pair into two Hands, moving the second card into a new Hand placed directly after this one on the same Player.
+// The new Hand is staked with the same wager (taken from the Player's bankroll), and both Hands are then dealt a card.
+// If aces are split and the table's rules only allow them one card, both Hands are locked straight away - unless a Hand is
+// dealt another ace, and the rules allow it to be re-split, in which case it can only be split again or stuck.
+// Returns the new Hand.
+func (h *Hand) Split() (hand *Hand, err error) {
+	defer h.Table.dispatchEvents()
+	defer h.Table.nextTurn()
+	err = h.canPlay()
+	if err != nil {
+		return nil, err
+	}
+	rules := h.Table.SplitRules()
+
+	h.RLock()
+	pair := h.isPair(rules.AnyTens)
+	aces := len(h.Cards) == 2 && h.Cards[0].Value == 1
+	splitAces := h.splitAces
+	wager := h.wager
+	h.RUnlock()
+
+	if !pair {
+		return nil, ErrSplitNotPair
+	}
+	if rules.MaxHands < 2 || (splitAces && !rules.ResplitAces) {
+		return nil, ErrSplitNotAllowed
+	}
+
+	hand, err = h.Player.splitHand(h, rules.MaxHands, wager)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move the second card across.
+	h.Lock()
+	hand.Lock()
+	hand.Cards = append(hand.Cards, h.Cards[1])
+	h.Cards = h.Cards[:1]
+	if len(h.positions) == 2 {
+		hand.positions = append(hand.positions, h.positions[1])
+		h.positions = h.positions[:1]
+	}
+	hand.shoe = h.shoe
+	h.split, hand.split = true, true
+	h.splitAces, hand.splitAces = aces, aces
+	hand.Unlock()
+	h.Unlock()
+	e := h.event(EventSplit)
+	e.Amount = wager
+	h.Table.emitAction(ActionSplit, e)
+
+	// Each hand now gets a second card.
+	for _, sh := range []*Hand{h, hand} {
+		err = sh.drawCard()
+		if err != nil {
+			return hand, err
+		}
+		err = sh.EvalScore()
+		if err != nil {
+			return hand, err
+		}
+		sh.emitBust()
+	}
+	if aces && rules.AcesOneCard {
+		h.Player.lockSplitAces(rules)
+	}
+	return hand, nil
+}
+
+// lockSplitAces locks every Hand made by splitting aces, once it's been dealt its one card, unless it's another pair of
+// aces that the rules allow to be re-split.
+func (p *Player) lockSplitAces(rules SplitRules) {
+	p.RLock()
+	hands := p.Hands
+	resplit := rules.ResplitAces && len(hands) < rules.MaxHands
+	p.RUnlock()
+	for _, h := range hands {
+		h.Lock()
+		if h.splitAces && len(h.Cards) == 2 && !(resplit && h.isPair(false)) {
+			h.locked = true
+		}
+		h.Unlock()
+	}
+}
+
+// oneCardAces returns true if this Hand is split aces, which the rules only allow a single card.
+// The hand lock must be held by the caller.
+func (h *Hand) oneCardAces(rules SplitRules) bool {
+	return h.splitAces && rules.AcesOneCard
+}
+
+// isPair returns whether this Hand is a pair that can be split.
+// If anyTens is set, any two ten-value cards count as a pair.
+// The hand lock must be held by the caller.
+func (h *Hand) isPair(anyTens bool) bool {
+	if len(h.Cards) != 2 {
+		return false
+	}
+	a, b := h.Cards[0].Value, h.Cards[1].Value
+	if a == b {
+		return true
+	}
+	return anyTens && a >= 10 && b >= 10
+}
+
+// WasSplit returns true if this Hand was made by splitting a pair. Thread-safe.
+func (h *Hand) WasSplit() bool {
+	h.RLock()
+	defer h.RUnlock()
+	return h.split
+}
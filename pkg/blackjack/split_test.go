@@ -0,0 +1,301 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// riggedTable is a helper that deals a single player with the given bankroll and bet into a round,
+// then replaces their hand with the given cards.
+func riggedTable(t *testing.T, bankroll int, bet int, cards ...playdeck.Card) (table *Table, player *Player) {
+	t.Helper()
+	table = NewTable(1)
+	player = NewPlayer()
+	err := table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bankroll > 0 {
+		err = player.Deposit(bankroll)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = player.PlaceBet(bet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	player.Hands[0].Cards = cards
+	err = player.Hands[0].EvalScore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table, player
+}
+
+func TestSplitRules(t *testing.T) {
+	table := NewTable(1)
+	if table.SplitRules() != DefaultSplitRules {
+		t.Errorf("new table should have default split rules, got %+v", table.SplitRules())
+	}
+	err := table.SetSplitRules(SplitRules{MaxHands: -1})
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error when setting bad rules, expected InvalidRule got %s", err)
+	}
+	rules := SplitRules{MaxHands: 2}
+	err = table.SetSplitRules(rules)
+	if err != nil {
+		t.Error(err)
+	}
+	if table.SplitRules() != rules {
+		t.Errorf("split rules not set, got %+v", table.SplitRules())
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	err = table.SetSplitRules(DefaultSplitRules)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing rules mid-game, expected TableInPlay got %s", err)
+	}
+}
+
+// Test that a simple pair splits into two hands, each staked and dealt a second card.
+func TestSplit(t *testing.T) {
+	eight := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueEight}
+	eight2 := playdeck.Card{Suit: playdeck.SuitHeart, Value: playdeck.ValueEight}
+	table, player := riggedTable(t, 100, 10, eight, eight2)
+	first := player.Hands[0]
+	if first.WasSplit() {
+		t.Errorf("hand marked as split before splitting")
+	}
+
+	second, err := first.Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.WasSplit() || !second.WasSplit() {
+		t.Errorf("both hands should be marked as split")
+	}
+	if len(player.Hands) != 2 || player.Hands[1] != second {
+		t.Fatalf("new hand not placed after the original, player has %v hands", len(player.Hands))
+	}
+	if first.Cards[0] != eight || second.Cards[0] != eight2 {
+		t.Errorf("pair not split across hands, got %v and %v", first.Cards, second.Cards)
+	}
+	if len(first.Cards) != 2 || len(second.Cards) != 2 {
+		t.Errorf("split hands should have two cards each, got %v and %v", len(first.Cards), len(second.Cards))
+	}
+	if second.Wager() != 10 || player.Bankroll() != 80 {
+		t.Errorf("split not staked correctly, expected wager 10 and bankroll 80 got %v and %v", second.Wager(), player.Bankroll())
+	}
+	if second.Player != player || second.Table != table {
+		t.Errorf("split hand not bound to the right player and table")
+	}
+
+	// Split hands play on as normal, and the round can't end until they're all done.
+	err = first.Stick()
+	if err != nil {
+		t.Error(err)
+	}
+	err = table.EndRound()
+	if !errors.Is(err, ErrHandNotLocked) {
+		t.Errorf("didn't get appropriate error when ending round with a split hand in play, expected HandNotLocked got %s", err)
+	}
+	err = second.Stick()
+	if err != nil {
+		t.Error(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Error(err)
+	}
+	results, err := table.Settle()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected both split hands to be settled, got %v results", len(results))
+	}
+}
+
+// Test that non-pairs are refused, and that the any-tens rule is honoured.
+func TestSplitNotPair(t *testing.T) {
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	jack := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueJack}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+
+	_, player := riggedTable(t, 0, 0, king, nine)
+	_, err := player.Hands[0].Split()
+	if !errors.Is(err, ErrSplitNotPair) {
+		t.Errorf("didn't get appropriate error when splitting a non-pair, expected SplitNotPair got %s", err)
+	}
+
+	_, player = riggedTable(t, 0, 0, king, jack)
+	_, err = player.Hands[0].Split()
+	if err != nil {
+		t.Errorf("couldn't split two ten-value cards with AnyTens set: %s", err)
+	}
+
+	table, player := riggedTable(t, 0, 0, king, jack)
+	table.sRules.Split.AnyTens = false
+	_, err = player.Hands[0].Split()
+	if !errors.Is(err, ErrSplitNotPair) {
+		t.Errorf("didn't get appropriate error when splitting unlike tens, expected SplitNotPair got %s", err)
+	}
+
+	table, player = riggedTable(t, 0, 0, king, king)
+	table.sRules.Split.MaxHands = 0
+	_, err = player.Hands[0].Split()
+	if !errors.Is(err, ErrSplitNotAllowed) {
+		t.Errorf("didn't get appropriate error when splitting is disabled, expected SplitNotAllowed got %s", err)
+	}
+}
+
+// Test that re-splitting stops at the limit, and that the wager must be affordable.
+func TestSplitLimits(t *testing.T) {
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+
+	table, player := riggedTable(t, 100, 10, nine, nine)
+	table.sRules.Split.MaxHands = 2
+	second, err := player.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Finish the first hand, force another pair on the second, then try to split again.
+	err = player.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.Cards = []playdeck.Card{nine, nine}
+	_, err = second.Split()
+	if !errors.Is(err, ErrSplitLimit) {
+		t.Errorf("didn't get appropriate error when re-splitting past the limit, expected SplitLimit got %s", err)
+	}
+	table.sRules.Split.MaxHands = 3
+	third, err := second.Split()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(player.Hands) != 3 || player.Hands[2] != third {
+		t.Errorf("re-split hand not placed after the hand it came from")
+	}
+
+	_, player = riggedTable(t, 10, 10, nine, nine)
+	_, err = player.Hands[0].Split()
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("didn't get appropriate error when splitting without funds, expected InsufficientFunds got %s", err)
+	}
+}
+
+// Test that split aces get one card each, can't be re-split, and don't count as naturals.
+func TestSplitAces(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+
+	_, player := riggedTable(t, 0, 0, ace, ace)
+	second, err := player.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, h := range player.Hands {
+		_, _, locked, _ := h.Score()
+		if !locked {
+			t.Errorf("split ace hand %v should be locked after one card", i)
+		}
+	}
+
+	// Even with AcesOneCard off, split aces can't be split again without ResplitAces.
+	table, player := riggedTable(t, 0, 0, ace, ace)
+	table.sRules.Split.AcesOneCard = false
+	second, err = player.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.Cards = []playdeck.Card{ace, ace}
+	_, err = second.Split()
+	if !errors.Is(err, ErrSplitNotAllowed) {
+		t.Errorf("didn't get appropriate error when re-splitting aces, expected SplitNotAllowed got %s", err)
+	}
+	table.sRules.Split.ResplitAces = true
+	_, err = second.Split()
+	if err != nil {
+		t.Errorf("couldn't re-split aces with ResplitAces set: %s", err)
+	}
+
+	second.Cards = []playdeck.Card{ace, king}
+	err = second.EvalScore()
+	if err != nil {
+		t.Error(err)
+	}
+	if second.Natural() {
+		t.Errorf("split hand of ace and king should not count as a natural")
+	}
+}
+
+// Test that split aces dealt another ace can be re-split when the rules allow it, even when aces only get one card.
+func TestSplitResplitAces(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	aceHearts := playdeck.Card{Suit: playdeck.SuitHeart, Value: playdeck.ValueAce}
+	nine := playdeck.Card{Suit: playdeck.SuitClub, Value: playdeck.ValueNine}
+	five := playdeck.Card{Suit: playdeck.SuitClub, Value: playdeck.ValueFive}
+	king := playdeck.Card{Suit: playdeck.SuitClub, Value: playdeck.ValueKing}
+
+	table, player := riggedTable(t, 100, 10, ace, ace)
+	table.sRules.Split.ResplitAces = true
+	table.Deck.Cards = &[]playdeck.Card{aceHearts, nine, five, king}
+	_, err := player.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := player.Hands[0]
+	if _, _, locked, _ := first.Score(); locked {
+		t.Fatalf("split aces dealt another ace should be left to re-split, got %v", first)
+	}
+	if _, _, locked, _ := player.Hands[1].Score(); !locked {
+		t.Errorf("split ace hand dealt a nine should be locked, got %v", player.Hands[1])
+	}
+	err = first.Hit()
+	if !errors.Is(err, ErrSplitAcesOneCard) {
+		t.Errorf("didn't get appropriate error when hitting split aces, expected SplitAcesOneCard got %s", err)
+	}
+	err = first.DoubleDown()
+	if !errors.Is(err, ErrSplitAcesOneCard) {
+		t.Errorf("didn't get appropriate error when doubling split aces, expected SplitAcesOneCard got %s", err)
+	}
+
+	_, err = first.Split()
+	if err != nil {
+		t.Fatalf("couldn't re-split aces: %s", err)
+	}
+	if len(player.Hands) != 3 {
+		t.Fatalf("expected 3 hands after re-splitting, got %v", len(player.Hands))
+	}
+	for i, h := range player.Hands {
+		if _, _, locked, _ := h.Score(); !locked || len(h.Cards) != 2 {
+			t.Errorf("split ace hand %v should be locked with two cards, got %v", i, h)
+		}
+	}
+
+	// Once the split limit's been reached, there's no re-splitting, so a pair of aces is locked like any other.
+	table, player = riggedTable(t, 100, 10, ace, ace)
+	table.sRules.Split.ResplitAces = true
+	table.sRules.Split.MaxHands = 2
+	table.Deck.Cards = &[]playdeck.Card{aceHearts, nine}
+	_, err = player.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, locked, _ := player.Hands[0].Score(); !locked {
+		t.Errorf("split aces should be locked once no more hands can be split")
+	}
+}
@@ -0,0 +1,88 @@
+package blackjack
+
+// SurrenderRule describes whether, and when, a Player may surrender a hand.
+type SurrenderRule uint8
+
+// Surrender is offered under one of these rules.
+const (
+	// SurrenderNone doesn't allow surrendering at all.
+	SurrenderNone SurrenderRule = iota
+	// SurrenderLate allows surrendering once the dealer has checked for blackjack.
+	// If the dealer never checks (see Table.SetDealerPeek()), a surrender against a dealer blackjack loses the whole wager.
+	SurrenderLate
+	// SurrenderEarly allows surrendering before the dealer checks for blackjack, so it stands even against a dealer blackjack.
+	SurrenderEarly
+)
+
+var surrenderRuleNames = map[SurrenderRule]string{
+	SurrenderNone:  "none",
+	SurrenderLate:  "late",
+	SurrenderEarly: "early",
+}
+
+// String returns a human-readable name for this rule (e.g. "late").
+// Unknown rules return "unknown".
+func (r SurrenderRule) String() string {
+	name, exists := surrenderRuleNames[r]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// SetSurrenderRule sets whether, and when, Players may surrender. This is a shortcut for changing a single rule with SetRules().
+// The rule cannot be changed while the table is in play.
+func (t *Table) SetSurrenderRule(rule SurrenderRule) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.Surrender = rule
+	})
+}
+
+// SurrenderRule returns the rule currently governing surrender.
+func (t *Table) SurrenderRule() (rule SurrenderRule) {
+	return t.Rules().Surrender
+}
+
+// Surrender gives up this Hand, locking it out of play. Half of the wager is returned to the Player when the round is settled.
+// It can only be done as the first decision on a two-card hand that hasn't been split, and only if the table's SurrenderRule allows it.
+// Under late surrender, it must wait until the dealer has checked for blackjack.
+func (h *Hand) Surrender() (err error) {
+	defer h.Table.dispatchEvents()
+	defer h.Table.nextTurn()
+	// Early surrender comes before the dealer's peek, so we can skip waiting for it.
+	rule := SurrenderNone
+	if h.Table != nil {
+		rule = h.Table.SurrenderRule()
+	}
+	err = h.checkPlay(rule == SurrenderEarly)
+	if err != nil {
+		return err
+	}
+	if rule == SurrenderNone {
+		return ErrSurrenderNotAllowed
+	}
+
+	h.Lock()
+	if len(h.Cards) != 2 {
+		h.Unlock()
+		return ErrHandNotTwoCards
+	}
+	if h.split {
+		h.Unlock()
+		return ErrSurrenderAfterSplit
+	}
+	h.surrendered = true
+	h.earlySurrender = rule == SurrenderEarly
+	h.locked = true
+	h.Unlock()
+
+	h.emitHand(EventSurrender, ActionSurrender)
+	return
+}
+
+// Surrendered returns true if this Hand has been surrendered. Thread-safe.
+func (h *Hand) Surrendered() bool {
+	h.RLock()
+	defer h.RUnlock()
+	return h.surrendered
+}
@@ -0,0 +1,184 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+func TestSurrenderRule(t *testing.T) {
+	table := NewTable(1)
+	if table.SurrenderRule() != SurrenderNone {
+		t.Errorf("new table should not offer surrender, got %s", table.SurrenderRule())
+	}
+	err := table.SetSurrenderRule(SurrenderRule(42))
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error when setting bad rule, expected InvalidRule got %s", err)
+	}
+	if SurrenderRule(42).String() != "unknown" {
+		t.Errorf("bad surrender rule returned a name of %s", SurrenderRule(42).String())
+	}
+	err = table.SetSurrenderRule(SurrenderLate)
+	if err != nil {
+		t.Error(err)
+	}
+	if table.SurrenderRule() != SurrenderLate {
+		t.Errorf("surrender rule not set, got %s", table.SurrenderRule())
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Error(errs)
+	}
+	err = table.SetSurrenderRule(SurrenderNone)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing rule mid-game, expected TableInPlay got %s", err)
+	}
+}
+
+// Test that surrendering locks the hand and refunds half the wager at settlement.
+func TestSurrender(t *testing.T) {
+	ten := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueTen}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+	seven := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSeven}
+
+	_, player := riggedTable(t, 100, 10, ten, six)
+	err := player.Hands[0].Surrender()
+	if !errors.Is(err, ErrSurrenderNotAllowed) {
+		t.Errorf("didn't get appropriate error when surrendering without the rule, expected SurrenderNotAllowed got %s", err)
+	}
+
+	table, player := riggedTable(t, 100, 10, ten, six)
+	table.sRules.Surrender = SurrenderLate
+	hand := player.Hands[0]
+	err = hand.Surrender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, _, locked, valid := hand.Score()
+	if !locked || !hand.Surrendered() {
+		t.Errorf("surrendered hand should be locked and marked surrendered")
+	}
+	if !valid {
+		t.Errorf("surrendered hand should not be marked bust")
+	}
+	err = hand.Surrender()
+	if !errors.Is(err, ErrHandLocked) {
+		t.Errorf("didn't get appropriate error when surrendering twice, expected HandLocked got %s", err)
+	}
+
+	// Settle against a dealer 17 that the hand would otherwise have lost to.
+	d := table.dealerHand()
+	d.Cards = []playdeck.Card{ten, seven}
+	err = d.EvalScore()
+	if err != nil {
+		t.Error(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Error(err)
+	}
+	results, err := table.Settle()
+	if err != nil {
+		t.Error(err)
+	}
+	if results[0].Outcome != OutcomeSurrender || results[0].Payout != 5 {
+		t.Errorf("surrender should pay back half, got %s paying %v on a score of %v", results[0].Outcome, results[0].Payout, score)
+	}
+	if player.Bankroll() != 95 {
+		t.Errorf("expected bankroll of 95 after surrender, got %v", player.Bankroll())
+	}
+}
+
+// Test that surrender is only allowed as the first decision on an unsplit hand.
+func TestSurrenderRestrictions(t *testing.T) {
+	two := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueTwo}
+	eight := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueEight}
+
+	table, player := riggedTable(t, 0, 0, two, two, two)
+	table.sRules.Surrender = SurrenderLate
+	err := player.Hands[0].Surrender()
+	if !errors.Is(err, ErrHandNotTwoCards) {
+		t.Errorf("didn't get appropriate error when surrendering three cards, expected HandNotTwoCards got %s", err)
+	}
+
+	table, player = riggedTable(t, 0, 0, eight, eight)
+	table.sRules.Surrender = SurrenderLate
+	_, err = player.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = player.Hands[0].Surrender()
+	if !errors.Is(err, ErrSurrenderAfterSplit) {
+		t.Errorf("didn't get appropriate error when surrendering a split hand, expected SurrenderAfterSplit got %s", err)
+	}
+}
+
+// Test how surrenders fare against a dealer blackjack under each rule.
+func TestSurrenderAgainstBlackjack(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+	dealer := testHand(t, ace, king)
+
+	late := testHand(t, king, six)
+	late.surrendered = true
+	if compareHands(late, dealer) != OutcomeLose {
+		t.Errorf("late surrender should not stand against a dealer blackjack")
+	}
+	early := testHand(t, king, six)
+	early.surrendered = true
+	early.earlySurrender = true
+	if compareHands(early, dealer) != OutcomeSurrender {
+		t.Errorf("early surrender should stand against a dealer blackjack")
+	}
+}
+
+// Test that early surrender is allowed while the dealer is waiting to peek, and nothing else is.
+func TestEarlySurrender(t *testing.T) {
+	ace := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+
+	table, player := riggedTable(t, 0, 0, king, six)
+	table.sRules.Surrender = SurrenderEarly
+	table.sRules.DealerPeek = true
+	d := table.dealerHand()
+	d.Cards = []playdeck.Card{ace, king}
+	err := d.EvalScore()
+	if err != nil {
+		t.Error(err)
+	}
+	// Back to straight after the deal.
+	table.phase = PhaseDealing
+	err = table.startPeek()
+	if err != nil {
+		t.Error(err)
+	}
+	if !table.PeekPending() {
+		t.Fatalf("dealer should wait to peek under early surrender")
+	}
+	err = player.Hands[0].Hit()
+	if !errors.Is(err, ErrPeekPending) {
+		t.Errorf("didn't get appropriate error when hitting before peek, expected PeekPending got %s", err)
+	}
+	err = player.Hands[0].Stick()
+	if !errors.Is(err, ErrPeekPending) {
+		t.Errorf("didn't get appropriate error when sticking before peek, expected PeekPending got %s", err)
+	}
+	err = player.Hands[0].Surrender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Everyone's surrendered, so the round can end - the dealer peeks on the way.
+	err = table.EndRound()
+	if err != nil {
+		t.Error(err)
+	}
+	outcome, err := player.Hands[0].Outcome()
+	if err != nil {
+		t.Error(err)
+	}
+	if outcome != OutcomeSurrender {
+		t.Errorf("early surrender should stand against a dealer blackjack, got %s", outcome)
+	}
+}
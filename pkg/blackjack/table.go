@@ -6,32 +6,75 @@ import (
 )
 
 // A Table represents an object that houses the overall state of a game of Blackjack.
-// Tables have a Deck of Cards to deal from, and a set of Players (with many Hands) playing at it.
+// Tables have a Deck of Cards to deal from, a dealer, and a set of Players (with many Hands) playing at it.
 type Table struct {
 	sync.Mutex
-	// The deck of cards to play from. Refilled every new game based on sDecks.
+	// The shoe of cards to play from. Kept between rounds, and replaced with a freshly shuffled one of sRules.Decks decks
+	// once the cut card (see RuleSet.Penetration) is reached.
 	Deck *playdeck.Deck
-	// The setting for the number of decks to refill the Deck with every new game.
-	sDecks int
+	// The discard tray, holding every card played since the shoe was last shuffled.
+	Discards *playdeck.Deck
+	// The settings for every house rule this table plays by, including the number of decks to refill the Deck with.
+	sRules RuleSet
 
 	// Pointers to all the Players currently playing on this Table.
 	Players []*Player
 
-	// The current state of play of the table. 0 = not in play, 1 = play in progress, 2 = (reserved, dealer playing), 3 = endgame (payouts, etc)
-	playState uint
+	// The house's Player, who is dealt an up card and a hole card every game. Never part of Players.
+	dealer *Player
+
+	// phase is the stage the current round has reached. It only changes through setPhase().
+	phase Phase
+	// insuranceOpen is true while Players may take insurance. The window closes when the dealer peeks.
+	insuranceOpen bool
+	// turn is the Hand whose turn was last announced with EventTurn, or nil.
+	turn *Hand
+	// deadline is the clock running for the current turn (see RuleSet.DecisionTimeout), or nil.
+	deadline *deadline
+	// changed is closed (and cleared) whenever the turn might have moved on, waking anyone waiting for it.
+	changed chan struct{}
+	// replaying is true for Tables rebuilt by a Replayer, which never run a clock.
+	replaying bool
+
+	// shoeSize is the number of cards the shoe held when it was last shuffled. 0 means it's never been shuffled.
+	shoeSize int
+	// shuffled is true if the shoe has been reshuffled during the current round.
+	shuffled bool
+	// source is where the shoe gets its randomness from. nil means playdeck.DefaultSource.
+	source playdeck.Source
+	// shoePos is the number of cards drawn from the shoe since it was last shuffled.
+	shoePos int
+	// fair holds the seeds for provably fair shuffling, or nil if it's not enabled.
+	fair *fairShuffle
+	// count is the card count shared with everyone at the table (see SetCount()), or nil.
+	count Count
+
+	// events queues and delivers this table's Events to its subscribers.
+	events eventBus
 }
 
-// NewTable initializes a new Table for further use.
+// NewTable initializes a new Table for further use, playing by DefaultRules with the given number of decks.
+// See NewTableWithRules() for anything more specific.
 func NewTable(decks int) (table *Table) {
+	rules := DefaultRules
+	rules.Decks = decks
+	return newTable(rules)
+}
+
+// newTable initializes a new Table playing by the given rules, which must already have been validated.
+func newTable(rules RuleSet) (table *Table) {
 	table = new(Table)
-	table.Deck = playdeck.NewDeckOfDecks(decks, false)
-	table.sDecks = decks
+	table.Deck = playdeck.NewDeckOfDecks(rules.Decks, false)
+	table.Discards = playdeck.NewDeckOfDecks(0, false)
+	table.sRules = rules
+	table.dealer = newDealer(table)
 	return table
 }
 
 // Join seats the given Player at the Table.
 // This function cannot be used and will return an error if the table is either in play, or if the Player is already known to this Table.
 func (t *Table) Join(p *Player) (err error) {
+	defer t.dispatchEvents()
 	// Lock the table
 	t.Lock()
 	defer t.Unlock()
@@ -42,8 +85,9 @@ func (t *Table) Join(p *Player) (err error) {
 
 	// Can't join a table that's in progress
 	// SwEng: this could be changed fairly easily, but it's a safety check for this implementation
-	if t.playState != 0 {
-		return ErrTableInPlay
+	err = t.checkPhase("join", ErrTableInPlay, PhaseBetting)
+	if err != nil {
+		return err
 	}
 
 	for _, tp := range t.Players {
@@ -51,37 +95,54 @@ func (t *Table) Join(p *Player) (err error) {
 			return ErrTablePlayerAlreadyJoined
 		}
 	}
+	p.seat = len(t.Players)
 	t.Players = append(t.Players, p)
 	p.Table = t
+	t.emit(Event{Type: EventPlayerJoined, Seat: p.seat, HandIndex: -1, Player: p})
 	return
 }
 
-// Reset sets the game state of a table back to 0 (pre-game).
-// It revokes all hands that each Player has.
+// Reset moves the table back to PhaseBetting, ready for the next round.
+// It revokes all hands that each Player has (moving their cards to the discard tray), settling them first if that hasn't
+// already been done.
 // It can only be called successfully if the game is not in play (SWEng: this could be changed).
 func (t *Table) Reset() (err error) {
+	defer t.dispatchEvents()
 	t.Lock()
 	defer t.Unlock()
 
-	// SWEng: Two ways to write this, the other one is (if (not valid state) and (not valid state))
-	// Both are equally tricky reads, so a comment is probably a good idea, such as:
-	// Check if the table is in a valid state to reset, throwing if it's in play or otherwise invalid
-	if !(t.playState == 3 || t.playState == 0) {
-		return ErrTableInPlay
+	err = t.checkPhase("reset", ErrTableInPlay, betweenRounds...)
+	if err != nil {
+		return err
 	}
-	for _, p := range t.Players {
-		// Clear their hands
-		p.clearHands()
+	// Nobody should lose their money just because the round wasn't explicitly settled.
+	if t.phase == PhaseSettlement {
+		_, err = t.settle()
+		if err != nil {
+			return err
+		}
+	}
+	err = t.discardHands()
+	if err != nil {
+		return err
 	}
 
-	t.playState = 0
-	return
+	t.insuranceOpen = false
+	return t.setPhase(PhaseBetting)
 }
 
-// Deal starts the game by dealing 2 cards from a new Deck into a new hand for each Player.
-// This function can only be used if the game is not in play (gameState 0 or 3).
+// Deal starts the game by dealing 2 cards from the shoe into a new hand for each Player, followed by the dealer's
+// up card and hole card. If the cut card has been reached, the shoe is reshuffled first (see Shuffled()).
+// This function can only be used if the game is not in play (see Reset()).
 // SWEng: This is a function I'd honestly like to completely reengineer because returning a slice of errors is silly
 func (t *Table) Deal() (err []error) {
+	return t.deal(false)
+}
+
+// deal is the implementation behind Deal().
+// When replaying a RoundLog, the shoe has been restored exactly as the round was dealt from, so it's used as it is.
+func (t *Table) deal(replay bool) (err []error) {
+	defer t.dispatchEvents()
 	// First ensure the game state is clean
 	e := t.Reset()
 	if e != nil {
@@ -94,11 +155,29 @@ func (t *Table) Deal() (err []error) {
 	t.Lock()
 	defer t.Unlock()
 
-	// Throw out the current deck, and create a new one.
-	// Yes, this is the equivalent of just throwing an entire pack of cards into the shredder and pulling a new one out of the box,
-	// but it works for pseudo-randomness.
-	// See README.md for further discussion.
-	t.Deck = playdeck.NewDeckOfDecks(t.sDecks, false)
+	// Reshuffle if the cut card's come out. With a penetration of 0 this happens every round, which is the equivalent of
+	// throwing the entire shoe into the shredder and pulling a new one out of the box.
+	action := t.record(Action{Type: ActionDeal, Seat: SeatDealer, HandIndex: -1})
+	if !replay {
+		t.shuffled = false
+		if t.cutCardReached() {
+			e = t.reshuffle()
+			if e != nil {
+				return append(err, e)
+			}
+		}
+	}
+	if t.shuffled {
+		t.emitTable(EventShuffle)
+	}
+	if action != nil {
+		start := t.snapshot()
+		action.start = &start
+	}
+	e = t.setPhase(PhaseDealing)
+	if e != nil {
+		return append(err, e)
+	}
 
 	// This would be pretty straight forward to switch to a goroutine for speed,
 	// and to also handle errors better.
@@ -109,6 +188,8 @@ func (t *Table) Deal() (err []error) {
 			err = append(err, e)
 			continue
 		}
+		// Stake the Player's bet on it
+		h.wager = p.takeBet()
 		// Pull two cards
 		for i := 0; i < 2; i++ {
 			e := h.addCard()
@@ -124,18 +205,34 @@ func (t *Table) Deal() (err []error) {
 			continue
 		}
 	}
-	t.playState = 1
+	// The dealer goes last - the first card is the up card, the second is the hole card.
+	err = append(err, t.dealDealer()...)
+	t.emitTable(EventRoundDealt)
+
+	// Check for a dealer blackjack, if the rules call for it, and move on to the players' turns (or insurance).
+	e = t.startPeek()
+	if e != nil {
+		err = append(err, e)
+	}
+	// Even if something went wrong, the round is under way.
+	if t.phase == PhaseDealing {
+		e = t.setPhase(PhasePlayerTurns)
+		if e != nil {
+			err = append(err, e)
+		}
+	}
 	return
 }
 
-// EndRound moves the game to the end phase.
-// It can only be used if the game is in the Play state, and no Players have any Hands that are not locked.
+// EndRound moves the game to PhaseSettlement, having the dealer play out their hand along the way.
+// It can only be used while the round is in play, and no Players have any Hands that are not locked.
 func (t *Table) EndRound() (err error) {
+	defer t.dispatchEvents()
 	t.Lock()
 	defer t.Unlock()
-	if t.playState != 1 {
-		// kinda silly way of returning a single error, but it works
-		return ErrTableNotInPlay
+	err = t.checkPhase("end the round", ErrTableNotInPlay, PhaseInsurance, PhasePlayerTurns)
+	if err != nil {
+		return err
 	}
 	// SWEng: This can easily be moved to a goroutine. It's a simple check that all players have finished playing with their hands.
 	for _, p := range t.Players {
@@ -145,8 +242,64 @@ func (t *Table) EndRound() (err error) {
 			}
 		}
 	}
+	t.record(Action{Type: ActionEndRound, Seat: SeatDealer, HandIndex: -1})
 
-	// move to endgame phase
-	t.playState = 3
+	// If the dealer is still waiting to check for blackjack (e.g. every hand was surrendered early), do it now.
+	if t.phase == PhaseInsurance {
+		err = t.peek()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Dealer's turn.
+	err = t.setPhase(PhaseDealerTurn)
+	if err != nil {
+		return err
+	}
+	err = t.playDealer()
+	if err != nil {
+		// Drop back to player turns, so that the round isn't left stranded mid-way through the dealer's turn.
+		_ = t.setPhase(PhasePlayerTurns)
+		return err
+	}
+
+	err = t.setPhase(PhaseSettlement)
+	if err != nil {
+		return err
+	}
+	t.emitTable(EventRoundEnded)
+	return
+}
+
+// dealDealer gives the dealer a new hand of two cards (or just the up card, if the rules say there's no hole card).
+// For internal use by Deal().
+// The table lock must be held by the caller.
+func (t *Table) dealDealer() (err []error) {
+	if t.dealer == nil {
+		t.dealer = newDealer(t)
+	}
+	h, e := t.dealer.newHand()
+	if e != nil {
+		return append(err, e)
+	}
+	if t.sRules.NoHoleCard {
+		// The second card comes when the dealer plays, and a single card can't be scored yet.
+		e = h.addCard()
+		if e != nil {
+			return append(err, e)
+		}
+		return
+	}
+	for i := 0; i < 2; i++ {
+		e := h.addCard()
+		if e != nil {
+			return append(err, e)
+		}
+	}
+	e = h.EvalScore()
+	if e != nil {
+		return append(err, e)
+	}
 	return
 }
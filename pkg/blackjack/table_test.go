@@ -12,8 +12,8 @@ func TestTableInitialisation(t *testing.T) {
 	if table.Deck == nil {
 		t.Errorf("no deck!")
 	}
-	if table.sDecks != 1 {
-		t.Errorf("deck count setting not set properly, expected 1 got %v", table.sDecks)
+	if table.sRules.Decks != 1 {
+		t.Errorf("deck count setting not set properly, expected 1 got %v", table.sRules.Decks)
 	}
 	if len(*table.Deck.Cards) != 52 {
 		t.Errorf("didn't get expected number of cards, expected 52 got %v", len(*table.Deck.Cards))
@@ -0,0 +1,230 @@
+package blackjack
+
+import (
+	"context"
+	"time"
+)
+
+// Tables with a DecisionTimeout give each Hand that long to make its decisions once it becomes its turn. When the time
+// runs out, the table's TimeoutAction is taken for it (announced with an EventTimeout), so that a Player who's gone
+// away can't stall the round forever. Under simultaneous play, every Hand shares a single deadline from the start of
+// the players' turns.
+
+// TimeoutAction is what a Table does for a Hand that runs out of time to decide.
+type TimeoutAction uint8
+
+// A Table can take either of these actions for a Hand that runs out of time.
+const (
+	// TimeoutStick sticks the Hand where it is.
+	TimeoutStick TimeoutAction = iota
+	// TimeoutSurrender surrenders the Hand if the table's rules allow it, and sticks it otherwise.
+	TimeoutSurrender
+)
+
+var timeoutActionNames = map[TimeoutAction]string{
+	TimeoutStick:     "stick",
+	TimeoutSurrender: "surrender",
+}
+
+// String returns a human-readable name for this action (e.g. "stick"). Unknown values return "unknown".
+func (a TimeoutAction) String() string {
+	name, exists := timeoutActionNames[a]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// deadline is the clock running for the Hand whose turn it is.
+type deadline struct {
+	timer *time.Timer
+	// hand is the Hand being timed, or nil if every Hand is (under simultaneous play).
+	hand *Hand
+	at   time.Time
+}
+
+// SetDecisionTimeout sets how long each Hand has to make its decisions once it becomes its turn. 0 (the default) gives
+// Hands as long as they like.
+// This is a shortcut for changing a single rule with SetRules(), and cannot be changed while the table is in play.
+func (t *Table) SetDecisionTimeout(timeout time.Duration) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.DecisionTimeout = timeout
+	})
+}
+
+// DecisionTimeout returns how long each Hand has to make its decisions, or 0 if there's no limit.
+func (t *Table) DecisionTimeout() time.Duration {
+	return t.Rules().DecisionTimeout
+}
+
+// SetTimeoutAction sets what the table does for a Hand that runs out of time to decide.
+// This is a shortcut for changing a single rule with SetRules(), and cannot be changed while the table is in play.
+func (t *Table) SetTimeoutAction(action TimeoutAction) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.TimeoutAction = action
+	})
+}
+
+// TimeoutAction returns what the table does for a Hand that runs out of time to decide.
+func (t *Table) TimeoutAction() TimeoutAction {
+	return t.Rules().TimeoutAction
+}
+
+// Deadline returns when the Hand whose turn it is (or, under simultaneous play, every Hand still being played) runs out
+// of time to decide. ok is false if no clock is running.
+func (t *Table) Deadline() (at time.Time, ok bool) {
+	t.Lock()
+	defer t.Unlock()
+	if t.deadline == nil {
+		return at, false
+	}
+	return t.deadline.at, true
+}
+
+// WaitForTurn blocks until it's the given Hand's turn to play (under simultaneous play, until the players' turns have
+// started), or the context is done, in which case the context's error is returned.
+// It returns ErrHandLocked if the Hand is locked before its turn comes, and a PhaseError wrapping ErrTableNotInPlay if
+// the Table has no round in play (or the players' turns are over).
+func (t *Table) WaitForTurn(ctx context.Context, h *Hand) (err error) {
+	return t.wait(ctx, func() (done bool, err error) {
+		err = t.checkPhase("wait for a turn", ErrTableNotInPlay, PhaseInsurance, PhasePlayerTurns)
+		if err != nil {
+			return false, err
+		}
+		h.RLock()
+		locked := h.locked
+		h.RUnlock()
+		if locked {
+			return false, ErrHandLocked
+		}
+		if t.phase != PhasePlayerTurns {
+			return false, nil
+		}
+		return t.sRules.SimultaneousPlay || t.activeHand() == h, nil
+	})
+}
+
+// WaitForPlayers blocks until every Hand at the Table has been played (e.g. so that EndRound() can be called), or the
+// context is done, in which case the context's error is returned. Hands that run out of time have the TimeoutAction
+// taken for them, so with a DecisionTimeout set this always returns eventually.
+// The clock doesn't run while the dealer is waiting to check for blackjack, so DealerPeek() must be called first; until
+// then this returns a PhaseError wrapping ErrPeekPending, or wrapping ErrTableNotInPlay if no round has been dealt.
+func (t *Table) WaitForPlayers(ctx context.Context) (err error) {
+	t.Lock()
+	notInPlay := ErrTableNotInPlay
+	if t.phase == PhaseInsurance {
+		notInPlay = ErrPeekPending
+	}
+	err = t.checkPhase("wait for players", notInPlay, PhasePlayerTurns)
+	t.Unlock()
+	if err != nil {
+		return err
+	}
+	return t.wait(ctx, func() (done bool, err error) {
+		return t.phase != PhasePlayerTurns || t.unlockedHands() == nil, nil
+	})
+}
+
+// wait blocks until check returns true or an error, or the context is done. check is called with the table lock held,
+// once straight away and again whenever the turn might have moved on.
+func (t *Table) wait(ctx context.Context, check func() (done bool, err error)) (err error) {
+	for {
+		t.Lock()
+		done, err := check()
+		if t.changed == nil {
+			t.changed = make(chan struct{})
+		}
+		changed := t.changed
+		t.Unlock()
+		if done || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// unlockedHands returns every Player's Hands that aren't locked yet, in seat order.
+// The table lock must be held by the caller.
+func (t *Table) unlockedHands() (hands []*Hand) {
+	for _, p := range t.Players {
+		p.RLock()
+		for _, h := range p.Hands {
+			h.RLock()
+			if !h.locked {
+				hands = append(hands, h)
+			}
+			h.RUnlock()
+		}
+		p.RUnlock()
+	}
+	return hands
+}
+
+// updateDeadline starts the clock for the Hand whose turn it is, stopping the one before it, and wakes anyone waiting on
+// the turn to move on.
+// The table lock must be held by the caller.
+func (t *Table) updateDeadline() {
+	if t.changed != nil {
+		close(t.changed)
+		t.changed = nil
+	}
+
+	timeout := t.sRules.DecisionTimeout
+	running := t.phase == PhasePlayerTurns && timeout > 0 && !t.replaying
+	hand := t.turn
+	if t.sRules.SimultaneousPlay {
+		hand = nil
+		running = running && t.unlockedHands() != nil
+	} else {
+		running = running && hand != nil
+	}
+	if t.deadline != nil && running && t.deadline.hand == hand {
+		return
+	}
+	if t.deadline != nil {
+		t.deadline.timer.Stop()
+		t.deadline = nil
+	}
+	if !running {
+		return
+	}
+	d := &deadline{hand: hand, at: time.Now().Add(timeout)}
+	d.timer = time.AfterFunc(timeout, func() {
+		t.expire(d)
+	})
+	t.deadline = d
+}
+
+// expire takes the TimeoutAction for every Hand the given deadline was timing, if it's still running.
+// The table lock may not be held by the caller.
+func (t *Table) expire(d *deadline) {
+	defer t.dispatchEvents()
+	t.Lock()
+	if t.deadline != d {
+		// The turn moved on just as the clock ran out.
+		t.Unlock()
+		return
+	}
+	t.deadline = nil
+	hands := []*Hand{d.hand}
+	if d.hand == nil {
+		hands = t.unlockedHands()
+	}
+	action := t.sRules.TimeoutAction
+	for _, h := range hands {
+		h.emitHand(EventTimeout, ActionTimeout)
+	}
+	t.Unlock()
+
+	for _, h := range hands {
+		// The Player may have got their decision in after all, in which case there's nothing left to do.
+		if action == TimeoutSurrender && h.Surrender() == nil {
+			continue
+		}
+		_ = h.Stick()
+	}
+}
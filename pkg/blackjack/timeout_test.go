@@ -0,0 +1,311 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"sync"
+	"testing"
+	"time"
+)
+
+// timeoutTable is a helper that returns a table with two players, giving each hand the given time to decide, ready to be
+// dealt.
+func timeoutTable(t *testing.T, timeout time.Duration, action TimeoutAction) (table *Table) {
+	t.Helper()
+	rules := DefaultRules
+	rules.Decks = 6
+	rules.DecisionTimeout = timeout
+	rules.TimeoutAction = action
+	return seatedTable(t, rules, 1234, 2)
+}
+
+// timeoutEvents is a helper that collects the Events of the given types published by a table, which may come from the
+// clock's goroutine.
+func timeoutEvents(table *Table, types ...EventType) (events func() []Event, unsubscribe func()) {
+	var mu sync.Mutex
+	var seen []Event
+	unsubscribe = table.Subscribe(func(e Event) {
+		for _, typ := range types {
+			if e.Type == typ {
+				mu.Lock()
+				seen = append(seen, e)
+				mu.Unlock()
+			}
+		}
+	})
+	return func() []Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Event(nil), seen...)
+	}, unsubscribe
+}
+
+// Test that hands that run out of time are stuck, one after the other, and announced.
+func TestTimeoutStick(t *testing.T) {
+	table := timeoutTable(t, 10*time.Millisecond, TimeoutStick)
+	events, unsubscribe := timeoutEvents(table, EventTimeout, EventStick)
+	defer unsubscribe()
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if _, ok := table.Deadline(); !ok {
+		t.Errorf("expected the first hand's clock to be running")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := table.WaitForPlayers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := table.Deadline(); ok {
+		t.Errorf("expected no clock once every hand has been played")
+	}
+
+	expected := []struct {
+		typ  EventType
+		seat int
+	}{{EventTimeout, 0}, {EventStick, 0}, {EventTimeout, 1}, {EventStick, 1}}
+	got := events()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v events, got %v", len(expected), got)
+	}
+	for i, e := range expected {
+		if got[i].Type != e.typ || got[i].Seat != e.seat {
+			t.Errorf("event %v: expected %s for seat %v, got %s for seat %v", i, e.typ, e.seat, got[i].Type, got[i].Seat)
+		}
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that hands that run out of time are surrendered if the rules allow it, all at once under simultaneous play.
+func TestTimeoutSurrender(t *testing.T) {
+	table := timeoutTable(t, 10*time.Millisecond, TimeoutSurrender)
+	err := table.SetSurrenderRule(SurrenderLate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.SetSimultaneousPlay(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, unsubscribe := timeoutEvents(table, EventTimeout, EventSurrender, EventStick)
+	defer unsubscribe()
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = table.WaitForPlayers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EventType{EventTimeout, EventTimeout, EventSurrender, EventSurrender}
+	got := events()
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v events, got %v", len(expected), got)
+	}
+	for i, typ := range expected {
+		if got[i].Type != typ {
+			t.Errorf("event %v: expected %s, got %s", i, typ, got[i].Type)
+		}
+	}
+}
+
+// Test that a decision made in time stops the clock, and that nothing is done once the turn has moved on.
+func TestTimeoutBeaten(t *testing.T) {
+	table := timeoutTable(t, 50*time.Millisecond, TimeoutStick)
+	events, unsubscribe := timeoutEvents(table, EventTimeout)
+	defer unsubscribe()
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	first, _ := table.Deadline()
+	err := table.Players[0].Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, ok := table.Deadline()
+	if !ok || !second.After(first) {
+		t.Errorf("expected a fresh clock for the second hand")
+	}
+	err = table.Players[1].Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := events(); len(got) != 0 {
+		t.Errorf("expected no timeouts, got %v", got)
+	}
+}
+
+// Test waiting for a turn, and giving up on it.
+func TestTimeoutWait(t *testing.T) {
+	table := timeoutTable(t, 0, TimeoutStick)
+	err := table.WaitForPlayers(context.Background())
+	if !errors.Is(err, ErrTableNotInPlay) {
+		t.Errorf("didn't get appropriate error when waiting before the deal, expected TableNotInPlay got %s", err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if _, ok := table.Deadline(); ok {
+		t.Errorf("expected no clock without a decision timeout")
+	}
+	first, second := table.Players[0].Hands[0], table.Players[1].Hands[0]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = table.WaitForTurn(ctx, second)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("didn't get appropriate error when giving up on a turn, expected Canceled got %s", err)
+	}
+	err = table.WaitForTurn(context.Background(), first)
+	if err != nil {
+		t.Errorf("expected the first hand's turn to have come, got %s", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- table.WaitForTurn(context.Background(), second)
+	}()
+	err = first.Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Errorf("expected the second hand's turn to come, got %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the second hand's turn")
+	}
+	err = table.WaitForTurn(context.Background(), first)
+	if !errors.Is(err, ErrHandLocked) {
+		t.Errorf("didn't get appropriate error when waiting on a played hand, expected HandLocked got %s", err)
+	}
+}
+
+// Test that waiting for players while the dealer's peek is pending fails straight away rather than blocking, since the
+// clock doesn't run until the peek, and that the wait finishes once it's done.
+func TestTimeoutPeekPending(t *testing.T) {
+	six := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueSix}
+	king := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueKing}
+	nine := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueNine}
+
+	table := insuranceTable(t, six, []playdeck.Card{king, nine})
+	table.sRules.DecisionTimeout = 10 * time.Millisecond
+	if table.CurrentPhase() != PhaseInsurance {
+		t.Fatalf("expected insurance after dealing an ace, got %s", table.CurrentPhase())
+	}
+	if _, ok := table.Deadline(); ok {
+		t.Errorf("expected no clock while the peek is pending")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := table.WaitForPlayers(ctx)
+	var phaseErr *PhaseError
+	if !errors.As(err, &phaseErr) || !errors.Is(err, ErrPeekPending) {
+		t.Errorf("didn't get appropriate error when waiting before the peek, expected a PhaseError wrapping PeekPending got %s", err)
+	}
+
+	err = table.DealerPeek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.WaitForPlayers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, locked, _ := table.Players[0].Hands[0].Score(); !locked {
+		t.Errorf("expected the hand to be stuck once its time ran out")
+	}
+}
+
+// Test that timeouts are recorded, and replay without a clock.
+func TestTimeoutReplay(t *testing.T) {
+	table := timeoutTable(t, 10*time.Millisecond, TimeoutStick)
+	recorder := table.Record()
+	defer recorder.Stop()
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := table.WaitForPlayers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	round := recorder.Rounds()[0]
+	timeouts := 0
+	for _, a := range round.Actions {
+		if a.Type == ActionTimeout {
+			timeouts++
+		}
+	}
+	if timeouts != 2 {
+		t.Errorf("expected 2 timeouts to be recorded, got %v", timeouts)
+	}
+	r, err := NewReplayer(round)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Seek(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Table().Deadline(); ok {
+		t.Errorf("expected no clock while replaying")
+	}
+	err = r.Seek(r.Len())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that decision timeouts are validated, and can't change mid-round.
+func TestTimeoutRules(t *testing.T) {
+	rules := DefaultRules
+	rules.DecisionTimeout = -time.Second
+	_, err := NewTableWithRules(rules)
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error with a negative timeout, expected InvalidRule got %s", err)
+	}
+	rules.DecisionTimeout = time.Second
+	rules.TimeoutAction = TimeoutAction(42)
+	_, err = NewTableWithRules(rules)
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error with an unknown timeout action, expected InvalidRule got %s", err)
+	}
+
+	table := timeoutTable(t, 0, TimeoutStick)
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	err = table.SetDecisionTimeout(time.Second)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing the timeout mid-round, expected TableInPlay got %s", err)
+	}
+	if TimeoutAction(42).String() != "unknown" {
+		t.Errorf("expected \"unknown\", got %q", TimeoutAction(42).String())
+	}
+}
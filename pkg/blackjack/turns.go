@@ -0,0 +1,103 @@
+package blackjack
+
+import "fmt"
+
+// Players take their turns in seat order, playing each of their Hands in order (including any split off along the way)
+// until it's locked - by sticking, going bust, doubling down, surrendering, and so on. Only the active Hand may be played;
+// the others get ErrNotYourTurn. Decisions made before the dealer's peek (insurance and early surrender) can be made in
+// any order, as can everything under simultaneous play.
+
+// SetSimultaneousPlay sets whether Players may play their hands in any order, as in simultaneous-play variants, rather
+// than strictly in seat order.
+// This is a shortcut for changing a single rule with SetRules(), and cannot be changed while the table is in play.
+func (t *Table) SetSimultaneousPlay(simultaneous bool) (err error) {
+	return t.updateRules(func(r *RuleSet) {
+		r.SimultaneousPlay = simultaneous
+	})
+}
+
+// SimultaneousPlay returns whether Players may play their hands in any order.
+func (t *Table) SimultaneousPlay() bool {
+	return t.Rules().SimultaneousPlay
+}
+
+// ActiveHand returns the Hand whose turn it is, or nil if it's nobody's turn (because the players' turns haven't started
+// or are over, or under simultaneous play). Its Player is the active Player.
+func (t *Table) ActiveHand() (hand *Hand) {
+	t.Lock()
+	defer t.Unlock()
+	return t.activeHand()
+}
+
+// activeHand is the implementation behind ActiveHand(): the first Hand, in seat order, that isn't locked yet.
+// The table lock must be held by the caller.
+func (t *Table) activeHand() (hand *Hand) {
+	if t.phase != PhasePlayerTurns || t.sRules.SimultaneousPlay {
+		return nil
+	}
+	for _, p := range t.Players {
+		p.RLock()
+		for _, h := range p.Hands {
+			h.RLock()
+			locked := h.locked
+			h.RUnlock()
+			if !locked {
+				p.RUnlock()
+				return h
+			}
+		}
+		p.RUnlock()
+	}
+	return nil
+}
+
+// updateTurn publishes an EventTurn if the active Hand has changed since it was last checked, and keeps the decision
+// clock in step with it.
+// The table lock must be held by the caller.
+func (t *Table) updateTurn() {
+	defer t.updateDeadline()
+	h := t.activeHand()
+	if h == t.turn {
+		return
+	}
+	t.turn = h
+	if h != nil {
+		t.emit(h.event(EventTurn))
+	}
+}
+
+// nextTurn moves the turn on if the active Hand has been locked. Hand actions defer it, so that it runs once they're done.
+// The table lock may not be held by the caller.
+func (t *Table) nextTurn() {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.updateTurn()
+}
+
+// checkTurn returns an error wrapping ErrNotYourTurn if it's another Hand's turn to play. Hands that are already locked
+// are left for the caller to deal with.
+// Neither the table, Player nor Hand locks may be held by the caller.
+func (h *Hand) checkTurn() (err error) {
+	h.RLock()
+	t := h.Table
+	locked := h.locked
+	h.RUnlock()
+	if t == nil || locked {
+		return nil
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if t.phase != PhasePlayerTurns || t.sRules.SimultaneousPlay {
+		return nil
+	}
+	active := t.activeHand()
+	if active == nil || active == h {
+		return nil
+	}
+	e := active.event(EventTurn)
+	return fmt.Errorf("%w: it's the turn of hand %v in seat %v", ErrNotYourTurn, e.HandIndex, e.Seat)
+}
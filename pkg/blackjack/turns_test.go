@@ -0,0 +1,142 @@
+package blackjack
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// turnsTable is a helper that returns a table with the given number of players, dealt and ready to play, with every
+// player holding a pair of eights.
+func turnsTable(t *testing.T, players int) (table *Table) {
+	t.Helper()
+	eight := playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueEight}
+	rules := DefaultRules
+	rules.Decks = 6
+	table = seatedTable(t, rules, 1, players)
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	for _, p := range table.Players {
+		h := p.Hands[0]
+		h.Cards = []playdeck.Card{eight, eight}
+		err := h.EvalScore()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return table
+}
+
+// Test that hands are played in seat order, and that the turn moves on as each is locked.
+func TestTurnOrder(t *testing.T) {
+	table := turnsTable(t, 2)
+	first, second := table.Players[0], table.Players[1]
+
+	if table.ActiveHand() != first.Hands[0] {
+		t.Fatalf("expected the first player's hand to be active")
+	}
+	err := second.Hands[0].Hit()
+	if !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("didn't get appropriate error when hitting out of turn, expected NotYourTurn got %s", err)
+	}
+	err = second.Hands[0].Stick()
+	if !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("didn't get appropriate error when sticking out of turn, expected NotYourTurn got %s", err)
+	}
+	if _, _, locked, _ := second.Hands[0].Score(); locked {
+		t.Errorf("out of turn stick locked the hand")
+	}
+
+	// Splitting adds a hand that's played straight after the one it came from.
+	split, err := first.Hands[0].Split()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = split.Stick()
+	if !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("didn't get appropriate error when playing a split hand early, expected NotYourTurn got %s", err)
+	}
+	err = first.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.ActiveHand() != split {
+		t.Errorf("expected the split hand to be active next")
+	}
+	err = split.Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.ActiveHand() != second.Hands[0] {
+		t.Errorf("expected the second player's hand to be active next")
+	}
+	err = second.Hands[0].Stick()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.ActiveHand() != nil {
+		t.Errorf("expected nobody's turn once every hand is locked")
+	}
+	err = table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that every change of turn is published.
+func TestTurnEvents(t *testing.T) {
+	table := turnsTable(t, 2)
+	var turns []*Hand
+	unsubscribe := table.Subscribe(func(e Event) {
+		if e.Type == EventTurn {
+			turns = append(turns, e.Hand)
+		}
+	})
+	defer unsubscribe()
+
+	for _, p := range table.Players {
+		err := p.Hands[0].Stick()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first player's turn was announced by the deal.
+	if len(turns) != 1 || turns[0] != table.Players[1].Hands[0] {
+		t.Errorf("expected a single turn event for the second player, got %v", turns)
+	}
+}
+
+// Test that simultaneous play lets hands be played in any order.
+func TestTurnSimultaneous(t *testing.T) {
+	table := NewTable(1)
+	err := table.SetSimultaneousPlay(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !table.SimultaneousPlay() {
+		t.Errorf("simultaneous play not set")
+	}
+	for i := 0; i < 2; i++ {
+		err = table.Join(NewPlayer())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if table.ActiveHand() != nil {
+		t.Errorf("expected no active hand under simultaneous play")
+	}
+	err = table.Players[1].Hands[0].Stick()
+	if err != nil {
+		t.Errorf("couldn't play out of seat order under simultaneous play: %s", err)
+	}
+	err = table.SetSimultaneousPlay(false)
+	if !errors.Is(err, ErrTableInPlay) {
+		t.Errorf("didn't get appropriate error when changing turn order mid-round, expected TableInPlay got %s", err)
+	}
+}
@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/counting"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"math"
+)
+
+// A Bot plays blackjack. It's shown a View of the Table, and decides how much to bet and what to do with each Hand.
+// A Driver plays a Bot at a Table.
+type Bot interface {
+	// Bet returns how much to bet on the next round. 0 sits it out (though the Player is still dealt in, with nothing
+	// riding on the Hand).
+	Bet(v View) int
+	// Play returns what to do with the Hand being decided (v.Hands[v.Hand]).
+	Play(v View) strategy.Action
+}
+
+// AlwaysStand never draws a card, betting Amount every round.
+type AlwaysStand struct {
+	Amount int
+}
+
+// Bet returns the flat bet.
+func (b AlwaysStand) Bet(View) int {
+	return b.Amount
+}
+
+// Play always stands.
+func (b AlwaysStand) Play(View) strategy.Action {
+	return strategy.ActionStand
+}
+
+// MimicDealer plays like the dealer has to, hitting until it reaches 17 (and hitting soft 17 if the dealer does),
+// betting Amount every round.
+type MimicDealer struct {
+	Amount int
+}
+
+// Bet returns the flat bet.
+func (b MimicDealer) Bet(View) int {
+	return b.Amount
+}
+
+// Play hits or stands by the dealer's rule.
+func (b MimicDealer) Play(v View) strategy.Action {
+	h := v.Hands[v.Hand]
+	if h.Score < 17 || (h.Score == 17 && h.Soft && v.Rules.DealerRule == blackjack.DealerHitsSoft17) {
+		return strategy.ActionHit
+	}
+	return strategy.ActionStand
+}
+
+// BasicStrategy plays by a basic strategy Chart (strategy.Basic if nil), betting Amount every round.
+type BasicStrategy struct {
+	Amount int
+	Chart  *strategy.Chart
+}
+
+// Bet returns the flat bet.
+func (b BasicStrategy) Bet(View) int {
+	return b.Amount
+}
+
+// Play returns what the Chart says.
+func (b BasicStrategy) Play(v View) strategy.Action {
+	return decide(b.Chart, v)
+}
+
+// DefaultRamp is the bet spread Counting uses if it isn't given one: one unit up to a true count of +1, then 2, 4, 6
+// and 8 units from +2 to +5 and over.
+var DefaultRamp = []int{1, 1, 2, 4, 6, 8}
+
+// Counting plays basic strategy, and counts the cards it's seen to spread its bets: the more high cards left in the
+// shoe, the more it bets.
+type Counting struct {
+	// System is the counting System to count by. nil means counting.HiLo.
+	System *counting.System
+	// Chart is the basic strategy Chart to play by. nil means strategy.Basic.
+	Chart *strategy.Chart
+	// Unit is the smallest bet.
+	Unit int
+	// Ramp is the number of Units to bet at each true count, starting from 0 (or less). Counts past the end of the Ramp
+	// bet its last entry. Unbalanced systems use their running count instead. nil means DefaultRamp.
+	Ramp []int
+}
+
+// Bet spreads the bet by the count.
+func (b Counting) Bet(v View) int {
+	ramp := b.Ramp
+	if len(ramp) == 0 {
+		ramp = DefaultRamp
+	}
+	step := int(math.Floor(b.Count(v)))
+	step = min(max(step, 0), len(ramp)-1)
+	return b.Unit * ramp[step]
+}
+
+// Play returns what the Chart says.
+func (b Counting) Play(v View) strategy.Action {
+	return decide(b.Chart, v)
+}
+
+// Count returns the count this Bot bets by: the true count of the cards it's seen, or the running count for unbalanced
+// systems.
+func (b Counting) Count(v View) float64 {
+	system := b.System
+	if system == nil {
+		system = counting.HiLo
+	}
+	running := system.InitialCount(v.Rules.Decks)
+	for _, card := range v.Seen {
+		running += system.Tag(card)
+	}
+	if !system.Balanced() {
+		return running
+	}
+	return running / v.DecksRemaining()
+}
+
+// decide returns what a Chart (strategy.Basic if nil) says to do with the Hand being decided.
+func decide(chart *strategy.Chart, v View) strategy.Action {
+	if chart == nil {
+		chart = strategy.Basic
+	}
+	return chart.Decide(v.Situation())
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/counting"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"testing"
+)
+
+// botView is a helper that builds a View deciding a single Hand of the given cards against an up card.
+func botView(t *testing.T, rules blackjack.RuleSet, cards string, up string) (v View) {
+	t.Helper()
+	hand, err := playdeck.ParseCards(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upCard, err := playdeck.ParseCard(up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, soft := 0, false
+	aces := false
+	for _, c := range hand {
+		v := min(c.Value.Value(), 10)
+		aces = aces || c.Value == playdeck.ValueAce
+		score += v
+	}
+	if aces && score+10 <= 21 {
+		score, soft = score+10, true
+	}
+	return View{Rules: rules, Hands: []HandView{{Cards: hand, Score: score, Soft: soft}}, UpCard: &upCard,
+		CardsRemaining: rules.Decks * 52}
+}
+
+// Test the built-in Bots' decisions.
+func TestBotPlay(t *testing.T) {
+	s17 := blackjack.VegasStripRules
+	h17 := s17
+	h17.DealerRule = blackjack.DealerHitsSoft17
+	cases := []struct {
+		name   string
+		bot    Bot
+		rules  blackjack.RuleSet
+		cards  string
+		up     string
+		action strategy.Action
+	}{
+		{"always stand on 5", AlwaysStand{}, s17, "2S 3H", "TD", strategy.ActionStand},
+		{"mimic hits 16", MimicDealer{}, s17, "TS 6H", "7D", strategy.ActionHit},
+		{"mimic stands on 17", MimicDealer{}, s17, "TS 7H", "7D", strategy.ActionStand},
+		{"mimic stands on soft 17", MimicDealer{}, s17, "AS 6H", "7D", strategy.ActionStand},
+		{"mimic hits soft 17 under H17", MimicDealer{}, h17, "AS 6H", "7D", strategy.ActionHit},
+		{"basic doubles 11", BasicStrategy{}, s17, "5S 6H", "6D", strategy.ActionDouble},
+		{"basic splits eights", BasicStrategy{}, s17, "8S 8H", "TD", strategy.ActionSplit},
+		{"counting plays basic", Counting{}, s17, "TS 6H", "TD", strategy.ActionSurrender},
+	}
+	for _, c := range cases {
+		if action := c.bot.Play(botView(t, c.rules, c.cards, c.up)); action != c.action {
+			t.Errorf("%s: expected %s, got %s", c.name, c.action, action)
+		}
+	}
+}
+
+// Test that the counting Bot spreads its bets by the count of the cards it's seen.
+func TestBotCountingBet(t *testing.T) {
+	rules := blackjack.VegasStripRules
+	bot := Counting{Unit: 5}
+	v := botView(t, rules, "TS 6H", "TD")
+
+	if bet := bot.Bet(v); bet != 5 {
+		t.Errorf("expected a bet of 5 at a count of 0, got %v", bet)
+	}
+	// Twelve low cards with two decks left is a true count of +6.
+	low, err := playdeck.ParseCards("2S 3S 4S 5S 6S 2H 3H 4H 5H 6H 2D 3D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Seen, v.CardsRemaining = low, 104
+	if count := bot.Count(v); count != 6 {
+		t.Errorf("expected a true count of +6, got %v", count)
+	}
+	if bet := bot.Bet(v); bet != 40 {
+		t.Errorf("expected a bet of 40 (8 units) at +6, got %v", bet)
+	}
+	// Half as many decks left makes it +12, which is still the top of the ramp.
+	v.CardsRemaining = 52
+	if bet := bot.Bet(v); bet != 40 {
+		t.Errorf("expected a bet of 40 (8 units) at +12, got %v", bet)
+	}
+	// A ramp of its own.
+	bot.Ramp = []int{0, 1, 3}
+	v.Seen = low[:1]
+	if bet := bot.Bet(v); bet != 5 {
+		t.Errorf("expected a bet of 5 (1 unit) at +1 off the custom ramp, got %v", bet)
+	}
+	v.Seen = nil
+	if bet := bot.Bet(v); bet != 0 {
+		t.Errorf("expected to sit out at 0 with the custom ramp, got %v", bet)
+	}
+
+	// KO is unbalanced, so its running count is used as it is: with four decks it starts at -12.
+	ko := Counting{System: counting.KO, Unit: 5}
+	if count := ko.Count(v); count != -12 {
+		t.Errorf("expected a KO count of -12, got %v", count)
+	}
+}
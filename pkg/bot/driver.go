@@ -0,0 +1,225 @@
+package bot
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"sync"
+)
+
+// A Driver plays a Bot's Player at a Table, without anyone having to ask it to: it plays each of the Player's Hands when
+// it's their turn (or as soon as the dealer's peeked, under simultaneous play), and places the Bot's next bet once each
+// round's settled. Dealing, peeking and ending rounds are still up to whoever runs the Table.
+// Insurance and even money are never taken.
+// Safe for use in asynchronous environments.
+type Driver struct {
+	sync.Mutex
+	table  *blackjack.Table
+	player *blackjack.Player
+	bot    Bot
+
+	// seen is every card shown since the shoe was shuffled, and hole is true once this round's hole card has been seen.
+	seen []playdeck.Card
+	hole bool
+	// err is the last error the Driver ran into.
+	err error
+	// stop unsubscribes the Driver from the Table, or is nil if it isn't running.
+	stop func()
+}
+
+// NewDriver returns a Driver that plays the given Bot for a Player seated at the Table. It doesn't start playing until
+// Start() is called.
+// It returns ErrNoBot without a Bot, and ErrPlayerNotSeated if the Player isn't at the Table.
+func NewDriver(t *blackjack.Table, p *blackjack.Player, b Bot) (driver *Driver, err error) {
+	if b == nil {
+		return nil, ErrNoBot
+	}
+	if t == nil || p == nil {
+		return nil, ErrPlayerNotSeated
+	}
+	p.RLock()
+	seated := p.Table == t
+	p.RUnlock()
+	if !seated {
+		return nil, ErrPlayerNotSeated
+	}
+	return &Driver{table: t, player: p, bot: b}, nil
+}
+
+// Start starts playing, placing the Bot's first bet straight away (unless the Table is in play, in which case it waits
+// for the round to be settled). Cards dealt before the Driver starts aren't seen.
+// It returns ErrDriverRunning if the Driver's already been started, and any error placing the bet.
+func (d *Driver) Start() (err error) {
+	d.Lock()
+	if d.stop != nil {
+		d.Unlock()
+		return ErrDriverRunning
+	}
+	d.stop = d.table.Subscribe(d.event)
+	d.Unlock()
+
+	if d.table.CurrentPhase() == blackjack.PhaseBetting || d.table.CurrentPhase() == blackjack.PhaseIdle {
+		return d.bet()
+	}
+	return nil
+}
+
+// Stop stops playing. Any bet already placed stays on the Table.
+func (d *Driver) Stop() {
+	d.Lock()
+	defer d.Unlock()
+	if d.stop != nil {
+		d.stop()
+		d.stop = nil
+	}
+}
+
+// Err returns the last error the Driver ran into while playing, such as the Bot choosing an Action the rules don't allow
+// (the Hand is stuck instead), or betting more than its bankroll (the round is sat out). nil means there haven't been any.
+func (d *Driver) Err() error {
+	d.Lock()
+	defer d.Unlock()
+	return d.err
+}
+
+// Seen returns every card the Driver has seen at the Table since the shoe was shuffled.
+func (d *Driver) Seen() (cards []playdeck.Card) {
+	d.Lock()
+	defer d.Unlock()
+	return append([]playdeck.Card(nil), d.seen...)
+}
+
+// fail records an error the Driver ran into.
+func (d *Driver) fail(err error) {
+	d.Lock()
+	defer d.Unlock()
+	d.err = err
+}
+
+// event handles an Event at the Table. Events are delivered one at a time, without any of the table's locks held, so
+// the Driver can play straight from here. Anything it does is delivered once it's returned.
+func (d *Driver) event(e blackjack.Event) {
+	switch e.Type {
+	case blackjack.EventShuffle:
+		d.Lock()
+		d.seen = nil
+		d.Unlock()
+	case blackjack.EventCardDealt:
+		if e.Card != nil && !e.Hidden {
+			d.see(*e.Card, false)
+		}
+	case blackjack.EventRoundDealt:
+		d.Lock()
+		d.hole = false
+		d.Unlock()
+		d.playAll()
+	case blackjack.EventDealerPeek:
+		if e.Card != nil {
+			d.see(*e.Card, true)
+		}
+		d.playAll()
+	case blackjack.EventDealerTurn:
+		if e.Card != nil {
+			d.see(*e.Card, true)
+		}
+	case blackjack.EventTurn:
+		if e.Player == d.player {
+			d.play(e.Hand)
+		}
+	case blackjack.EventSettled:
+		// Bet again once the last of the Player's Hands has been settled.
+		if e.Player == d.player && e.HandIndex == d.hands()-1 {
+			d.report(d.bet())
+		}
+	}
+}
+
+// see adds a card to those seen. The hole card is only added the first time it's shown.
+func (d *Driver) see(card playdeck.Card, hole bool) {
+	d.Lock()
+	defer d.Unlock()
+	if hole {
+		if d.hole {
+			return
+		}
+		d.hole = true
+	}
+	d.seen = append(d.seen, card)
+}
+
+// report records an error, if there is one.
+func (d *Driver) report(err error) {
+	if err != nil {
+		d.fail(err)
+	}
+}
+
+// hands returns the number of Hands the Player holds.
+func (d *Driver) hands() int {
+	d.player.RLock()
+	defer d.player.RUnlock()
+	return len(d.player.Hands)
+}
+
+// view returns a View of the Table, deciding the given Hand (or -1 when betting).
+func (d *Driver) view(hand int) View {
+	return newView(d.table, d.player, hand, d.Seen())
+}
+
+// bet places the Bot's bet for the next round.
+func (d *Driver) bet() (err error) {
+	amount := d.bot.Bet(d.view(-1))
+	err = d.player.PlaceBet(amount)
+	if err != nil {
+		return fmt.Errorf("betting %v: %w", amount, err)
+	}
+	return nil
+}
+
+// playAll plays every one of the Player's Hands, if the Table plays simultaneously and it's time to.
+func (d *Driver) playAll() {
+	if !d.table.SimultaneousPlay() || d.table.CurrentPhase() != blackjack.PhasePlayerTurns {
+		return
+	}
+	// Splitting adds Hands as we go.
+	for i := 0; i < d.hands(); i++ {
+		d.player.RLock()
+		h := d.player.Hands[i]
+		d.player.RUnlock()
+		d.play(h)
+	}
+}
+
+// play plays a Hand with the Bot until it's locked. If the Bot chooses something the Table won't do, the error is
+// recorded and the Hand is stuck, so that the Table isn't left waiting.
+func (d *Driver) play(h *blackjack.Hand) {
+	for {
+		if _, _, locked, _ := h.Score(); locked {
+			return
+		}
+		action := d.bot.Play(d.view(d.index(h)))
+		err := action.Play(h)
+		if err == nil {
+			continue
+		}
+		d.fail(fmt.Errorf("%s: %w", action, err))
+		err = strategy.ActionStand.Play(h)
+		if err != nil {
+			d.fail(fmt.Errorf("%s: %w", strategy.ActionStand, err))
+			return
+		}
+	}
+}
+
+// index returns the index of a Hand among the Player's, or -1 if it isn't one of theirs.
+func (d *Driver) index(h *blackjack.Hand) int {
+	d.player.RLock()
+	defer d.player.RUnlock()
+	for i, ph := range d.player.Hands {
+		if ph == h {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,238 @@
+package bot
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"testing"
+)
+
+// driverTable is a helper that seats a Player for each Bot at a Table playing by the given rules and shuffling from the
+// given seed, and starts a Driver for each.
+func driverTable(t *testing.T, rules blackjack.RuleSet, seed int64, bots ...Bot) (table *blackjack.Table, players []*blackjack.Player, drivers []*Driver) {
+	t.Helper()
+	table, err := blackjack.NewTableWithRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.SetSource(playdeck.NewSeededSource(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range bots {
+		p := blackjack.NewPlayer()
+		err = p.Deposit(100000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = table.Join(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := NewDriver(table, p, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = d.Start()
+		if err != nil {
+			t.Fatal(err)
+		}
+		players = append(players, p)
+		drivers = append(drivers, d)
+	}
+	return table, players, drivers
+}
+
+// driverRound is a helper that deals a round, lets the Drivers play it, and ends and settles it.
+func driverRound(t *testing.T, table *blackjack.Table) {
+	t.Helper()
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if table.PeekPending() {
+		err := table.DealerPeek()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Every Hand should have been played by now.
+	err := table.EndRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = table.Settle()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that Drivers play every Hand and bet every round, in seat order and under simultaneous play.
+func TestDriverPlays(t *testing.T) {
+	for _, simultaneous := range []bool{false, true} {
+		rules := blackjack.VegasStripRules
+		rules.SimultaneousPlay = simultaneous
+		table, players, drivers := driverTable(t, rules, 1234,
+			AlwaysStand{Amount: 10}, MimicDealer{Amount: 20}, BasicStrategy{Amount: 30}, Counting{Unit: 10})
+		for i, p := range players[:3] {
+			if p.Bet() != (i+1)*10 {
+				t.Errorf("expected seat %v to bet %v straight away, got %v", i, (i+1)*10, p.Bet())
+			}
+		}
+
+		split := false
+		for round := 0; round < 100; round++ {
+			driverRound(t, table)
+			for _, h := range players[0].Hands {
+				if len(h.Cards) != 2 {
+					t.Errorf("expected always stand to keep two cards, got %s", h)
+				}
+			}
+			dealer, err := table.DealerHand()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, h := range players[1].Hands {
+				// A dealer blackjack ends the round before anyone plays.
+				score, _, _, valid := h.Score()
+				if valid && score < 17 && !dealer.Natural() {
+					t.Errorf("expected mimic the dealer to finish on 17 or more, got %s", h)
+				}
+			}
+			split = split || len(players[2].Hands) > 1
+			if players[0].Bet() != 10 || players[1].Bet() != 20 || players[2].Bet() != 30 {
+				t.Errorf("expected the next bets to be placed, got %v, %v and %v", players[0].Bet(), players[1].Bet(), players[2].Bet())
+			}
+			if players[3].Bet() < 10 {
+				t.Errorf("expected the counting bot to bet at least a unit, got %v", players[3].Bet())
+			}
+		}
+		if !split {
+			t.Errorf("expected basic strategy to split at least once")
+		}
+		for i, d := range drivers {
+			if d.Err() != nil {
+				t.Errorf("seat %v: unexpected error %s", i, d.Err())
+			}
+		}
+	}
+}
+
+// Test that a Driver sees every card shown at the Table (the hole card once it's turned over), and forgets them when
+// the shoe's shuffled.
+func TestDriverSeen(t *testing.T) {
+	table, players, drivers := driverTable(t, blackjack.VegasStripRules, 1234, BasicStrategy{Amount: 10})
+	var expected []playdeck.Card
+	for round := 0; round < 40; round++ {
+		driverRound(t, table)
+		if table.Shuffled() {
+			expected = nil
+		}
+		dealer, err := table.DealerHand()
+		if err != nil {
+			t.Fatal(err)
+		}
+		round := 0
+		for _, h := range append(players[0].Hands, dealer) {
+			round += len(h.Cards)
+		}
+		seen := drivers[0].Seen()
+		if len(seen) != len(expected)+round {
+			t.Fatalf("expected %v cards seen, got %v", len(expected)+round, len(seen))
+		}
+		expected = seen
+	}
+}
+
+// Test that a Bot choosing something the Table won't do has its Hand stuck, and the error reported.
+func TestDriverError(t *testing.T) {
+	table, players, drivers := driverTable(t, blackjack.DefaultRules, 1234, splitter{})
+	driverRound(t, table)
+	if !errors.Is(drivers[0].Err(), blackjack.ErrSplitNotPair) {
+		t.Errorf("didn't get appropriate error when splitting everything, expected ErrSplitNotPair got %s", drivers[0].Err())
+	}
+	if len(players[0].Hands[0].Cards) != 2 {
+		t.Errorf("expected the hand to be stuck, got %s", players[0].Hands[0])
+	}
+
+	// Betting too much sits the round out.
+	err := table.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := blackjack.NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewDriver(table, player, AlwaysStand{Amount: 1000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.Start()
+	if !errors.Is(err, blackjack.ErrInsufficientFunds) {
+		t.Errorf("didn't get appropriate error when betting too much, expected ErrInsufficientFunds got %s", err)
+	}
+	driverRound(t, table)
+	if !errors.Is(d.Err(), blackjack.ErrInsufficientFunds) || player.Bet() != 0 {
+		t.Errorf("expected the next bet to fail too, got %v bet and error %s", player.Bet(), d.Err())
+	}
+}
+
+// splitter is a Bot that splits everything.
+type splitter struct{}
+
+func (splitter) Bet(View) int {
+	return 10
+}
+
+func (splitter) Play(View) strategy.Action {
+	return strategy.ActionSplit
+}
+
+// Test that Drivers can only be made for seated Players, and only started once.
+func TestDriverInvalid(t *testing.T) {
+	table := blackjack.NewTable(1)
+	player := blackjack.NewPlayer()
+	_, err := NewDriver(table, player, AlwaysStand{})
+	if !errors.Is(err, ErrPlayerNotSeated) {
+		t.Errorf("didn't get appropriate error when driving an unseated player, expected ErrPlayerNotSeated got %s", err)
+	}
+	_, err = NewDriver(nil, nil, AlwaysStand{})
+	if !errors.Is(err, ErrPlayerNotSeated) {
+		t.Errorf("didn't get appropriate error when driving nothing, expected ErrPlayerNotSeated got %s", err)
+	}
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewDriver(table, player, nil)
+	if !errors.Is(err, ErrNoBot) {
+		t.Errorf("didn't get appropriate error when driving without a bot, expected ErrNoBot got %s", err)
+	}
+
+	d, err := NewDriver(table, player, AlwaysStand{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.Start()
+	if !errors.Is(err, ErrDriverRunning) {
+		t.Errorf("didn't get appropriate error when starting twice, expected ErrDriverRunning got %s", err)
+	}
+	d.Stop()
+	d.Stop()
+
+	// Once stopped, the Driver leaves the Hand alone.
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if _, _, locked, _ := player.Hands[0].Score(); locked && !player.Hands[0].Natural() {
+		t.Errorf("expected a stopped driver not to play")
+	}
+}
@@ -0,0 +1,10 @@
+package bot
+
+import "errors"
+
+// Errors throwable by this module.
+var (
+	ErrNoBot           = errors.New("no bot given")
+	ErrPlayerNotSeated = errors.New("player is not seated at the table")
+	ErrDriverRunning   = errors.New("driver is already running")
+)
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+)
+
+// cardsPerDeck is the number of cards in a deck, without jokers.
+const cardsPerDeck = 52
+
+// A View is what a Bot can see of the Table it's playing at, as a copy: changing it changes nothing at the Table.
+type View struct {
+	// Rules are the rules the Table plays by.
+	Rules blackjack.RuleSet
+	// Bankroll is the Player's bankroll, not including anything bet or riding on a Hand.
+	Bankroll int
+	// Hands are the Player's Hands this round (if it's been dealt).
+	Hands []HandView
+	// Hand is the index in Hands of the Hand being decided, or -1 when betting.
+	Hand int
+	// UpCard is the dealer's up card, or nil if the round hasn't been dealt.
+	UpCard *playdeck.Card
+	// Seen is every card seen at the Table since the shoe was shuffled, in the order they were shown. The dealer's hole
+	// card is only seen once it's turned over.
+	Seen []playdeck.Card
+	// CardsRemaining is the number of cards left in the shoe.
+	CardsRemaining int
+	// TrueCount is the true count shared by the Table (see Table.SetCount()), if Counted is true.
+	TrueCount float64
+	Counted   bool
+}
+
+// A HandView is a copy of one of the Player's Hands.
+type HandView struct {
+	Cards []playdeck.Card
+	// Score is the best score of the Hand, and Soft is true if it's counting an ace as 11.
+	Score int
+	Soft  bool
+	// Wager is the amount staked on the Hand.
+	Wager   int
+	Split   bool
+	Doubled bool
+	// Locked is true once the Hand can't be played any further.
+	Locked bool
+}
+
+// DecksRemaining returns the number of decks left in the shoe (never less than a single card's worth).
+func (v View) DecksRemaining() float64 {
+	return float64(max(v.CardsRemaining, 1)) / cardsPerDeck
+}
+
+// Situation returns the strategy.Situation of the Hand being decided, to look up in a strategy.Chart.
+func (v View) Situation() (s strategy.Situation) {
+	s = strategy.Situation{Rules: v.Rules, Hands: len(v.Hands)}
+	if v.UpCard != nil {
+		s.UpCard = *v.UpCard
+	}
+	if v.Hand >= 0 && v.Hand < len(v.Hands) {
+		s.Cards = v.Hands[v.Hand].Cards
+		s.Split = v.Hands[v.Hand].Split
+	}
+	return s
+}
+
+// newView builds a View of the Table for a Player, deciding the given Hand (or -1 when betting).
+// None of the table's locks may be held by the caller.
+func newView(t *blackjack.Table, p *blackjack.Player, hand int, seen []playdeck.Card) (v View) {
+	v = View{
+		Rules:          t.Rules(),
+		Bankroll:       p.Bankroll(),
+		Hand:           hand,
+		Seen:           seen,
+		CardsRemaining: t.CardsRemaining(),
+	}
+	v.TrueCount, v.Counted = t.TrueCount()
+	if up, err := t.DealerUpCard(); err == nil {
+		v.UpCard = &up
+	}
+
+	p.RLock()
+	hands := append([]*blackjack.Hand(nil), p.Hands...)
+	p.RUnlock()
+	for _, h := range hands {
+		score, minScore, locked, _ := h.Score()
+		h.RLock()
+		cards := append([]playdeck.Card(nil), h.Cards...)
+		h.RUnlock()
+		v.Hands = append(v.Hands, HandView{
+			Cards:   cards,
+			Score:   score,
+			Soft:    score != minScore,
+			Wager:   h.Wager(),
+			Split:   h.WasSplit(),
+			Doubled: h.Doubled(),
+			Locked:  locked,
+		})
+	}
+	return v
+}
@@ -0,0 +1,214 @@
+package counting
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"math"
+	"sync"
+)
+
+// cardsPerDeck is the number of cards in a deck, without jokers.
+const cardsPerDeck = 52
+
+// A Counter keeps a running count of the cards seen coming out of a shoe under a counting System, and works out the
+// true count from how much of the shoe is left. It can be fed cards by hand (see See()), or left to watch a Deck or a
+// Table.
+// Counters implement blackjack.Count, and are safe for use in asynchronous environments.
+type Counter struct {
+	sync.Mutex
+	system *System
+	// decks is the number of decks in the shoe when it was shuffled.
+	decks int
+	// running is the running count, and seen the number of cards it's made of.
+	running float64
+	seen    int
+	// remaining is the number of cards left in the shoe, as seen by whatever the Counter is watching, or -1 if it isn't
+	// watching anything (so the cards it hasn't seen are taken to be left).
+	remaining int
+	// precision is what the decks left are rounded to (see SetPrecision()).
+	precision float64
+	// hole is true once the dealer's hole card has been counted this round, when watching a Table.
+	hole bool
+}
+
+// NewCounter returns a Counter for a freshly shuffled shoe of the given number of decks.
+func NewCounter(system *System, decks int) (counter *Counter) {
+	counter = &Counter{system: system}
+	counter.Shuffle(decks)
+	return counter
+}
+
+// System returns the counting System this Counter counts by.
+func (c *Counter) System() *System {
+	return c.system
+}
+
+// Shuffle starts the count again for a freshly shuffled shoe of the given number of decks.
+func (c *Counter) Shuffle(decks int) {
+	c.Lock()
+	defer c.Unlock()
+	c.shuffle(decks, -1)
+}
+
+// shuffle is the implementation behind Shuffle(). remaining is the number of cards in the new shoe, or -1 if unknown.
+// The counter lock must be held by the caller.
+func (c *Counter) shuffle(decks int, remaining int) {
+	c.decks = decks
+	c.running = c.system.InitialCount(decks)
+	c.seen = 0
+	c.remaining = remaining
+	c.hole = false
+}
+
+// See adds cards that have been seen to the count.
+func (c *Counter) See(cards ...playdeck.Card) {
+	c.Lock()
+	defer c.Unlock()
+	c.see(cards, c.remaining)
+}
+
+// see adds cards to the count, and sets the number of cards left in the shoe (-1 if unknown).
+// The counter lock must be held by the caller.
+func (c *Counter) see(cards []playdeck.Card, remaining int) {
+	for _, card := range cards {
+		if _, ok := index(card); !ok {
+			continue
+		}
+		c.running += c.system.Tag(card)
+		c.seen++
+	}
+	c.remaining = remaining
+}
+
+// RunningCount returns the count of every card seen since the shoe was shuffled.
+func (c *Counter) RunningCount() float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.running
+}
+
+// CardsSeen returns the number of cards counted since the shoe was shuffled.
+func (c *Counter) CardsSeen() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.seen
+}
+
+// SetPrecision sets what DecksRemaining() rounds to, in decks: 0.5 estimates to the nearest half deck, as a player
+// eyeing up the discard tray would. 0 (the default) counts exactly.
+func (c *Counter) SetPrecision(decks float64) {
+	c.Lock()
+	defer c.Unlock()
+	c.precision = max(decks, 0)
+}
+
+// DecksRemaining estimates the number of decks left in the shoe, rounded to the Counter's precision (and never less
+// than it, or a single card if counting exactly). When watching a Deck or Table, it's worked out from the cards actually
+// left in the shoe; otherwise, every card that hasn't been seen is taken to be left.
+func (c *Counter) DecksRemaining() float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.decksRemaining()
+}
+
+// decksRemaining is the implementation behind DecksRemaining().
+// The counter lock must be held by the caller.
+func (c *Counter) decksRemaining() float64 {
+	cards := c.remaining
+	if cards < 0 {
+		cards = c.decks*cardsPerDeck - c.seen
+	}
+	if c.precision > 0 {
+		decks := math.Round(float64(cards)/cardsPerDeck/c.precision) * c.precision
+		return max(decks, c.precision)
+	}
+	return float64(max(cards, 1)) / cardsPerDeck
+}
+
+// TrueCount returns the running count per deck left in the shoe. Unbalanced systems (see System.Balanced()) are meant
+// to be played by their running count instead, but the true count is worked out the same way for them.
+func (c *Counter) TrueCount() float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.running / c.decksRemaining()
+}
+
+// String summarises the count, e.g. "Hi-Lo: running +4, true +2.0, 2.0 decks left".
+func (c *Counter) String() string {
+	c.Lock()
+	defer c.Unlock()
+	decks := c.decksRemaining()
+	return fmt.Sprintf("%s: running %+g, true %+.1f, %.1f decks left", c.system, c.running, c.running/decks, decks)
+}
+
+// WatchDeck counts every card pulled from the given Deck, and takes the cards left in the shoe from it, until the
+// returned function is called. The count isn't started again; call Shuffle() first if the Deck is fresh.
+func (c *Counter) WatchDeck(d *playdeck.Deck) (unwatch func()) {
+	c.Lock()
+	c.remaining = d.Len()
+	c.Unlock()
+	return d.Watch(func(card playdeck.Card) {
+		remaining := d.Len()
+		c.Lock()
+		defer c.Unlock()
+		c.see([]playdeck.Card{card}, remaining)
+	})
+}
+
+// WatchTable counts the cards dealt at a Table as a player sitting there would see them: face up cards as they're
+// dealt, and the dealer's hole card once it's turned over. The count starts again whenever the shoe is reshuffled.
+// The Counter is shared with everyone at the Table (see Table.SetCount()) until the returned function is called.
+// The count is started again for the Table's shoe straight away, so it's best to start watching before the first deal.
+func (c *Counter) WatchTable(t *blackjack.Table) (unwatch func()) {
+	decks, remaining := t.Rules().Decks, t.CardsRemaining()
+	c.Lock()
+	c.shuffle(decks, remaining)
+	c.Unlock()
+
+	stop := t.Subscribe(func(e blackjack.Event) {
+		c.tableEvent(t, e)
+	})
+	t.SetCount(c)
+	return func() {
+		stop()
+		if t.Count() == blackjack.Count(c) {
+			t.SetCount(nil)
+		}
+	}
+}
+
+// tableEvent updates the count from an Event at a Table being watched.
+func (c *Counter) tableEvent(t *blackjack.Table, e blackjack.Event) {
+	switch e.Type {
+	case blackjack.EventShuffle:
+		decks, remaining := t.Rules().Decks, t.CardsRemaining()
+		c.Lock()
+		defer c.Unlock()
+		c.shuffle(decks, remaining)
+	case blackjack.EventRoundDealt:
+		c.Lock()
+		defer c.Unlock()
+		c.hole = false
+	case blackjack.EventCardDealt:
+		if e.Card == nil || e.Hidden {
+			return
+		}
+		remaining := t.CardsRemaining()
+		c.Lock()
+		defer c.Unlock()
+		c.see([]playdeck.Card{*e.Card}, remaining)
+	case blackjack.EventDealerPeek, blackjack.EventDealerTurn:
+		// The hole card is shown when the dealer peeks at a blackjack, and again when they play.
+		if e.Card == nil {
+			return
+		}
+		remaining := t.CardsRemaining()
+		c.Lock()
+		defer c.Unlock()
+		if !c.hole {
+			c.hole = true
+			c.see([]playdeck.Card{*e.Card}, remaining)
+		}
+	}
+}
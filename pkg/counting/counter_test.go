@@ -0,0 +1,79 @@
+package counting
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"math"
+	"testing"
+)
+
+// countingCards is a helper that parses a list of cards.
+func countingCards(t *testing.T, s string) (cards []playdeck.Card) {
+	t.Helper()
+	cards, err := playdeck.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+// Test the running and true counts of cards fed to a Counter by hand.
+func TestCounterSee(t *testing.T) {
+	c := NewCounter(HiLo, 2)
+	// 26 cards: ten low cards, four high cards and twelve neutral ones, leaving a deck and a half.
+	c.See(countingCards(t, "2S 3S 4S 5S 6S 2H 3H 4H 5H 6H AS KS QH TD 7S 8S 9S 7H 8H 9H 7D 8D 9D 7C 8C 9C")...)
+	if c.RunningCount() != 6 {
+		t.Errorf("expected a running count of +6, got %v", c.RunningCount())
+	}
+	if c.CardsSeen() != 26 {
+		t.Errorf("expected 26 cards seen, got %v", c.CardsSeen())
+	}
+	if c.DecksRemaining() != 1.5 {
+		t.Errorf("expected 1.5 decks left, got %v", c.DecksRemaining())
+	}
+	if c.TrueCount() != 4 {
+		t.Errorf("expected a true count of +4, got %v", c.TrueCount())
+	}
+	if s := c.String(); s != "Hi-Lo: running +6, true +4.0, 1.5 decks left" {
+		t.Errorf("unexpected summary %q", s)
+	}
+
+	// Jokers aren't cards as far as counting goes.
+	c.See(playdeck.Card{})
+	if c.CardsSeen() != 26 {
+		t.Errorf("expected a joker not to be counted, got %v cards seen", c.CardsSeen())
+	}
+
+	c.Shuffle(6)
+	if c.RunningCount() != 0 || c.CardsSeen() != 0 || c.DecksRemaining() != 6 {
+		t.Errorf("expected a fresh count of 6 decks, got %v", c)
+	}
+	if c.System() != HiLo {
+		t.Errorf("expected the counter's system to be Hi-Lo, got %s", c.System())
+	}
+}
+
+// Test that decks left are rounded to the Counter's precision, and never reach 0.
+func TestCounterPrecision(t *testing.T) {
+	c := NewCounter(KO, 1)
+	c.See(countingCards(t, "2S 3S 4S 5S 6S 2H 3H 4H 5H 6H 2D 3D 4D 5D 6D 2C 3C 4C 5C 6C")...)
+	if math.Abs(c.DecksRemaining()-32.0/52) > 1e-9 {
+		t.Errorf("expected %v decks left, got %v", 32.0/52, c.DecksRemaining())
+	}
+	c.SetPrecision(0.5)
+	if c.DecksRemaining() != 0.5 {
+		t.Errorf("expected 0.5 decks left, got %v", c.DecksRemaining())
+	}
+	// KO starts at 0 for a single deck, and there have been twenty low cards.
+	if c.TrueCount() != 40 {
+		t.Errorf("expected a true count of 40, got %v", c.TrueCount())
+	}
+
+	c.See(*playdeck.NewDeck(false).Cards...)
+	if c.DecksRemaining() != 0.5 {
+		t.Errorf("expected the decks left to stop at 0.5, got %v", c.DecksRemaining())
+	}
+	c.SetPrecision(0)
+	if c.DecksRemaining() != 1.0/52 {
+		t.Errorf("expected the decks left to stop at a card, got %v", c.DecksRemaining())
+	}
+}
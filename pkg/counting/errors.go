@@ -0,0 +1,8 @@
+package counting
+
+import "errors"
+
+// Errors throwable by this module.
+var (
+	ErrUnknownSystem = errors.New("counting system is unknown")
+)
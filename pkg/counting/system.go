@@ -0,0 +1,106 @@
+package counting
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"strings"
+	"unicode"
+)
+
+// Tags are the values a counting System gives each card, from the ace to the ten (which picture cards count as).
+type Tags [10]float64
+
+// A System is a card counting system: how much each card adds to the count, and where the count starts.
+type System struct {
+	// Name is the system's usual name, e.g. "Hi-Lo".
+	Name string
+	// Tags are what each card adds to the running count.
+	Tags Tags
+	// Start and StartPerDeck give the running count for a freshly shuffled shoe: Start, plus StartPerDeck for every deck
+	// in it. Balanced systems start at 0; unbalanced ones start low, so that their running count can be used as it is.
+	Start        float64
+	StartPerDeck float64
+}
+
+// The built-in counting systems.
+var (
+	//                    A    2    3    4    5    6    7    8    9    T
+	HiLo = &System{Name: "Hi-Lo",
+		Tags: Tags{-1, +1, +1, +1, +1, +1, 0, 0, 0, -1}}
+	KO = &System{Name: "KO",
+		Tags: Tags{-1, +1, +1, +1, +1, +1, +1, 0, 0, -1}, Start: 4, StartPerDeck: -4}
+	HiOptI = &System{Name: "Hi-Opt I",
+		Tags: Tags{0, 0, +1, +1, +1, +1, 0, 0, 0, -1}}
+	HiOptII = &System{Name: "Hi-Opt II",
+		Tags: Tags{0, +1, +1, +2, +2, +1, +1, 0, 0, -2}}
+	OmegaII = &System{Name: "Omega II",
+		Tags: Tags{0, +1, +1, +2, +2, +2, +1, 0, -1, -2}}
+	Zen = &System{Name: "Zen",
+		Tags: Tags{-1, +1, +1, +2, +2, +2, +1, 0, 0, -2}}
+	WongHalves = &System{Name: "Wong Halves",
+		Tags: Tags{-1, +0.5, +1, +1, +1.5, +1, +0.5, 0, -0.5, -1}}
+)
+
+// Systems lists every built-in System.
+var Systems = []*System{HiLo, KO, HiOptI, HiOptII, OmegaII, Zen, WongHalves}
+
+// ParseSystem returns the built-in System with the given name, ignoring case, spaces and punctuation (so "hilo" is
+// Hi-Lo). It returns an error wrapping ErrUnknownSystem if there isn't one.
+func ParseSystem(name string) (system *System, err error) {
+	for _, s := range Systems {
+		if simplify(s.Name) == simplify(name) {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownSystem, name)
+}
+
+// simplify lower-cases a name and strips everything but letters and digits from it.
+func simplify(name string) string {
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// String returns the System's name.
+func (s *System) String() string {
+	return s.Name
+}
+
+// Tag returns what a card adds to the running count. Jokers add nothing.
+func (s *System) Tag(card playdeck.Card) float64 {
+	i, ok := index(card)
+	if !ok {
+		return 0
+	}
+	return s.Tags[i]
+}
+
+// Balanced returns whether a full deck counts to 0 under this System, so that it needs a true count to be used.
+func (s *System) Balanced() bool {
+	// Four of each card, and sixteen tens.
+	sum := s.Tags[9] * 16
+	for _, tag := range s.Tags[:9] {
+		sum += tag * 4
+	}
+	return sum == 0
+}
+
+// InitialCount returns the running count for a freshly shuffled shoe of the given number of decks.
+func (s *System) InitialCount(decks int) float64 {
+	return s.Start + s.StartPerDeck*float64(decks)
+}
+
+// index returns the Tags index of a card, and false for jokers.
+func index(card playdeck.Card) (i int, ok bool) {
+	switch {
+	case card.Value < playdeck.ValueAce || card.Value > playdeck.ValueKing:
+		return 0, false
+	case card.Value >= playdeck.ValueTen:
+		return 9, true
+	}
+	return int(card.Value) - 1, true
+}
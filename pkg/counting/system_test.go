@@ -0,0 +1,73 @@
+package counting
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// Test that every built-in System but KO is balanced, and that KO starts low enough to end at 4.
+func TestSystemBalanced(t *testing.T) {
+	for _, s := range Systems {
+		if s.Balanced() != (s != KO) {
+			t.Errorf("%s: expected balanced to be %v", s, s != KO)
+		}
+	}
+	// A full six deck shoe counts +24 under KO, so it starts at -20 to finish on +4.
+	if start := KO.InitialCount(6); start != -20 {
+		t.Errorf("expected KO to start at -20 with six decks, got %v", start)
+	}
+	if start := HiLo.InitialCount(6); start != 0 {
+		t.Errorf("expected Hi-Lo to start at 0, got %v", start)
+	}
+}
+
+// Test that cards are tagged by their value, with picture cards as tens.
+func TestSystemTag(t *testing.T) {
+	cases := []struct {
+		system *System
+		card   string
+		tag    float64
+	}{
+		{HiLo, "AS", -1},
+		{HiLo, "2H", 1},
+		{HiLo, "7D", 0},
+		{HiLo, "KC", -1},
+		{KO, "7D", 1},
+		{HiOptI, "2H", 0},
+		{HiOptII, "5S", 2},
+		{OmegaII, "9S", -1},
+		{Zen, "AS", -1},
+		{WongHalves, "5S", 1.5},
+		{WongHalves, "QH", -1},
+		{HiLo, "Jkr", 0},
+	}
+	for _, c := range cases {
+		card, err := playdeck.ParseCard(c.card)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tag := c.system.Tag(card); tag != c.tag {
+			t.Errorf("%s: expected %s to be tagged %v, got %v", c.system, c.card, c.tag, tag)
+		}
+	}
+}
+
+// Test that Systems can be looked up by name.
+func TestParseSystem(t *testing.T) {
+	for name, want := range map[string]*System{
+		"Hi-Lo": HiLo, "hilo": HiLo, "ko": KO, "hi-opt i": HiOptI, "HiOptII": HiOptII, "omega 2": nil, "omegaii": OmegaII,
+		"zen": Zen, "wong_halves": WongHalves,
+	} {
+		s, err := ParseSystem(name)
+		if want == nil {
+			if !errors.Is(err, ErrUnknownSystem) {
+				t.Errorf("didn't get appropriate error when parsing %q, expected ErrUnknownSystem got %s", name, err)
+			}
+			continue
+		}
+		if err != nil || s != want {
+			t.Errorf("expected %q to be %s, got %v (%s)", name, want, s, err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package counting
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// Test that a Counter watching a Deck counts what's pulled from it, and takes the cards left from it.
+func TestCounterWatchDeck(t *testing.T) {
+	deck := playdeck.NewDeckOfDecks(2, false)
+	c := NewCounter(HiLo, 2)
+	unwatch := c.WatchDeck(deck)
+	expected := 0.0
+	for i := 0; i < 52; i++ {
+		card, err := deck.PullCard()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected += HiLo.Tag(card)
+	}
+	// Nothing after this is seen.
+	err := deck.PushCard(playdeck.Card{Suit: playdeck.SuitSpade, Value: playdeck.ValueAce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwatch()
+	_, err = deck.PullCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RunningCount() != expected || c.CardsSeen() != 52 {
+		t.Errorf("expected a running count of %v from 52 cards, got %v from %v", expected, c.RunningCount(), c.CardsSeen())
+	}
+	if c.DecksRemaining() != 1 {
+		t.Errorf("expected a deck left, got %v", c.DecksRemaining())
+	}
+}
+
+// Test that a Counter watching a Table counts every card as it's shown, including the hole card (but only once), and
+// starts again when the shoe is shuffled.
+func TestCounterWatchTable(t *testing.T) {
+	table, err := blackjack.NewTableWithRules(blackjack.VegasStripRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.SetSource(playdeck.NewSeededSource(1234))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := blackjack.NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCounter(Zen, 1)
+	unwatch := c.WatchTable(table)
+	if table.Count() != blackjack.Count(c) {
+		t.Fatalf("expected the table to share the counter")
+	}
+
+	expected, shuffles, peeked := 0.0, 0, 0
+	for round := 0; round < 300; round++ {
+		errs := table.Deal()
+		if len(errs) != 0 {
+			t.Fatal(errs)
+		}
+		if table.Shuffled() {
+			shuffles++
+			expected = 0
+		}
+		// Only the up card is showing.
+		up, err := table.DealerUpCard()
+		if err != nil {
+			t.Fatal(err)
+		}
+		showing := expected + Zen.Tag(up) + Zen.Tag(player.Hands[0].Cards[0]) + Zen.Tag(player.Hands[0].Cards[1])
+		if table.PeekPending() {
+			err = table.DealerPeek()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, _, locked, _ := player.Hands[0].Score(); !locked {
+			if c.RunningCount() != showing {
+				t.Fatalf("round %v: expected a running count of %v before playing, got %v", round, showing, c.RunningCount())
+			}
+			err = player.Hands[0].Stick()
+			if err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			peeked++
+		}
+		err = table.EndRound()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealer, err := table.DealerHand()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, h := range []*blackjack.Hand{player.Hands[0], dealer} {
+			for _, card := range h.Cards {
+				expected += Zen.Tag(card)
+			}
+		}
+		if c.RunningCount() != expected {
+			t.Fatalf("round %v: expected a running count of %v, got %v", round, expected, c.RunningCount())
+		}
+		if c.DecksRemaining() != float64(table.CardsRemaining())/52 {
+			t.Errorf("round %v: expected %v decks left, got %v", round, float64(table.CardsRemaining())/52, c.DecksRemaining())
+		}
+		if count, ok := table.TrueCount(); !ok || count != c.TrueCount() {
+			t.Errorf("round %v: expected the table's true count to be %v, got %v", round, c.TrueCount(), count)
+		}
+	}
+	if shuffles < 2 || peeked == 0 {
+		t.Errorf("expected the shoe to be shuffled and the dealer to show a blackjack, got %v shuffles and %v blackjacks", shuffles, peeked)
+	}
+
+	unwatch()
+	if table.Count() != nil {
+		t.Errorf("expected the table to stop sharing the counter")
+	}
+}
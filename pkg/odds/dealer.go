@@ -0,0 +1,185 @@
+package odds
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"strings"
+)
+
+// DealerResult is how the dealer's hand can finish.
+type DealerResult uint8
+
+// The dealer finishes on one of these.
+const (
+	Dealer17 DealerResult = iota
+	Dealer18
+	Dealer19
+	Dealer20
+	Dealer21
+	DealerBust
+	DealerBlackjack
+	dealerResults
+)
+
+var dealerResultNames = map[DealerResult]string{
+	Dealer17:        "17",
+	Dealer18:        "18",
+	Dealer19:        "19",
+	Dealer20:        "20",
+	Dealer21:        "21",
+	DealerBust:      "bust",
+	DealerBlackjack: "blackjack",
+}
+
+// String returns a human-readable name for this result (e.g. "bust"). Unknown values return "unknown".
+func (r DealerResult) String() string {
+	name, exists := dealerResultNames[r]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// A Distribution is the probability of the dealer finishing on each DealerResult, indexed by DealerResult.
+type Distribution [dealerResults]float64
+
+// NoBlackjack returns this Distribution given that the dealer doesn't have blackjack (e.g. because they've peeked).
+func (d Distribution) NoBlackjack() (nd Distribution) {
+	rest := 1 - d[DealerBlackjack]
+	if rest <= 0 {
+		return nd
+	}
+	for r := Dealer17; r < DealerBlackjack; r++ {
+		nd[r] = d[r] / rest
+	}
+	return nd
+}
+
+// String lists the probability of each result, e.g. "17: 14.58%, 18: 13.81%, ...".
+func (d Distribution) String() string {
+	parts := make([]string, 0, len(d))
+	for r, p := range d {
+		parts = append(parts, fmt.Sprintf("%s: %.2f%%", DealerResult(r), p*100))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Dealer returns the exact probability of the dealer finishing on each DealerResult, given their up card and the
+// composition of the cards they'll draw from (including their hole card). Blackjack is included whether or not the
+// dealer peeks for it; see Distribution.NoBlackjack() for the odds once they have.
+// If the shoe runs out while the dealer is still drawing, they're taken to finish on 17.
+func (c *Calculator) Dealer(shoe Shoe, up playdeck.Card) (d Distribution, err error) {
+	u, ok := Index(up)
+	if !ok {
+		return d, fmt.Errorf("%w: %s", ErrInvalidCard, up.Notation())
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.trim()
+	d, pBJ := c.dealerUp(shoe, value(u))
+	for r := range d {
+		d[r] *= 1 - pBJ
+	}
+	d[DealerBlackjack] = pBJ
+	return d, nil
+}
+
+// dealerKey identifies a dealer's hand partway through being played out from a Shoe. up is set for hands that only have
+// their up card so far.
+type dealerKey struct {
+	shoe Shoe
+	hard int
+	ace  bool
+	up   bool
+}
+
+// dealerUp returns the Distribution for a dealer with only their up card (as a value, with aces as 1), given that they
+// don't have blackjack, along with the probability that they do.
+// The calculator lock must be held by the caller.
+func (c *Calculator) dealerUp(shoe Shoe, up int) (d Distribution, pBJ float64) {
+	key := dealerKey{shoe: shoe, hard: up, ace: up == 1, up: true}
+	if d, exists := c.dealer[key]; exists {
+		return d, c.blackjack[key]
+	}
+
+	total := shoe.Len()
+	naturals := 0
+	for i, n := range shoe {
+		if n == 0 {
+			continue
+		}
+		hard, ace := up+value(i), up == 1 || i == 0
+		if ace && hard == 11 {
+			naturals += n
+			continue
+		}
+		shoe[i]--
+		next := c.dealerDraw(shoe, hard, ace)
+		shoe[i]++
+		for r := range d {
+			d[r] += float64(n) * next[r]
+		}
+	}
+	if rest := total - naturals; rest > 0 {
+		for r := range d {
+			d[r] /= float64(rest)
+		}
+	} else if total == 0 {
+		d[Dealer17] = 1
+	}
+	if total > 0 {
+		pBJ = float64(naturals) / float64(total)
+	}
+	c.dealer[key] = d
+	c.blackjack[key] = pBJ
+	return d, pBJ
+}
+
+// dealerDraw returns the Distribution for a dealer with at least two cards, totalling hard (with aces as 1), plus 10 if
+// they hold an ace that can count as 11.
+// The calculator lock must be held by the caller.
+func (c *Calculator) dealerDraw(shoe Shoe, hard int, ace bool) (d Distribution) {
+	best, soft := bestTotal(hard, ace)
+	switch {
+	case best > 21:
+		d[DealerBust] = 1
+		return d
+	case best > 17 || (best == 17 && !(soft && c.hitsSoft17)):
+		d[DealerResult(best-17)] = 1
+		return d
+	}
+
+	key := dealerKey{shoe: shoe, hard: hard, ace: ace}
+	if d, exists := c.dealer[key]; exists {
+		return d
+	}
+	total := shoe.Len()
+	if total == 0 {
+		d[Dealer17] = 1
+		return d
+	}
+	for i, n := range shoe {
+		if n == 0 {
+			continue
+		}
+		shoe[i]--
+		next := c.dealerDraw(shoe, hard+value(i), ace || i == 0)
+		shoe[i]++
+		for r := range d {
+			d[r] += float64(n) * next[r]
+		}
+	}
+	for r := range d {
+		d[r] /= float64(total)
+	}
+	c.dealer[key] = d
+	return d
+}
+
+// bestTotal returns the best total of a hand totalling hard with aces as 1, and whether that total is soft.
+func bestTotal(hard int, ace bool) (total int, soft bool) {
+	if ace && hard+10 <= 21 {
+		return hard + 10, true
+	}
+	return hard, false
+}
@@ -0,0 +1,100 @@
+package odds
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"math"
+	"testing"
+)
+
+// oddsCard is a helper that parses a single card.
+func oddsCard(t *testing.T, s string) (card playdeck.Card) {
+	t.Helper()
+	card, err := playdeck.ParseCard(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return card
+}
+
+// Test dealer odds from shoes small enough to work out by hand.
+func TestDealerExact(t *testing.T) {
+	s17 := NewCalculator(blackjack.DefaultRules)
+	h17rules := blackjack.DefaultRules
+	h17rules.DealerRule = blackjack.DealerHitsSoft17
+	h17 := NewCalculator(h17rules)
+
+	tens := Shoe{9: 4}
+	sixes := Shoe{5: 4}
+	cases := []struct {
+		name   string
+		c      *Calculator
+		shoe   Shoe
+		up     string
+		result DealerResult
+		p      float64
+	}{
+		{"seven against tens", s17, tens, "7S", Dealer17, 1},
+		{"six against tens", s17, tens, "6S", DealerBust, 1},
+		{"ace against tens", s17, tens, "AS", DealerBlackjack, 1},
+		{"soft 17 standing", s17, sixes, "AS", Dealer17, 1},
+		{"soft 17 hitting", h17, sixes, "AS", Dealer19, 1},
+		// Half the time the hole card is a ten (20), and half the time a two, which then has to take the ten (22).
+		{"two or ten", s17, Shoe{1: 1, 9: 1}, "TS", Dealer20, 0.5},
+		{"two or ten bust", s17, Shoe{1: 1, 9: 1}, "TS", DealerBust, 0.5},
+		{"empty shoe", s17, Shoe{}, "5S", Dealer17, 1},
+	}
+	for _, c := range cases {
+		d, err := c.c.Dealer(c.shoe, oddsCard(t, c.up))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(d[c.result]-c.p) > 1e-9 {
+			t.Errorf("%s: expected %s with probability %v, got %s", c.name, c.result, c.p, d)
+		}
+	}
+}
+
+// Test dealer odds from a real shoe against well-known figures.
+func TestDealerShoe(t *testing.T) {
+	c := NewCalculator(blackjack.DefaultRules)
+	for _, up := range []string{"AS", "2S", "6S", "TS"} {
+		card := oddsCard(t, up)
+		shoe, err := NewShoe(6).Remove(card)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := c.Dealer(shoe, card)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := 0.0
+		for _, p := range d {
+			sum += p
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("%s: probabilities sum to %v", up, sum)
+		}
+		if nb := d.NoBlackjack(); nb[DealerBlackjack] != 0 {
+			t.Errorf("%s: expected no blackjack once it's been ruled out", up)
+		}
+	}
+
+	// A dealer showing a six busts about 42% of the time, and one showing a ten has an ace in the hole 24 times in 311.
+	d, err := c.Dealer(NewShoe(6), oddsCard(t, "6S"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d[DealerBust] < 0.41 || d[DealerBust] > 0.43 {
+		t.Errorf("expected the dealer to bust about 42%% of the time under a six, got %s", d)
+	}
+	ten := oddsCard(t, "TS")
+	shoe, _ := NewShoe(6).Remove(ten)
+	d, _ = c.Dealer(shoe, ten)
+	if math.Abs(d[DealerBlackjack]-24.0/311) > 1e-9 {
+		t.Errorf("expected blackjack with probability %v under a ten, got %v", 24.0/311, d[DealerBlackjack])
+	}
+	if DealerResult(42).String() != "unknown" {
+		t.Errorf("expected \"unknown\", got %q", DealerResult(42).String())
+	}
+}
@@ -0,0 +1,10 @@
+package odds
+
+import "errors"
+
+// Errors throwable by this module.
+var (
+	ErrCardNotInShoe = errors.New("card is not in the shoe")
+	ErrInvalidCard   = errors.New("card is invalid")
+	ErrInvalidHand   = errors.New("hand cannot be played")
+)
@@ -0,0 +1,312 @@
+package odds
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"math"
+	"sync"
+)
+
+// maxCache is the number of results a Calculator keeps before it starts again with an empty cache.
+const maxCache = 1 << 18
+
+// A Calculator works out exact dealer odds and expected values for the given rules. It caches everything it works out
+// by the composition of the cards left, so asking about the same shoe again (e.g. for each decision in a round) is cheap.
+// Safe for use in asynchronous environments, though calculations are done one at a time.
+type Calculator struct {
+	sync.Mutex
+	rules      blackjack.RuleSet
+	hitsSoft17 bool
+
+	// dealer caches Distributions by the dealer's hand and the shoe, and blackjack the chance of a dealer blackjack for
+	// hands with just an up card.
+	dealer    map[dealerKey]Distribution
+	blackjack map[dealerKey]float64
+	// hit caches the expected value of hitting (and then playing on as well as possible) by the player's hand and the shoe.
+	hit map[playerKey]float64
+}
+
+// playerKey identifies a player's hand against a dealer up card, partway through being played out from a Shoe.
+type playerKey struct {
+	shoe Shoe
+	hard int
+	ace  bool
+	up   int
+}
+
+// NewCalculator returns a Calculator for the given rules.
+func NewCalculator(rules blackjack.RuleSet) (c *Calculator) {
+	c = &Calculator{rules: rules, hitsSoft17: rules.DealerRule == blackjack.DealerHitsSoft17}
+	c.reset()
+	return c
+}
+
+// reset empties the cache.
+func (c *Calculator) reset() {
+	c.dealer = map[dealerKey]Distribution{}
+	c.blackjack = map[dealerKey]float64{}
+	c.hit = map[playerKey]float64{}
+}
+
+// trim empties the cache if it's grown too big.
+// The calculator lock must be held by the caller.
+func (c *Calculator) trim() {
+	if len(c.dealer)+len(c.hit) > maxCache {
+		c.reset()
+	}
+}
+
+// EV is the expected value of each thing a Player can do with a Hand, as a proportion of the Hand's wager (so -0.5 is
+// losing half of it, on average). Actions the rules don't allow are NaN.
+// Splits are worked out without resplitting, and assume the other split hand's draws don't affect this one's.
+type EV struct {
+	Stand     float64 `json:"stand"`
+	Hit       float64 `json:"hit"`
+	Double    float64 `json:"double"`
+	Split     float64 `json:"split"`
+	Surrender float64 `json:"surrender"`
+}
+
+// Best returns the Action with the highest expected value, and its value.
+func (e EV) Best() (action strategy.Action, ev float64) {
+	action, ev = strategy.ActionStand, e.Stand
+	for _, a := range []struct {
+		action strategy.Action
+		ev     float64
+	}{{strategy.ActionHit, e.Hit}, {strategy.ActionDouble, e.Double}, {strategy.ActionSplit, e.Split},
+		{strategy.ActionSurrender, e.Surrender}} {
+		if !math.IsNaN(a.ev) && a.ev > ev {
+			action, ev = a.action, a.ev
+		}
+	}
+	return action, ev
+}
+
+// EV returns the expected value of each Action for a freshly dealt (not split) hand holding the given cards, against
+// the given dealer up card. The shoe is every card the Player hasn't seen, including the dealer's hole card. If the
+// dealer peeks for blackjack, the values are as they stand after the peek.
+// It returns an error wrapping ErrInvalidHand if the cards can't be played (e.g. they're bust), or ErrInvalidCard.
+func (c *Calculator) EV(shoe Shoe, cards []playdeck.Card, up playdeck.Card) (ev EV, err error) {
+	canSplit := c.rules.Split.MaxHands >= 2
+	return c.ev(shoe, cards, up, false, canSplit)
+}
+
+// HandEV returns the expected value of each Action for a Hand being played at a Table, taking the shoe, dealer up card
+// and split state from the Table. The dealer's hole card is counted as unseen.
+// It returns ErrInvalidHand if the Hand isn't at a Table, and blackjack.ErrDealerNoHand if the round hasn't been dealt.
+func (c *Calculator) HandEV(h *blackjack.Hand) (ev EV, err error) {
+	if h == nil || h.Table == nil || h.Player == nil {
+		return ev, ErrInvalidHand
+	}
+	snap := h.Table.Snapshot()
+	if len(snap.Dealer) == 0 || len(snap.Dealer[0].Cards) == 0 {
+		return ev, blackjack.ErrDealerNoHand
+	}
+	dealer := snap.Dealer[0].Cards
+	shoe, err := Shoe{}.Add(snap.Deck...)
+	if err != nil {
+		return ev, err
+	}
+	shoe, err = shoe.Add(dealer[1:]...)
+	if err != nil {
+		return ev, err
+	}
+
+	h.RLock()
+	cards := append([]playdeck.Card(nil), h.Cards...)
+	h.RUnlock()
+	h.Player.RLock()
+	hands := len(h.Player.Hands)
+	h.Player.RUnlock()
+	split := h.WasSplit()
+	rules := c.rules.Split
+	canSplit := rules.MaxHands >= 2 && hands < rules.MaxHands && !(split && cards[0].Value == playdeck.ValueAce && !rules.ResplitAces)
+	return c.ev(shoe, cards, dealer[0], split, canSplit)
+}
+
+// ev is the implementation of EV() and HandEV().
+func (c *Calculator) ev(shoe Shoe, cards []playdeck.Card, up playdeck.Card, split bool, canSplit bool) (ev EV, err error) {
+	u, ok := Index(up)
+	if !ok {
+		return ev, fmt.Errorf("%w: %s", ErrInvalidCard, up.Notation())
+	}
+	if len(cards) < 2 {
+		return ev, fmt.Errorf("%w: a hand needs at least two cards, got %v", ErrInvalidHand, len(cards))
+	}
+	hard, ace := 0, false
+	for _, card := range cards {
+		i, ok := Index(card)
+		if !ok {
+			return ev, fmt.Errorf("%w: %s", ErrInvalidCard, card.Notation())
+		}
+		hard += value(i)
+		ace = ace || i == 0
+	}
+	best, _ := bestTotal(hard, ace)
+	if best > 21 {
+		return ev, fmt.Errorf("%w: hand is bust", ErrInvalidHand)
+	}
+	two := len(cards) == 2
+
+	c.Lock()
+	defer c.Unlock()
+	c.trim()
+	up1 := value(u)
+	_, pBJ := c.dealerUp(shoe, up1)
+	// Once the dealer's peeked, they can't have blackjack.
+	if c.rules.DealerPeek && !c.rules.NoHoleCard {
+		pBJ = 0
+	}
+	// lose weighs up the expected value of an action given the dealer doesn't have blackjack, against losing the given
+	// stake if they do.
+	lose := func(value float64, stake float64) float64 {
+		return (1-pBJ)*value - pBJ*stake
+	}
+
+	ev = EV{Double: math.NaN(), Split: math.NaN(), Surrender: math.NaN()}
+	if two && best == 21 && !split {
+		// A natural pushes against a dealer blackjack, and is paid out otherwise.
+		payout := c.rules.BlackjackPayout
+		ev.Stand = (1 - pBJ) * float64(payout.Pays) / float64(payout.Per)
+	} else {
+		ev.Stand = lose(c.stand(shoe, best, up1), 1)
+	}
+	// Split aces that only get one card can be stood on (or re-split), but not drawn to.
+	oneCard := split && c.rules.Split.AcesOneCard && cards[0].Value == playdeck.ValueAce
+	ev.Hit = math.NaN()
+	if !oneCard {
+		ev.Hit = lose(c.hitEV(shoe, hard, ace, up1), 1)
+	}
+	if two && !oneCard && c.rules.Double.Totals.Allows(best) && !(split && !c.rules.Double.AfterSplit) {
+		ev.Double = lose(c.double(shoe, hard, ace, up1), 2)
+	}
+	if two && canSplit && sameValue(cards[0], cards[1], c.rules.Split.AnyTens) {
+		i, _ := Index(cards[0])
+		ev.Split = lose(c.split(shoe, i, up1), 2)
+	}
+	if two && !split && c.rules.Surrender != blackjack.SurrenderNone {
+		ev.Surrender = -0.5
+		if c.rules.Surrender == blackjack.SurrenderLate {
+			ev.Surrender = lose(-0.5, 1)
+		}
+	}
+	return ev, nil
+}
+
+// stand returns the expected value of standing on the given total, given the dealer doesn't have blackjack.
+// The calculator lock must be held by the caller.
+func (c *Calculator) stand(shoe Shoe, total int, up int) (ev float64) {
+	d, _ := c.dealerUp(shoe, up)
+	ev = d[DealerBust]
+	for r := Dealer17; r <= Dealer21; r++ {
+		switch dealer := int(r) + 17; {
+		case total > dealer:
+			ev += d[r]
+		case total < dealer:
+			ev -= d[r]
+		}
+	}
+	return ev
+}
+
+// hitEV returns the expected value of hitting a hand and then playing on as well as possible (hitting or standing),
+// given the dealer doesn't have blackjack.
+// The calculator lock must be held by the caller.
+func (c *Calculator) hitEV(shoe Shoe, hard int, ace bool, up int) (ev float64) {
+	key := playerKey{shoe: shoe, hard: hard, ace: ace, up: up}
+	if ev, exists := c.hit[key]; exists {
+		return ev
+	}
+	total := shoe.Len()
+	if total == 0 {
+		best, _ := bestTotal(hard, ace)
+		return c.stand(shoe, best, up)
+	}
+	for i, n := range shoe {
+		if n == 0 {
+			continue
+		}
+		nextHard, nextAce := hard+value(i), ace || i == 0
+		best, _ := bestTotal(nextHard, nextAce)
+		if best > 21 {
+			ev -= float64(n)
+			continue
+		}
+		shoe[i]--
+		next := c.stand(shoe, best, up)
+		if best < 21 {
+			next = math.Max(next, c.hitEV(shoe, nextHard, nextAce, up))
+		}
+		shoe[i]++
+		ev += float64(n) * next
+	}
+	ev /= float64(total)
+	c.hit[key] = ev
+	return ev
+}
+
+// double returns the expected value of doubling down (counting the doubled stake), given the dealer doesn't have
+// blackjack.
+// The calculator lock must be held by the caller.
+func (c *Calculator) double(shoe Shoe, hard int, ace bool, up int) (ev float64) {
+	total := shoe.Len()
+	if total == 0 {
+		return math.NaN()
+	}
+	for i, n := range shoe {
+		if n == 0 {
+			continue
+		}
+		best, _ := bestTotal(hard+value(i), ace || i == 0)
+		if best > 21 {
+			ev -= float64(n)
+			continue
+		}
+		shoe[i]--
+		ev += float64(n) * c.stand(shoe, best, up)
+		shoe[i]++
+	}
+	return 2 * ev / float64(total)
+}
+
+// split returns the expected value of splitting a pair of the cards at the given index of a Shoe (counting both
+// stakes), given the dealer doesn't have blackjack. Each hand is played as well as possible, but not resplit.
+// The calculator lock must be held by the caller.
+func (c *Calculator) split(shoe Shoe, pair int, up int) (ev float64) {
+	total := shoe.Len()
+	if total == 0 {
+		return math.NaN()
+	}
+	aces := pair == 0
+	for i, n := range shoe {
+		if n == 0 {
+			continue
+		}
+		hard, ace := value(pair)+value(i), aces || i == 0
+		best, _ := bestTotal(hard, ace)
+		shoe[i]--
+		// A split 21 isn't a natural.
+		hand := c.stand(shoe, best, up)
+		if !(aces && c.rules.Split.AcesOneCard) {
+			if best < 21 {
+				hand = math.Max(hand, c.hitEV(shoe, hard, ace, up))
+			}
+			if c.rules.Double.AfterSplit && c.rules.Double.Totals.Allows(best) {
+				hand = math.Max(hand, c.double(shoe, hard, ace, up))
+			}
+		}
+		shoe[i]++
+		ev += float64(n) * hand
+	}
+	return 2 * ev / float64(total)
+}
+
+// sameValue returns whether two cards make a pair that can be split.
+func sameValue(a playdeck.Card, b playdeck.Card, anyTens bool) bool {
+	if a.Value == b.Value {
+		return true
+	}
+	return anyTens && a.Value >= playdeck.ValueTen && b.Value >= playdeck.ValueTen
+}
@@ -0,0 +1,196 @@
+package odds
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"math"
+	"testing"
+)
+
+// oddsCards is a helper that parses a list of cards.
+func oddsCards(t *testing.T, s string) (cards []playdeck.Card) {
+	t.Helper()
+	cards, err := playdeck.ParseCards(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+// Test expected values from shoes small enough to work out by hand.
+func TestEVExact(t *testing.T) {
+	c := NewCalculator(blackjack.VegasStripRules)
+	tens := Shoe{9: 8}
+
+	// Against a 7 with nothing but tens to come, the dealer always makes 17.
+	ev, err := c.EV(tens, oddsCards(t, "TS TH"), oddsCard(t, "7S"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := EV{Stand: 1, Hit: -1, Double: -2, Split: 2, Surrender: -0.5}
+	if !sameEV(ev, expected) {
+		t.Errorf("expected %+v, got %+v", expected, ev)
+	}
+	if action, value := ev.Best(); action != strategy.ActionSplit || value != 2 {
+		t.Errorf("expected splitting to be best, got %s (%v)", action, value)
+	}
+
+	// 9 and 2 doubles into a sure 21.
+	ev, err = c.EV(tens, oddsCards(t, "9S 2H"), oddsCard(t, "7S"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Double != 2 || ev.Hit != 1 || ev.Stand != -1 {
+		t.Errorf("expected a sure double, got %+v", ev)
+	}
+
+	// A natural gets paid 3:2.
+	ev, err = c.EV(tens, oddsCards(t, "AS TH"), oddsCard(t, "7S"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Stand != 1.5 {
+		t.Errorf("expected a natural to be worth 1.5, got %v", ev.Stand)
+	}
+}
+
+// Test that a dealer blackjack is weighed up when the dealer doesn't peek.
+func TestEVNoPeek(t *testing.T) {
+	rules := blackjack.DefaultRules
+	rules.Surrender = blackjack.SurrenderLate
+	c := NewCalculator(rules)
+
+	// Half the time the dealer has a ten in the hole, and blackjack. The other half, a 7 makes them stand on a soft 18.
+	shoe := Shoe{6: 1, 9: 1}
+	ev, err := c.EV(shoe, oddsCards(t, "TS 9H"), oddsCard(t, "AS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Stand != 0 {
+		t.Errorf("expected standing on 19 to break even, got %v", ev.Stand)
+	}
+	if ev.Surrender != -0.75 {
+		t.Errorf("expected a late surrender to lose three quarters, got %v", ev.Surrender)
+	}
+	ev, err = c.EV(shoe, oddsCards(t, "AS TH"), oddsCard(t, "AS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Stand != 0.75 {
+		t.Errorf("expected a natural to push half the time, got %v", ev.Stand)
+	}
+}
+
+// Test expected values from a real shoe against well-known basic strategy decisions.
+func TestEVShoe(t *testing.T) {
+	c := NewCalculator(blackjack.VegasStripRules)
+	cases := []struct {
+		cards  string
+		up     string
+		action strategy.Action
+	}{
+		{"TS 6H", "TD", strategy.ActionSurrender},
+		{"TS 3H", "2D", strategy.ActionStand},
+		{"6S 5H", "6D", strategy.ActionDouble},
+		{"8S 8H", "9D", strategy.ActionSplit},
+		{"AS 7H", "9D", strategy.ActionHit},
+		{"TS 7H", "TD", strategy.ActionStand},
+	}
+	for _, tc := range cases {
+		cards, up := oddsCards(t, tc.cards), oddsCard(t, tc.up)
+		shoe, err := NewShoe(6).Remove(append(cards, up)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ev, err := c.EV(shoe, cards, up)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if action, _ := ev.Best(); action != tc.action {
+			t.Errorf("%s against %s: expected %s to be best, got %s (%+v)", tc.cards, tc.up, tc.action, action, ev)
+		}
+		again, _ := c.EV(shoe, cards, up)
+		if !sameEV(ev, again) {
+			t.Errorf("%s against %s: cached result doesn't match", tc.cards, tc.up)
+		}
+	}
+}
+
+// Test expected values for a Hand at a Table.
+func TestHandEV(t *testing.T) {
+	c := NewCalculator(blackjack.VegasStripRules)
+	_, err := c.HandEV(&blackjack.Hand{})
+	if !errors.Is(err, ErrInvalidHand) {
+		t.Errorf("didn't get appropriate error for a hand with no table, expected InvalidHand got %s", err)
+	}
+
+	table, err := blackjack.NewTableWithRules(blackjack.VegasStripRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.SetSource(playdeck.NewSeededSource(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := blackjack.NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	ev, err := c.HandEV(player.Hands[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.IsNaN(ev.Stand) || math.IsNaN(ev.Hit) {
+		t.Errorf("expected standing and hitting to have values, got %+v", ev)
+	}
+
+	_, err = c.EV(NewShoe(1), oddsCards(t, "TS TH 5C"), oddsCard(t, "7S"))
+	if !errors.Is(err, ErrInvalidHand) {
+		t.Errorf("didn't get appropriate error for a bust hand, expected InvalidHand got %s", err)
+	}
+	_, err = c.EV(NewShoe(1), oddsCards(t, "TS"), oddsCard(t, "7S"))
+	if !errors.Is(err, ErrInvalidHand) {
+		t.Errorf("didn't get appropriate error for a single card, expected InvalidHand got %s", err)
+	}
+	_, err = c.EV(NewShoe(1), oddsCards(t, "TS TH"), oddsCard(t, "Jkr"))
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("didn't get appropriate error for a joker, expected InvalidCard got %s", err)
+	}
+}
+
+// Test that split aces that only get one card can only be stood on or re-split, like at the Table.
+func TestEVSplitAces(t *testing.T) {
+	rules := blackjack.VegasStripRules
+	rules.Split.ResplitAces = true
+	ev, err := NewCalculator(rules).ev(NewShoe(1), oddsCards(t, "AS AH"), oddsCard(t, "6S"), true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(ev.Hit) || !math.IsNaN(ev.Double) || math.IsNaN(ev.Stand) || math.IsNaN(ev.Split) {
+		t.Errorf("expected split aces to only stand or re-split, got %+v", ev)
+	}
+	rules.Split.AcesOneCard = false
+	ev, err = NewCalculator(rules).ev(NewShoe(1), oddsCards(t, "AS AH"), oddsCard(t, "6S"), true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.IsNaN(ev.Hit) || math.IsNaN(ev.Double) {
+		t.Errorf("expected split aces to draw when they're allowed more than one card, got %+v", ev)
+	}
+}
+
+// sameEV returns whether two EVs are the same, counting NaNs as equal.
+func sameEV(a EV, b EV) bool {
+	same := func(x float64, y float64) bool {
+		return x == y || (math.IsNaN(x) && math.IsNaN(y))
+	}
+	return same(a.Stand, b.Stand) && same(a.Hit, b.Hit) && same(a.Double, b.Double) && same(a.Split, b.Split) &&
+		same(a.Surrender, b.Surrender)
+}
@@ -0,0 +1,91 @@
+package odds
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+)
+
+// A Shoe is the composition of a set of cards, as far as blackjack cares: how many there are of each value. Suits don't
+// matter, and tens and picture cards are all worth 10.
+// Index 0 counts aces, 1 twos, and so on up to 9 for ten-value cards (see Index()).
+type Shoe [10]int
+
+// NewShoe returns the composition of the given number of standard 52-card decks.
+func NewShoe(decks int) (shoe Shoe) {
+	for i := range shoe {
+		shoe[i] = 4 * decks
+	}
+	shoe[9] = 16 * decks
+	return shoe
+}
+
+// ShoeOf returns the composition of the cards in a Deck. Jokers aren't counted.
+func ShoeOf(d *playdeck.Deck) (shoe Shoe) {
+	if d == nil {
+		return shoe
+	}
+	d.Lock()
+	defer d.Unlock()
+	if d.Cards == nil {
+		return shoe
+	}
+	for _, c := range *d.Cards {
+		if i, ok := Index(c); ok {
+			shoe[i]++
+		}
+	}
+	return shoe
+}
+
+// Index returns the index of a card's value in a Shoe, and false if it has no blackjack value (i.e. it's a joker).
+func Index(c playdeck.Card) (index int, ok bool) {
+	switch {
+	case c.Value == playdeck.ValueJoker || c.Value > playdeck.ValueKing:
+		return 0, false
+	case c.Value >= playdeck.ValueTen:
+		return 9, true
+	}
+	return int(c.Value) - 1, true
+}
+
+// Len returns the number of cards in the Shoe.
+func (s Shoe) Len() (count int) {
+	for _, n := range s {
+		count += n
+	}
+	return count
+}
+
+// Add returns the Shoe with the given cards added to it.
+// It returns an error wrapping ErrInvalidCard if any of them has no blackjack value.
+func (s Shoe) Add(cards ...playdeck.Card) (shoe Shoe, err error) {
+	for _, c := range cards {
+		i, ok := Index(c)
+		if !ok {
+			return s, fmt.Errorf("%w: %s", ErrInvalidCard, c.Notation())
+		}
+		s[i]++
+	}
+	return s, nil
+}
+
+// Remove returns the Shoe with the given cards taken out of it.
+// It returns an error wrapping ErrCardNotInShoe if any of them isn't there, or ErrInvalidCard if it has no blackjack value.
+func (s Shoe) Remove(cards ...playdeck.Card) (shoe Shoe, err error) {
+	for _, c := range cards {
+		i, ok := Index(c)
+		if !ok {
+			return s, fmt.Errorf("%w: %s", ErrInvalidCard, c.Notation())
+		}
+		if s[i] == 0 {
+			return s, fmt.Errorf("%w: %s", ErrCardNotInShoe, c.Notation())
+		}
+		s[i]--
+	}
+	return s, nil
+}
+
+// value returns the blackjack value of the cards at the given index of a Shoe, with aces as 1.
+func value(index int) int {
+	return index + 1
+}
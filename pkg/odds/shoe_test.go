@@ -0,0 +1,54 @@
+package odds
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// Test building shoes, and taking cards in and out of them.
+func TestShoe(t *testing.T) {
+	shoe := NewShoe(2)
+	if shoe.Len() != 104 || shoe[0] != 8 || shoe[9] != 32 {
+		t.Errorf("two decks have the wrong composition: %v", shoe)
+	}
+	if ShoeOf(playdeck.NewDeckOfDecks(2, true)) != shoe {
+		t.Errorf("composition of a real deck doesn't match")
+	}
+	if ShoeOf(nil).Len() != 0 {
+		t.Errorf("expected a nil deck to be empty")
+	}
+
+	cards, err := playdeck.ParseCards("AS KH QD 7C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shoe, err = shoe.Remove(cards...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shoe[0] != 7 || shoe[9] != 30 || shoe[6] != 7 {
+		t.Errorf("cards not removed correctly: %v", shoe)
+	}
+	shoe, err = shoe.Add(cards...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shoe != NewShoe(2) {
+		t.Errorf("cards not added back correctly: %v", shoe)
+	}
+
+	_, err = Shoe{}.Remove(cards[0])
+	if !errors.Is(err, ErrCardNotInShoe) {
+		t.Errorf("didn't get appropriate error when removing a missing card, expected CardNotInShoe got %s", err)
+	}
+	joker := playdeck.Card{Suit: playdeck.SuitJoker, Value: playdeck.ValueJoker}
+	_, err = shoe.Add(joker)
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("didn't get appropriate error when adding a joker, expected InvalidCard got %s", err)
+	}
+	_, err = shoe.Remove(joker)
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("didn't get appropriate error when removing a joker, expected InvalidCard got %s", err)
+	}
+}
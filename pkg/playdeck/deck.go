@@ -1,9 +1,7 @@
 package playdeck
 
 import (
-	"math/rand"
 	"sync"
-	"time"
 )
 
 // A Deck is a helper struct that contains a quantity of Cards.
@@ -11,6 +9,12 @@ import (
 type Deck struct {
 	sync.Mutex
 	Cards *[]Card
+	// Source is where random draws and shuffles get their randomness from. If nil, DefaultSource is used.
+	Source Source
+
+	// watchers are called with every card pulled from the Deck (see Watch()).
+	watchers    []watcher
+	nextWatcher uint64
 }
 
 // NewDeck returns a memory pointer to a new, standard, 52-card Deck.
@@ -47,6 +51,8 @@ func NewDeckOfDecks(count int, joker bool) *Deck {
 // PullRandomCard returns a random card from the Deck, if possible.
 // It returns an error if this is not possible for some reason (i.e the deck is empty).
 func (d *Deck) PullRandomCard() (card Card, err error) {
+	// Registered first, so that it runs once the lock's been released.
+	defer d.pulled(&card, &err)
 	// Take a mutex lock, as this operation mutates the state of the deck
 	d.Lock()
 	defer d.Unlock()
@@ -59,10 +65,7 @@ func (d *Deck) PullRandomCard() (card Card, err error) {
 		return card, ErrDeckEmpty
 	}
 
-	// Randomness could be seeded in other ways (discussion point?)
-	// lint call out use of weak RNG, suggest crypto/rand (but is that necessary?)
-	rand.Seed(time.Now().Unix()) // initialize PRNG
-	indexToPull := rand.Intn(len(*d.Cards))
+	indexToPull := d.source().Intn(len(*d.Cards))
 	card = (*d.Cards)[indexToPull]
 	// Useful one-liner for deletion: https://github.com/golang/go/wiki/SliceTricks#delete
 	*d.Cards = append((*d.Cards)[:indexToPull], (*d.Cards)[indexToPull+1:]...)
@@ -73,6 +76,8 @@ func (d *Deck) PullRandomCard() (card Card, err error) {
 // PullCard returns the first card on top of the Deck, if possible.
 // It returns an error if this is not possible for some reason (i.e the deck is empty).
 func (d *Deck) PullCard() (card Card, err error) {
+	// Registered first, so that it runs once the lock's been released.
+	defer d.pulled(&card, &err)
 	// Take a mutex lock, as this operation mutates the state of the deck
 	d.Lock()
 	defer d.Unlock()
@@ -109,6 +114,17 @@ func (d *Deck) PushCard(card Card) (err error) {
 	return
 }
 
+// Len returns the number of cards currently in the Deck. An uninitialized Deck has no cards.
+func (d *Deck) Len() (count int) {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.Cards == nil {
+		return 0
+	}
+	return len(*d.Cards)
+}
+
 // Shuffle randomly repositions all cards in the Deck. This may be useful if your game depends on having a linear deck chronology.
 // You might want to consider PullRandomCard() if you only need the deck to be pseudo-random.
 // It returns an error if this is not possible for some reason (i.e the deck is uninitialized)
@@ -124,10 +140,17 @@ func (d *Deck) Shuffle() (err error) {
 	if len(*d.Cards) == 0 {
 		return ErrDeckEmpty
 	}
-	// Randomness could be seeded in other ways (discussion point?)
-	rand.Seed(time.Now().Unix()) // initialize PRNG
-	rand.Shuffle(len(*d.Cards), func(i, j int) {
+	shuffle(d.source(), len(*d.Cards), func(i, j int) {
 		(*d.Cards)[i], (*d.Cards)[j] = (*d.Cards)[j], (*d.Cards)[i]
 	})
 	return
 }
+
+// source returns the Source this Deck should draw randomness from.
+// The deck lock must be held by the caller.
+func (d *Deck) source() Source {
+	if d.Source == nil {
+		return DefaultSource
+	}
+	return d.Source
+}
@@ -76,6 +76,9 @@ func TestDeckMutation(t *testing.T) {
 	if len(*deck.Cards) != 51 {
 		t.Errorf("deck of cards did not increase in size! expected 51 cards, got %v", len(*deck.Cards))
 	}
+	if deck.Len() != 51 {
+		t.Errorf("deck reported the wrong length, expected 51 got %v", deck.Len())
+	}
 }
 
 func TestDeckMutationErrorHandling(t *testing.T) {
@@ -92,6 +95,9 @@ func TestDeckMutationErrorHandling(t *testing.T) {
 	if !errors.Is(err, ErrDeckUninitialized) {
 		t.Errorf("wrong error when deck uninitialized, got %s", err)
 	}
+	if deck.Len() != 0 {
+		t.Errorf("uninitialized deck should have no cards, got %v", deck.Len())
+	}
 
 	// Now add an empty hand and check that pulling cards fails correctly
 	deck.Cards = new([]Card)
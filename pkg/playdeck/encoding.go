@@ -0,0 +1,160 @@
+package playdeck
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Cards and Decks implement the standard encoding interfaces, so they can be stored and sent over the wire:
+//   - As text (and so in JSON), a Card is its compact notation (e.g. "AS"), and a Deck is a list of them, top card first.
+//   - As binary, a Card is a single byte (its suit in the high four bits, and its value in the low four), and a Deck is
+//     one byte per card, top card first.
+//
+// These formats are stable; don't change them without a very good reason.
+
+// MarshalText returns the compact notation for this card (e.g. "AS"). Invalid cards can't be marshalled.
+func (c Card) MarshalText() (text []byte, err error) {
+	if !c.Valid() {
+		return nil, fmt.Errorf("%w: suit %v value %v", ErrInvalidCard, uint8(c.Suit), uint8(c.Value))
+	}
+	return []byte(c.Notation()), nil
+}
+
+// UnmarshalText parses a card from its compact notation (or anything else ParseCard() understands).
+func (c *Card) UnmarshalText(text []byte) (err error) {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return
+}
+
+// MarshalBinary returns this card as a single byte. Invalid cards can't be marshalled.
+func (c Card) MarshalBinary() (data []byte, err error) {
+	if !c.Valid() {
+		return nil, fmt.Errorf("%w: suit %v value %v", ErrInvalidCard, uint8(c.Suit), uint8(c.Value))
+	}
+	return []byte{c.toByte()}, nil
+}
+
+// UnmarshalBinary reads a card from a single byte, as written by MarshalBinary().
+func (c *Card) UnmarshalBinary(data []byte) (err error) {
+	if len(data) != 1 {
+		return fmt.Errorf("%w: expected 1 byte, got %v", ErrInvalidCard, len(data))
+	}
+	card, err := cardFromByte(data[0])
+	if err != nil {
+		return err
+	}
+	*c = card
+	return
+}
+
+// toByte packs this card into a single byte.
+func (c Card) toByte() byte {
+	return byte(c.Suit)<<4 | byte(c.Value)
+}
+
+// cardFromByte unpacks a card packed by toByte(), checking that it's valid.
+func cardFromByte(b byte) (card Card, err error) {
+	card = Card{Suit: CardSuit(b >> 4), Value: CardValue(b & 0x0F)}
+	if !card.Valid() {
+		return card, fmt.Errorf("%w: byte %#02x", ErrInvalidCard, b)
+	}
+	return card, nil
+}
+
+// MarshalText returns the cards in this Deck in compact notation, separated by spaces, top card first.
+func (d *Deck) MarshalText() (text []byte, err error) {
+	d.Lock()
+	defer d.Unlock()
+	if d.Cards == nil {
+		return nil, ErrDeckUninitialized
+	}
+	for _, c := range *d.Cards {
+		if !c.Valid() {
+			return nil, fmt.Errorf("%w: suit %v value %v", ErrInvalidCard, uint8(c.Suit), uint8(c.Value))
+		}
+	}
+	return []byte(FormatCards(*d.Cards)), nil
+}
+
+// UnmarshalText replaces the cards in this Deck with those parsed from the given text (see ParseCards()), top card first.
+func (d *Deck) UnmarshalText(text []byte) (err error) {
+	cards, err := ParseCards(string(text))
+	if err != nil {
+		return err
+	}
+	d.setCards(cards)
+	return
+}
+
+// MarshalJSON returns the cards in this Deck as a JSON array of compact notations (e.g. ["AS","TD"]), top card first.
+// An uninitialized Deck is null.
+func (d *Deck) MarshalJSON() (data []byte, err error) {
+	d.Lock()
+	defer d.Unlock()
+	if d.Cards == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(*d.Cards)
+}
+
+// UnmarshalJSON replaces the cards in this Deck with those in the given JSON array, top card first.
+// null leaves the Deck uninitialized.
+func (d *Deck) UnmarshalJSON(data []byte) (err error) {
+	var cards []Card
+	err = json.Unmarshal(data, &cards)
+	if err != nil {
+		return err
+	}
+	d.Lock()
+	defer d.Unlock()
+	if cards == nil {
+		d.Cards = nil
+		return
+	}
+	d.Cards = &cards
+	return
+}
+
+// MarshalBinary returns the cards in this Deck as one byte each, top card first.
+func (d *Deck) MarshalBinary() (data []byte, err error) {
+	d.Lock()
+	defer d.Unlock()
+	if d.Cards == nil {
+		return nil, ErrDeckUninitialized
+	}
+	data = make([]byte, len(*d.Cards))
+	for i, c := range *d.Cards {
+		if !c.Valid() {
+			return nil, fmt.Errorf("%w: suit %v value %v", ErrInvalidCard, uint8(c.Suit), uint8(c.Value))
+		}
+		data[i] = c.toByte()
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the cards in this Deck with those in the given data, as written by MarshalBinary().
+func (d *Deck) UnmarshalBinary(data []byte) (err error) {
+	cards := make([]Card, len(data))
+	for i, b := range data {
+		cards[i], err = cardFromByte(b)
+		if err != nil {
+			return err
+		}
+	}
+	d.setCards(cards)
+	return
+}
+
+// setCards replaces the cards in this Deck.
+func (d *Deck) setCards(cards []Card) {
+	d.Lock()
+	defer d.Unlock()
+	if cards == nil {
+		cards = []Card{}
+	}
+	d.Cards = &cards
+}
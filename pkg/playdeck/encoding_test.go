@@ -0,0 +1,123 @@
+package playdeck
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Test that every card survives a round trip through each encoding.
+func TestCardEncoding(t *testing.T) {
+	for _, c := range *NewDeck(true).Cards {
+		text, err := c.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromText Card
+		err = fromText.UnmarshalText(text)
+		if err != nil || fromText != c {
+			t.Errorf("%s didn't survive text encoding as %q, got %s (%v)", c.String(), text, fromText.String(), err)
+		}
+
+		data, err := c.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != 1 {
+			t.Errorf("%s encoded to %v bytes", c.String(), len(data))
+		}
+		var fromBinary Card
+		err = fromBinary.UnmarshalBinary(data)
+		if err != nil || fromBinary != c {
+			t.Errorf("%s didn't survive binary encoding as %x, got %s (%v)", c.String(), data, fromBinary.String(), err)
+		}
+	}
+
+	data, err := json.Marshal(Card{Suit: SuitSpade, Value: ValueAce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"AS"` {
+		t.Errorf("card encoded to JSON as %s", data)
+	}
+
+	bad := Card{Suit: 50, Value: 42}
+	_, err = bad.MarshalText()
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected InvalidCard marshalling a bad card, got %v", err)
+	}
+	_, err = bad.MarshalBinary()
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected InvalidCard marshalling a bad card, got %v", err)
+	}
+	err = bad.UnmarshalBinary([]byte{0xFF})
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected InvalidCard unmarshalling a bad byte, got %v", err)
+	}
+	err = bad.UnmarshalBinary([]byte{0x41, 0x41})
+	if !errors.Is(err, ErrInvalidCard) {
+		t.Errorf("expected InvalidCard unmarshalling two bytes, got %v", err)
+	}
+}
+
+// Test that a shuffled deck keeps its order through each encoding.
+func TestDeckEncoding(t *testing.T) {
+	deck := NewDeckOfDecks(2, true)
+	deck.Source = NewSeededSource(7)
+	err := deck.Shuffle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(deck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON := new(Deck)
+	err = json.Unmarshal(data, fromJSON)
+	if err != nil || !reflect.DeepEqual(*fromJSON.Cards, *deck.Cards) {
+		t.Errorf("deck didn't survive JSON encoding (%v)", err)
+	}
+
+	text, err := deck.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromText := new(Deck)
+	err = fromText.UnmarshalText(text)
+	if err != nil || !reflect.DeepEqual(*fromText.Cards, *deck.Cards) {
+		t.Errorf("deck didn't survive text encoding (%v)", err)
+	}
+
+	bin, err := deck.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bin) != 106 {
+		t.Errorf("deck encoded to %v bytes, expected one per card", len(bin))
+	}
+	fromBinary := new(Deck)
+	err = fromBinary.UnmarshalBinary(bin)
+	if err != nil || !reflect.DeepEqual(*fromBinary.Cards, *deck.Cards) {
+		t.Errorf("deck didn't survive binary encoding (%v)", err)
+	}
+
+	// Uninitialized decks.
+	data, err = json.Marshal(new(Deck))
+	if err != nil || string(data) != "null" {
+		t.Errorf("uninitialized deck encoded to JSON as %s (%v)", data, err)
+	}
+	err = json.Unmarshal(data, fromJSON)
+	if err != nil || fromJSON.Cards != nil {
+		t.Errorf("null didn't decode to an uninitialized deck (%v)", err)
+	}
+	_, err = new(Deck).MarshalBinary()
+	if !errors.Is(err, ErrDeckUninitialized) {
+		t.Errorf("expected DeckUninitialized, got %v", err)
+	}
+	err = json.Unmarshal([]byte(`["AS","XX"]`), fromJSON)
+	if !errors.Is(err, ErrInvalidNotation) {
+		t.Errorf("expected InvalidNotation, got %v", err)
+	}
+}
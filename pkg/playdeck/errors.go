@@ -6,4 +6,6 @@ import "errors"
 var (
 	ErrDeckEmpty         = errors.New("deck is empty")
 	ErrDeckUninitialized = errors.New("deck is uninitialized")
+	ErrInvalidNotation   = errors.New("invalid card notation")
+	ErrInvalidCard       = errors.New("card is invalid")
 )
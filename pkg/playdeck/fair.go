@@ -0,0 +1,104 @@
+package playdeck
+
+import (
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strconv"
+)
+
+// Provably fair shuffling works like so:
+//  1. The server picks a secret server seed (NewServerSeed()), and publishes a commitment to it (CommitSeed()) before
+//     anything is dealt.
+//  2. The player picks a client seed of their own, so that the server can't have chosen a seed that suits it.
+//  3. The deck is shuffled deterministically from both seeds (NewFairDeck()).
+//  4. Once the deck is finished with, the server reveals the server seed. Anybody can then check it against the
+//     commitment (VerifyCommitment()), and recompute the order of the deck (FairOrder()) to check what was dealt.
+
+// NewServerSeed returns a new, secret, server seed for a provably fair shuffle, as a hex string.
+func NewServerSeed() (seed string, err error) {
+	b := make([]byte, 32)
+	_, err = cryptorand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CommitSeed returns the commitment to publish for the given server seed before the shuffle (a hex SHA-256 hash of it).
+func CommitSeed(serverSeed string) (commitment string) {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyCommitment returns whether the given server seed is the one that was committed to.
+func VerifyCommitment(serverSeed string, commitment string) bool {
+	return subtle.ConstantTimeCompare([]byte(CommitSeed(serverSeed)), []byte(commitment)) == 1
+}
+
+// NewFairSource returns the deterministic Source that a provably fair shuffle draws from, derived from both seeds.
+// Like NewSeededSource(), it is not safe for concurrent use.
+func NewFairSource(serverSeed string, clientSeed string) Source {
+	return &fairSource{serverSeed: []byte(serverSeed), clientSeed: clientSeed}
+}
+
+// NewFairDeck returns a Deck containing the given number of standard decks, shuffled provably fairly from both seeds.
+func NewFairDeck(count int, joker bool, serverSeed string, clientSeed string) (deck *Deck, err error) {
+	deck = NewDeckOfDecks(count, joker)
+	deck.Source = NewFairSource(serverSeed, clientSeed)
+	err = deck.Shuffle()
+	if err != nil {
+		return nil, err
+	}
+	return deck, nil
+}
+
+// FairOrder recomputes the order of the cards in a Deck made by NewFairDeck(), top card first.
+// Use it with the revealed server seed to check the cards that were dealt.
+func FairOrder(count int, joker bool, serverSeed string, clientSeed string) (cards []Card, err error) {
+	deck, err := NewFairDeck(count, joker, serverSeed, clientSeed)
+	if err != nil {
+		return nil, err
+	}
+	return *deck.Cards, nil
+}
+
+// fairSource is a Source that draws from a stream of HMAC-SHA256(serverSeed, clientSeed:counter) blocks.
+type fairSource struct {
+	serverSeed []byte
+	clientSeed string
+	counter    uint64
+	buf        []byte
+}
+
+// next returns the next 64 random bits from the stream.
+func (f *fairSource) next() uint64 {
+	if len(f.buf) < 8 {
+		mac := hmac.New(sha256.New, f.serverSeed)
+		mac.Write([]byte(f.clientSeed + ":" + strconv.FormatUint(f.counter, 10)))
+		f.counter++
+		f.buf = mac.Sum(nil)
+	}
+	v := binary.BigEndian.Uint64(f.buf[:8])
+	f.buf = f.buf[8:]
+	return v
+}
+
+func (f *fairSource) Intn(n int) int {
+	if n <= 0 {
+		panic("playdeck: invalid argument to Intn")
+	}
+	// Throw away anything in the biased bit at the bottom of the range, so that every result is equally likely.
+	max := uint64(n)
+	threshold := (math.MaxUint64 - max + 1) % max
+	for {
+		v := f.next()
+		if v >= threshold {
+			return int(v % max)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package playdeck
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test the commit-reveal cycle of a provably fair shuffle.
+func TestFairShuffle(t *testing.T) {
+	serverSeed, err := NewServerSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitment := CommitSeed(serverSeed)
+	if !VerifyCommitment(serverSeed, commitment) {
+		t.Errorf("server seed doesn't match its own commitment")
+	}
+	if VerifyCommitment(serverSeed+"0", commitment) {
+		t.Errorf("a different server seed matched the commitment")
+	}
+
+	deck, err := NewFairDeck(2, false, serverSeed, "lucky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	order, err := FairOrder(2, false, serverSeed, "lucky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*deck.Cards, order) {
+		t.Errorf("recomputed order doesn't match the deck")
+	}
+	if reflect.DeepEqual(*NewDeckOfDecks(2, false).Cards, order) {
+		t.Errorf("fair deck wasn't shuffled")
+	}
+
+	other, err := FairOrder(2, false, serverSeed, "unlucky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(other, order) {
+		t.Errorf("changing the client seed didn't change the shuffle")
+	}
+
+	_, err = NewFairDeck(0, false, serverSeed, "lucky")
+	if err == nil {
+		t.Errorf("shouldn't be able to shuffle an empty deck")
+	}
+}
+
+// Test that the fair source stays in range.
+func TestFairSource(t *testing.T) {
+	src := NewFairSource("server", "client")
+	counts := make([]int, 3)
+	for i := 0; i < 300; i++ {
+		n := src.Intn(3)
+		if n < 0 || n >= 3 {
+			t.Fatalf("fair source returned %v, out of range [0, 3)", n)
+		}
+		counts[n]++
+	}
+	for i, c := range counts {
+		if c == 0 {
+			t.Errorf("fair source never returned %v", i)
+		}
+	}
+}
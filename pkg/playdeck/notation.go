@@ -0,0 +1,211 @@
+package playdeck
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Cards can be written in a compact notation of a value followed by a suit, e.g. "AS" (ace of spades), "Td" or "10d"
+// (ten of diamonds), or "Jkr" for a joker. Values are A, 2-9, T (or 10), J, Q and K, and suits are S, H, D and C
+// (or ♠, ♥, ♦ and ♣). Case doesn't matter when parsing.
+// They can also be written as a single Unicode playing card glyph, e.g. 🂡 (ace of spades).
+
+// jokerNotation is the compact notation for a joker.
+const jokerNotation = "Jkr"
+
+var valueNotation = map[CardValue]string{
+	ValueAce:   "A",
+	ValueTwo:   "2",
+	ValueThree: "3",
+	ValueFour:  "4",
+	ValueFive:  "5",
+	ValueSix:   "6",
+	ValueSeven: "7",
+	ValueEight: "8",
+	ValueNine:  "9",
+	ValueTen:   "T",
+	ValueJack:  "J",
+	ValueQueen: "Q",
+	ValueKing:  "K",
+}
+
+var suitNotation = map[CardSuit]string{
+	SuitClub:    "C",
+	SuitDiamond: "D",
+	SuitHeart:   "H",
+	SuitSpade:   "S",
+}
+
+var suitSymbols = map[string]CardSuit{
+	"♣": SuitClub, "♧": SuitClub,
+	"♦": SuitDiamond, "♢": SuitDiamond,
+	"♥": SuitHeart, "♡": SuitHeart,
+	"♠": SuitSpade, "♤": SuitSpade,
+}
+
+// The Unicode playing cards block has a row per suit, with the ace at 1, through to the king at 14 (skipping the knight at 12).
+var glyphSuitBase = map[CardSuit]rune{
+	SuitSpade:   0x1F0A0,
+	SuitHeart:   0x1F0B0,
+	SuitDiamond: 0x1F0C0,
+	SuitClub:    0x1F0D0,
+}
+
+// glyphBlackJoker is the glyph used for jokers. The red (U+1F0BF) and white (U+1F0DF) jokers are also understood when parsing.
+const glyphBlackJoker rune = 0x1F0CF
+
+// Notation returns the compact notation for this card (e.g. "AS" for the ace of spades, "TD" for the ten of diamonds,
+// or "Jkr" for a joker). Invalid cards return "??".
+func (c Card) Notation() string {
+	if c.Suit == SuitJoker || c.Value == ValueJoker {
+		if !c.Valid() {
+			return "??"
+		}
+		return jokerNotation
+	}
+	value, vok := valueNotation[c.Value]
+	suit, sok := suitNotation[c.Suit]
+	if !vok || !sok {
+		return "??"
+	}
+	return value + suit
+}
+
+// Glyph returns the Unicode playing card glyph for this card (e.g. "🂡" for the ace of spades).
+// Invalid cards return the Unicode replacement character.
+func (c Card) Glyph() string {
+	if c.Suit == SuitJoker || c.Value == ValueJoker {
+		if !c.Valid() {
+			return string(utf8.RuneError)
+		}
+		return string(glyphBlackJoker)
+	}
+	base, exists := glyphSuitBase[c.Suit]
+	if !exists || !c.Valid() {
+		return string(utf8.RuneError)
+	}
+	offset := rune(c.Value)
+	if c.Value >= ValueQueen {
+		// Skip the knight.
+		offset++
+	}
+	return string(base + offset)
+}
+
+// ParseCard parses a single card from its compact notation (e.g. "AS", "Td", "10h", "Jkr") or its Unicode glyph.
+// It returns an error wrapping ErrInvalidNotation if the card can't be understood.
+func ParseCard(s string) (card Card, err error) {
+	s = strings.TrimSpace(s)
+	if r, size := utf8.DecodeRuneInString(s); size == len(s) && size > 1 {
+		card, ok := parseGlyph(r)
+		if ok {
+			return card, nil
+		}
+	}
+	if strings.EqualFold(s, jokerNotation) || strings.EqualFold(s, "joker") {
+		return Card{Suit: SuitJoker, Value: ValueJoker}, nil
+	}
+	if len(s) < 2 {
+		return card, fmt.Errorf("%w: %q", ErrInvalidNotation, s)
+	}
+
+	// The suit's always the last character (which may be a multi-byte symbol).
+	r, size := utf8.DecodeLastRuneInString(s)
+	rawValue, rawSuit := strings.ToUpper(s[:len(s)-size]), string(unicode.ToUpper(r))
+	suit, exists := suitSymbols[rawSuit]
+	if !exists {
+		suit, exists = parseNotation(suitNotation, rawSuit)
+	}
+	if !exists {
+		return card, fmt.Errorf("%w: unknown suit in %q", ErrInvalidNotation, s)
+	}
+	if rawValue == "10" {
+		rawValue = "T"
+	}
+	value, exists := parseNotation(valueNotation, rawValue)
+	if !exists {
+		return card, fmt.Errorf("%w: unknown value in %q", ErrInvalidNotation, s)
+	}
+	return Card{Suit: suit, Value: value}, nil
+}
+
+// ParseCards parses a list of cards, separated by spaces or commas (e.g. "AS Td, 10h"). Unicode glyphs don't need separating.
+// It returns an error wrapping ErrInvalidNotation if any card can't be understood.
+func ParseCards(s string) (cards []Card, err error) {
+	for _, token := range strings.FieldsFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) {
+		// Split up any run of glyphs into individual cards.
+		for len(token) > 0 {
+			r, size := utf8.DecodeRuneInString(token)
+			if _, ok := parseGlyph(r); !ok {
+				break
+			}
+			card, err := ParseCard(token[:size])
+			if err != nil {
+				return nil, err
+			}
+			cards = append(cards, card)
+			token = token[size:]
+		}
+		if len(token) == 0 {
+			continue
+		}
+		card, err := ParseCard(token)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// ParseDeck parses a Deck from a list of cards (see ParseCards()), with the first card parsed on top of the Deck.
+func ParseDeck(s string) (deck *Deck, err error) {
+	cards, err := ParseCards(s)
+	if err != nil {
+		return nil, err
+	}
+	if cards == nil {
+		cards = []Card{}
+	}
+	return &Deck{Cards: &cards}, nil
+}
+
+// FormatCards returns the compact notation for a list of cards, separated by spaces (e.g. "AS TD 2H").
+func FormatCards(cards []Card) string {
+	notations := make([]string, len(cards))
+	for i, c := range cards {
+		notations[i] = c.Notation()
+	}
+	return strings.Join(notations, " ")
+}
+
+// parseNotation finds the key with the given notation in one of the notation maps.
+func parseNotation[K comparable](notation map[K]string, s string) (key K, exists bool) {
+	for k, n := range notation {
+		if n == s {
+			return k, true
+		}
+	}
+	return key, false
+}
+
+// parseGlyph returns the card represented by a Unicode playing card glyph, if it is one.
+func parseGlyph(r rune) (card Card, ok bool) {
+	switch r {
+	case 0x1F0BF, glyphBlackJoker, 0x1F0DF:
+		return Card{Suit: SuitJoker, Value: ValueJoker}, true
+	}
+	for suit, base := range glyphSuitBase {
+		offset := r - base
+		if offset < 1 || offset > 14 || offset == 12 {
+			continue
+		}
+		if offset > 12 {
+			offset--
+		}
+		return Card{Suit: suit, Value: CardValue(offset)}, true
+	}
+	return card, false
+}
@@ -0,0 +1,96 @@
+package playdeck
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCard(t *testing.T) {
+	cases := []struct {
+		notation string
+		expected Card
+	}{
+		{"AS", Card{Suit: SuitSpade, Value: ValueAce}},
+		{"as", Card{Suit: SuitSpade, Value: ValueAce}},
+		{"Td", Card{Suit: SuitDiamond, Value: ValueTen}},
+		{"10h", Card{Suit: SuitHeart, Value: ValueTen}},
+		{"2C", Card{Suit: SuitClub, Value: ValueTwo}},
+		{"qh", Card{Suit: SuitHeart, Value: ValueQueen}},
+		{"K♠", Card{Suit: SuitSpade, Value: ValueKing}},
+		{"9♢", Card{Suit: SuitDiamond, Value: ValueNine}},
+		{"Jkr", Card{Suit: SuitJoker, Value: ValueJoker}},
+		{" JKR ", Card{Suit: SuitJoker, Value: ValueJoker}},
+		{"🂡", Card{Suit: SuitSpade, Value: ValueAce}},
+		{"🂽", Card{Suit: SuitHeart, Value: ValueQueen}},
+		{"🃞", Card{Suit: SuitClub, Value: ValueKing}},
+		{"🃏", Card{Suit: SuitJoker, Value: ValueJoker}},
+	}
+	for _, c := range cases {
+		card, err := ParseCard(c.notation)
+		if err != nil {
+			t.Errorf("%q: %s", c.notation, err)
+			continue
+		}
+		if card != c.expected {
+			t.Errorf("%q: expected %s got %s", c.notation, c.expected.String(), card.String())
+		}
+	}
+
+	for _, bad := range []string{"", "A", "1S", "AX", "11S", "ZS", "🂬", "Joke"} {
+		_, err := ParseCard(bad)
+		if !errors.Is(err, ErrInvalidNotation) {
+			t.Errorf("%q: expected InvalidNotation got %v", bad, err)
+		}
+	}
+}
+
+// Test that every card in a deck survives formatting and parsing in both notations.
+func TestNotationRoundTrip(t *testing.T) {
+	for _, c := range *NewDeck(true).Cards {
+		for _, notation := range []string{c.Notation(), c.Glyph()} {
+			parsed, err := ParseCard(notation)
+			if err != nil {
+				t.Errorf("%s (%q): %s", c.String(), notation, err)
+				continue
+			}
+			if parsed != c {
+				t.Errorf("%q: expected %s got %s", notation, c.String(), parsed.String())
+			}
+		}
+	}
+
+	bad := Card{Suit: 50, Value: 42}
+	if bad.Notation() != "??" {
+		t.Errorf("bad card returned a notation of %s", bad.Notation())
+	}
+}
+
+func TestParseCards(t *testing.T) {
+	cards, err := ParseCards("AS Td,10h  🂡🂮 Jkr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FormatCards(cards) != "AS TD TH AS KS Jkr" {
+		t.Errorf("parsed the wrong cards, got %s", FormatCards(cards))
+	}
+	_, err = ParseCards("AS XX")
+	if !errors.Is(err, ErrInvalidNotation) {
+		t.Errorf("expected InvalidNotation got %v", err)
+	}
+
+	deck, err := ParseDeck("2C 3C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := deck.PullCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Notation() != "2C" || deck.Len() != 1 {
+		t.Errorf("deck parsed in the wrong order, drew %s with %v left", card.Notation(), deck.Len())
+	}
+	deck, err = ParseDeck("")
+	if err != nil || deck.Len() != 0 {
+		t.Errorf("expected an empty deck, got %v cards and %v", deck.Len(), err)
+	}
+}
@@ -0,0 +1,61 @@
+package playdeck
+
+import (
+	cryptorand "crypto/rand"
+	"math/big"
+	"math/rand"
+)
+
+// A Source is where a Deck gets its randomness from when drawing random cards and shuffling.
+// *math/rand.Rand satisfies this interface, as do the sources returned by NewSeededSource() and NewCryptoSource().
+type Source interface {
+	// Intn returns a random number in the half-open interval [0, n). It panics if n <= 0.
+	Intn(n int) int
+}
+
+// DefaultSource is the Source used by Decks that haven't been given one of their own.
+// It uses math/rand's global generator, which is randomly seeded at startup and safe for concurrent use.
+var DefaultSource Source = globalSource{}
+
+// globalSource is a Source backed by math/rand's top-level functions.
+type globalSource struct{}
+
+func (globalSource) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// NewSeededSource returns a deterministic Source that always produces the same sequence for the same seed.
+// Use this to make games reproducible (e.g. in tests or simulations). It is not safe for concurrent use, so don't share
+// one between Decks that are used from different goroutines.
+func NewSeededSource(seed int64) Source {
+	return rand.New(rand.NewSource(seed))
+}
+
+// NewCryptoSource returns a Source backed by crypto/rand, for when shuffles must not be predictable (e.g. real money play).
+// It is safe for concurrent use.
+func NewCryptoSource() Source {
+	return cryptoSource{}
+}
+
+// cryptoSource is a Source backed by crypto/rand.
+type cryptoSource struct{}
+
+func (cryptoSource) Intn(n int) int {
+	if n <= 0 {
+		panic("playdeck: invalid argument to Intn")
+	}
+	v, err := cryptorand.Int(cryptorand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// SWEng: crypto/rand only fails if the OS can't give us any randomness, and there's no sensible way to carry on.
+		panic(err)
+	}
+	return int(v.Int64())
+}
+
+// shuffle randomly repositions n items with a Fisher-Yates shuffle, drawing from the given Source.
+func shuffle(src Source, n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j := src.Intn(i + 1)
+		swap(i, j)
+	}
+}
@@ -0,0 +1,85 @@
+package playdeck
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test that decks with the same seed are shuffled and drawn from identically.
+func TestSeededSource(t *testing.T) {
+	a := NewDeckOfDecks(2, false)
+	b := NewDeckOfDecks(2, false)
+	a.Source = NewSeededSource(42)
+	b.Source = NewSeededSource(42)
+
+	for _, deck := range []*Deck{a, b} {
+		err := deck.Shuffle()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(*a.Cards, *b.Cards) {
+		t.Errorf("decks with the same seed were shuffled differently")
+	}
+	for i := 0; i < 10; i++ {
+		ca, err := a.PullRandomCard()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cb, err := b.PullRandomCard()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ca != cb {
+			t.Errorf("draw %v differed between decks with the same seed, got %s and %s", i, ca.String(), cb.String())
+		}
+	}
+
+	c := NewDeckOfDecks(2, false)
+	c.Source = NewSeededSource(43)
+	err := c.Shuffle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDeckOfDecks(2, false)
+	d.Source = NewSeededSource(42)
+	err = d.Shuffle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(*c.Cards, *d.Cards) {
+		t.Errorf("decks with different seeds were shuffled identically")
+	}
+}
+
+// Test that the crypto source stays in range, and shuffles without losing any cards.
+func TestCryptoSource(t *testing.T) {
+	src := NewCryptoSource()
+	for i := 0; i < 100; i++ {
+		n := src.Intn(5)
+		if n < 0 || n >= 5 {
+			t.Fatalf("crypto source returned %v, out of range [0, 5)", n)
+		}
+	}
+
+	deck := NewDeck(false)
+	deck.Source = src
+	err := deck.Shuffle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[Card]bool{}
+	for _, c := range *deck.Cards {
+		seen[c] = true
+	}
+	if len(seen) != 52 {
+		t.Errorf("shuffle lost cards, expected 52 unique cards got %v", len(seen))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("crypto source should panic on a bad argument, like math/rand does")
+		}
+	}()
+	src.Intn(0)
+}
@@ -0,0 +1,48 @@
+package playdeck
+
+import "sync"
+
+// watcher is a function watching the cards pulled from a Deck.
+type watcher struct {
+	id uint64
+	fn func(card Card)
+}
+
+// Watch registers a function to be called with every card pulled from this Deck (by PullCard() or PullRandomCard()),
+// returning a function that stops it. The function is called once the pull has finished, without the deck lock held,
+// so it's free to look at the Deck (e.g. to see how many cards are left).
+func (d *Deck) Watch(fn func(card Card)) (unwatch func()) {
+	d.Lock()
+	defer d.Unlock()
+	d.nextWatcher++
+	id := d.nextWatcher
+	d.watchers = append(d.watchers, watcher{id: id, fn: fn})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.Lock()
+			defer d.Unlock()
+			for i, w := range d.watchers {
+				if w.id == id {
+					d.watchers = append(d.watchers[:i:i], d.watchers[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
+
+// pulled tells this Deck's watchers about a card that's been pulled from it, unless pulling it failed.
+// It must be called without the deck lock held.
+func (d *Deck) pulled(card *Card, err *error) {
+	if *err != nil {
+		return
+	}
+	d.Lock()
+	watchers := d.watchers
+	d.Unlock()
+	for _, w := range watchers {
+		w.fn(*card)
+	}
+}
@@ -0,0 +1,60 @@
+package playdeck
+
+import (
+	"testing"
+)
+
+// Test that watchers see every card pulled from a Deck, and stop once they've unwatched.
+func TestDeckWatch(t *testing.T) {
+	deck, err := ParseDeck("AS 2H 3D 4C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seen []Card
+	left := -1
+	unwatch := deck.Watch(func(card Card) {
+		seen = append(seen, card)
+		// The deck lock isn't held, so the deck can be looked at.
+		left = deck.Len()
+	})
+
+	card, err := deck.PullCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 1 || seen[0] != card || left != 3 {
+		t.Errorf("expected the watcher to see %s with 3 cards left, saw %v with %v left", card.Notation(), FormatCards(seen), left)
+	}
+	card, err = deck.PullRandomCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 2 || seen[1] != card {
+		t.Errorf("expected the watcher to see %s, saw %v", card.Notation(), FormatCards(seen))
+	}
+
+	// Pushing cards back isn't a pull.
+	err = deck.PushCard(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwatch()
+	unwatch()
+	_, err = deck.PullCard()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected the watcher to stop after unwatching, saw %v", FormatCards(seen))
+	}
+
+	// Failed pulls aren't seen either.
+	empty := NewDeckOfDecks(0, false)
+	empty.Watch(func(card Card) {
+		t.Errorf("didn't expect to see %s pulled from an empty deck", card.Notation())
+	})
+	_, err = empty.PullCard()
+	if err == nil {
+		t.Errorf("expected an error pulling from an empty deck")
+	}
+}
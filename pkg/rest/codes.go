@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"net/http"
+)
+
+// CodeInternal is the code given to errors that aren't known to the API.
+const CodeInternal = "internal"
+
+// An errorCode is the stable code and HTTP status that an error is reported with.
+type errorCode struct {
+	err    error
+	code   string
+	status int
+}
+
+// errorCodes maps errors to their codes, which are the error's name in snake case without the Err (so ErrHandLocked is
+// "hand_locked"). Codes are part of the API, and mustn't change once they're out there.
+// Errors are matched in order, so the more specific ones come first; in particular, a PhaseError matches ErrWrongPhase as
+// well as the error saying what's wrong, which is more useful.
+var errorCodes = []errorCode{
+	// This package's own.
+	{ErrBadRequest, "bad_request", http.StatusBadRequest},
+	{ErrNotFound, "not_found", http.StatusNotFound},
+	{ErrMethodNotAllowed, "method_not_allowed", http.StatusMethodNotAllowed},
+	{ErrTableNotFound, "table_not_found", http.StatusNotFound},
+	{ErrPlayerNotFound, "player_not_found", http.StatusNotFound},
+	{ErrHandNotFound, "hand_not_found", http.StatusNotFound},
+	{ErrUnknownAction, "unknown_action", http.StatusBadRequest},
+
+	// Requests that don't make sense, whatever's going on at the Table.
+	{blackjack.ErrInvalidAmount, "invalid_amount", http.StatusBadRequest},
+	{blackjack.ErrInvalidRule, "invalid_rule", http.StatusBadRequest},
+	{blackjack.ErrInvalidCard, "invalid_card", http.StatusBadRequest},
+	{blackjack.ErrInvalidSnapshot, "invalid_snapshot", http.StatusBadRequest},
+
+	// Things the Table's rules (or the Player's bankroll) don't allow.
+	{blackjack.ErrHandBust, "hand_bust", http.StatusUnprocessableEntity},
+	{blackjack.ErrHandNotTwoCards, "hand_not_two_cards", http.StatusUnprocessableEntity},
+	{blackjack.ErrDoubleTotal, "double_total", http.StatusUnprocessableEntity},
+	{blackjack.ErrDoubleAfterSplit, "double_after_split", http.StatusUnprocessableEntity},
+	{blackjack.ErrSurrenderNotAllowed, "surrender_not_allowed", http.StatusUnprocessableEntity},
+	{blackjack.ErrSurrenderAfterSplit, "surrender_after_split", http.StatusUnprocessableEntity},
+	{blackjack.ErrHandNotNatural, "hand_not_natural", http.StatusUnprocessableEntity},
+	{blackjack.ErrSplitNotPair, "split_not_pair", http.StatusUnprocessableEntity},
+	{blackjack.ErrSplitNotAllowed, "split_not_allowed", http.StatusUnprocessableEntity},
+	{blackjack.ErrSplitLimit, "split_limit", http.StatusUnprocessableEntity},
+	{blackjack.ErrSplitAcesOneCard, "split_aces_one_card", http.StatusUnprocessableEntity},
+	{blackjack.ErrInsufficientFunds, "insufficient_funds", http.StatusUnprocessableEntity},
+	{blackjack.ErrBetBelowMinimum, "bet_below_minimum", http.StatusUnprocessableEntity},
+	{blackjack.ErrBetAboveMaximum, "bet_above_maximum", http.StatusUnprocessableEntity},
+	{blackjack.ErrFairShuffleOff, "fair_shuffle_off", http.StatusUnprocessableEntity},
+
+	// Things that can't be done right now, but might be later.
+	{blackjack.ErrHandLocked, "hand_locked", http.StatusConflict},
+	{blackjack.ErrHandNotLocked, "hand_not_locked", http.StatusConflict},
+	{blackjack.ErrNotYourTurn, "not_your_turn", http.StatusConflict},
+	{blackjack.ErrInsuranceClosed, "insurance_closed", http.StatusConflict},
+	{blackjack.ErrInsuranceDecided, "insurance_decided", http.StatusConflict},
+	{blackjack.ErrDealerNoHand, "dealer_no_hand", http.StatusConflict},
+	{blackjack.ErrPeekPending, "peek_pending", http.StatusConflict},
+	{blackjack.ErrNoPeekPending, "no_peek_pending", http.StatusConflict},
+	{blackjack.ErrTableInPlay, "table_in_play", http.StatusConflict},
+	{blackjack.ErrTableNotInPlay, "table_not_in_play", http.StatusConflict},
+	{blackjack.ErrTablePlayerAlreadyJoined, "table_player_already_joined", http.StatusConflict},
+	{blackjack.ErrRoundNotEnded, "round_not_ended", http.StatusConflict},
+	{blackjack.ErrRoundSettled, "round_settled", http.StatusConflict},
+	{blackjack.ErrShoeNotFinished, "shoe_not_finished", http.StatusConflict},
+	{blackjack.ErrReplayFinished, "replay_finished", http.StatusConflict},
+	{blackjack.ErrReplayStart, "replay_start", http.StatusConflict},
+	{blackjack.ErrPhaseTransition, "phase_transition", http.StatusConflict},
+	{blackjack.ErrWrongPhase, "wrong_phase", http.StatusConflict},
+
+	// Things that should never happen through the API, and mean something's gone wrong inside.
+	{blackjack.ErrHandNoPlayer, "hand_no_player", http.StatusInternalServerError},
+	{blackjack.ErrHandInvalid, "hand_invalid", http.StatusInternalServerError},
+	{blackjack.ErrHandDealer, "hand_dealer", http.StatusInternalServerError},
+	{blackjack.ErrPlayerNoTable, "player_no_table", http.StatusInternalServerError},
+	{blackjack.ErrPlayerInvalid, "player_invalid", http.StatusInternalServerError},
+	{blackjack.ErrFairCommitment, "fair_commitment", http.StatusInternalServerError},
+	{blackjack.ErrFairMismatch, "fair_mismatch", http.StatusInternalServerError},
+	{blackjack.ErrReplayMismatch, "replay_mismatch", http.StatusInternalServerError},
+}
+
+// Code returns the stable code (e.g. "hand_locked") and HTTP status that the given error is reported with.
+// Errors that aren't known give CodeInternal and a 500.
+func Code(err error) (code string, status int) {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code, c.status
+		}
+	}
+	return CodeInternal, http.StatusInternalServerError
+}
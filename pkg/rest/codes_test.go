@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// codesBlackjackErrors is a helper that reads the names of every error declared in the blackjack package's errors.go.
+func codesBlackjackErrors(t *testing.T) (names []string) {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "../blackjack/errors.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return names
+}
+
+// codesSnakeCase is a helper that turns an error's name into its code, e.g. ErrHandLocked into "hand_locked".
+func codesSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimPrefix(name, "Err") {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Test that every error the blackjack package can throw has its own code, named after it.
+func TestCodesCoverBlackjackErrors(t *testing.T) {
+	codes := map[string]bool{}
+	for _, c := range errorCodes {
+		if codes[c.code] {
+			t.Errorf("code %q is used more than once", c.code)
+		}
+		codes[c.code] = true
+	}
+	names := codesBlackjackErrors(t)
+	if len(names) == 0 {
+		t.Fatal("didn't find any errors in the blackjack package")
+	}
+	for _, name := range names {
+		if !codes[codesSnakeCase(name)] {
+			t.Errorf("%s has no code, expected %q", name, codesSnakeCase(name))
+		}
+	}
+}
+
+// Test that errors are given the right codes and statuses, however they're wrapped.
+func TestCode(t *testing.T) {
+	cases := []struct {
+		err    error
+		code   string
+		status int
+	}{
+		{blackjack.ErrHandLocked, "hand_locked", http.StatusConflict},
+		{fmt.Errorf("%w: no", blackjack.ErrInvalidRule), "invalid_rule", http.StatusBadRequest},
+		{blackjack.ErrSplitNotPair, "split_not_pair", http.StatusUnprocessableEntity},
+		{&blackjack.PhaseError{Action: "deal", Err: blackjack.ErrTableInPlay}, "table_in_play", http.StatusConflict},
+		{errors.Join(errors.New("first"), blackjack.ErrTableNotInPlay), "table_not_in_play", http.StatusConflict},
+		{ErrTableNotFound, "table_not_found", http.StatusNotFound},
+		{errors.New("something else"), CodeInternal, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		code, status := Code(c.err)
+		if code != c.code || status != c.status {
+			t.Errorf("%s: expected %s (%v), got %s (%v)", c.err, c.code, c.status, code, status)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package rest
+
+import "errors"
+
+// Errors throwable by this module.
+var (
+	ErrBadRequest       = errors.New("request body is invalid")
+	ErrNotFound         = errors.New("no such endpoint")
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	ErrTableNotFound    = errors.New("table not found")
+	ErrPlayerNotFound   = errors.New("player not found")
+	ErrHandNotFound     = errors.New("hand not found")
+	ErrUnknownAction    = errors.New("action is unknown")
+)
@@ -0,0 +1,411 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxBody is the largest request body the API reads.
+const maxBody = 1 << 20
+
+// A Handler serves blackjack Tables over a JSON REST API (see the package README for the endpoints). Tables are kept in
+// memory, for as long as the Handler's around.
+// Safe for use in asynchronous environments.
+type Handler struct {
+	mu     sync.Mutex
+	tables map[string]*entry
+	nextID int
+	mux    *http.ServeMux
+}
+
+// An entry is a Table being served. Its lock is held while handling each request for it, so that requests for the same
+// Table are handled one at a time, and each sees it as the last one left it.
+type entry struct {
+	sync.Mutex
+	id    string
+	table *blackjack.Table
+}
+
+// ErrorResponse is the JSON body sent with every error.
+type ErrorResponse struct {
+	// Code is the error's stable code (see Code()), for programs to act on.
+	Code string `json:"code"`
+	// Message describes the error, for people to read.
+	Message string `json:"message"`
+}
+
+// EndResponse is the JSON body sent when a round is ended and settled.
+type EndResponse struct {
+	Results []ResultState `json:"results"`
+	Table   TableState    `json:"table"`
+}
+
+// JoinRequest is the JSON body for joining a Table.
+type JoinRequest struct {
+	// Bankroll is what the new Player starts with.
+	Bankroll int `json:"bankroll"`
+}
+
+// BetRequest is the JSON body for placing a bet.
+type BetRequest struct {
+	Amount int `json:"amount"`
+}
+
+// ActionRequest is the JSON body for acting on a Hand.
+type ActionRequest struct {
+	// Action is one of "hit", "stick", "double", "split", "surrender", "insure", "decline-insurance" or "even-money".
+	Action string `json:"action"`
+	// Amount is the insurance to take, for "insure".
+	Amount int `json:"amount"`
+}
+
+// NewHandler returns a Handler serving no Tables.
+func NewHandler() (handler *Handler) {
+	h := &Handler{tables: map[string]*entry{}, mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /tables", h.createTable)
+	h.mux.HandleFunc("GET /tables", h.listTables)
+	h.mux.HandleFunc("GET /tables/{table}", h.withTable(h.getTable))
+	h.mux.HandleFunc("DELETE /tables/{table}", h.deleteTable)
+	h.mux.HandleFunc("POST /tables/{table}/players", h.withTable(h.join))
+	h.mux.HandleFunc("POST /tables/{table}/players/{seat}/bet", h.withTable(h.bet))
+	h.mux.HandleFunc("POST /tables/{table}/deal", h.withTable(h.deal))
+	h.mux.HandleFunc("POST /tables/{table}/peek", h.withTable(h.peek))
+	h.mux.HandleFunc("POST /tables/{table}/players/{seat}/hands/{hand}", h.withTable(h.act))
+	h.mux.HandleFunc("POST /tables/{table}/end", h.withTable(h.end))
+	return h
+}
+
+// ServeHTTP handles a request to the API. Requests that nothing's routed to get the mux's 404 or 405 (with its Allow
+// header), sent as an ErrorResponse like any other error.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, pattern := h.mux.Handler(r)
+	if pattern != "" {
+		h.mux.ServeHTTP(w, r)
+		return
+	}
+	unrouted := &unroutedWriter{header: http.Header{}}
+	handler.ServeHTTP(unrouted, r)
+	switch unrouted.status {
+	case http.StatusMethodNotAllowed:
+		w.Header().Set("Allow", unrouted.header.Get("Allow"))
+		writeError(w, fmt.Errorf("%w: %s %s", ErrMethodNotAllowed, r.Method, r.URL.Path))
+	case http.StatusNotFound:
+		writeError(w, fmt.Errorf("%w: %s %s", ErrNotFound, r.Method, r.URL.Path))
+	default:
+		// Anything else (e.g. a redirect to the clean path) is fine as it is.
+		handler.ServeHTTP(w, r)
+	}
+}
+
+// unroutedWriter catches what the mux would have sent for a request it has no route for, so that it can be sent as an
+// ErrorResponse instead.
+type unroutedWriter struct {
+	header http.Header
+	status int
+}
+
+// Header returns the headers the mux set, which aren't sent.
+func (u *unroutedWriter) Header() http.Header {
+	return u.header
+}
+
+// Write throws the mux's body away.
+func (u *unroutedWriter) Write(b []byte) (int, error) {
+	if u.status == 0 {
+		u.status = http.StatusOK
+	}
+	return len(b), nil
+}
+
+// WriteHeader notes the status the mux would have sent.
+func (u *unroutedWriter) WriteHeader(status int) {
+	if u.status == 0 {
+		u.status = status
+	}
+}
+
+// AddTable serves an existing Table, returning the ID it's served under.
+func (h *Handler) AddTable(t *blackjack.Table) (id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextID++
+	id = strconv.Itoa(h.nextID)
+	h.tables[id] = &entry{id: id, table: t}
+	return id
+}
+
+// Table returns the Table served under the given ID, or nil.
+func (h *Handler) Table(id string) (table *blackjack.Table) {
+	if e := h.entry(id); e != nil {
+		return e.table
+	}
+	return nil
+}
+
+// entry returns the entry for the Table with the given ID, or nil.
+func (h *Handler) entry(id string) *entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.tables[id]
+}
+
+// withTable wraps a handler for a single Table, looking it up and holding its lock while the handler runs. Errors from
+// the handler are sent as an ErrorResponse.
+func (h *Handler) withTable(fn func(w http.ResponseWriter, r *http.Request, e *entry) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		e := h.entry(r.PathValue("table"))
+		if e == nil {
+			writeError(w, fmt.Errorf("%w: %q", ErrTableNotFound, r.PathValue("table")))
+			return
+		}
+		e.Lock()
+		defer e.Unlock()
+		err := fn(w, r, e)
+		if err != nil {
+			writeError(w, err)
+		}
+	}
+}
+
+// createTable creates a Table playing by the RuleSet in the request body. Any rules left out are taken from
+// blackjack.DefaultRules, so an empty body gives a default Table.
+func (h *Handler) createTable(w http.ResponseWriter, r *http.Request) {
+	rules := blackjack.DefaultRules
+	err := readJSON(r, &rules, true)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	t, err := blackjack.NewTableWithRules(rules)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	id := h.AddTable(t)
+	w.Header().Set("Location", "/tables/"+id)
+	writeJSON(w, http.StatusCreated, newTableState(id, t))
+}
+
+// listTables describes every Table, in the order they were created.
+func (h *Handler) listTables(w http.ResponseWriter, _ *http.Request) {
+	h.mu.Lock()
+	entries := make([]*entry, 0, len(h.tables))
+	for _, e := range h.tables {
+		entries = append(entries, e)
+	}
+	h.mu.Unlock()
+	slices.SortFunc(entries, func(a, b *entry) int {
+		ai, _ := strconv.Atoi(a.id)
+		bi, _ := strconv.Atoi(b.id)
+		return ai - bi
+	})
+
+	states := make([]TableState, len(entries))
+	for i, e := range entries {
+		e.Lock()
+		states[i] = newTableState(e.id, e.table)
+		e.Unlock()
+	}
+	writeJSON(w, http.StatusOK, states)
+}
+
+// getTable describes a Table.
+func (h *Handler) getTable(w http.ResponseWriter, _ *http.Request, e *entry) error {
+	writeJSON(w, http.StatusOK, newTableState(e.id, e.table))
+	return nil
+}
+
+// deleteTable stops serving a Table.
+func (h *Handler) deleteTable(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	_, exists := h.tables[r.PathValue("table")]
+	delete(h.tables, r.PathValue("table"))
+	h.mu.Unlock()
+	if !exists {
+		writeError(w, fmt.Errorf("%w: %q", ErrTableNotFound, r.PathValue("table")))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// join seats a new Player at a Table, with the bankroll in the request body, and describes them.
+// Players can join once the last round's been settled, which clears its Hands away.
+func (h *Handler) join(w http.ResponseWriter, r *http.Request, e *entry) (err error) {
+	var req JoinRequest
+	err = readJSON(r, &req, true)
+	if err != nil {
+		return err
+	}
+	if req.Bankroll < 0 {
+		return fmt.Errorf("%w: bankroll must not be negative", blackjack.ErrInvalidAmount)
+	}
+	if e.table.CurrentPhase() == blackjack.PhaseIdle {
+		err = e.table.Reset()
+		if err != nil {
+			return err
+		}
+	}
+	p := blackjack.NewPlayer()
+	if req.Bankroll > 0 {
+		err = p.Deposit(req.Bankroll)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.table.Join(p)
+	if err != nil {
+		return err
+	}
+	state := newTableState(e.id, e.table)
+	w.Header().Set("Location", fmt.Sprintf("/tables/%s/players/%v", e.id, len(state.Players)-1))
+	writeJSON(w, http.StatusCreated, state.Players[len(state.Players)-1])
+	return nil
+}
+
+// bet places a Player's bet on the next round, and describes them.
+func (h *Handler) bet(w http.ResponseWriter, r *http.Request, e *entry) (err error) {
+	seat, p, err := player(r, e)
+	if err != nil {
+		return err
+	}
+	var req BetRequest
+	err = readJSON(r, &req, false)
+	if err != nil {
+		return err
+	}
+	err = p.PlaceBet(req.Amount)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, newTableState(e.id, e.table).Players[seat])
+	return nil
+}
+
+// deal deals the next round, and describes the Table.
+func (h *Handler) deal(w http.ResponseWriter, _ *http.Request, e *entry) (err error) {
+	errs := e.table.Deal()
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	writeJSON(w, http.StatusOK, newTableState(e.id, e.table))
+	return nil
+}
+
+// peek has the dealer check for blackjack, and describes the Table.
+func (h *Handler) peek(w http.ResponseWriter, _ *http.Request, e *entry) (err error) {
+	err = e.table.DealerPeek()
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, newTableState(e.id, e.table))
+	return nil
+}
+
+// act takes the action in the request body on one of a Player's Hands, and describes the Table.
+func (h *Handler) act(w http.ResponseWriter, r *http.Request, e *entry) (err error) {
+	_, p, err := player(r, e)
+	if err != nil {
+		return err
+	}
+	index, err := strconv.Atoi(r.PathValue("hand"))
+	p.RLock()
+	hands := p.Hands
+	p.RUnlock()
+	if err != nil || index < 0 || index >= len(hands) {
+		return fmt.Errorf("%w: %q", ErrHandNotFound, r.PathValue("hand"))
+	}
+	hand := hands[index]
+
+	var req ActionRequest
+	err = readJSON(r, &req, false)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(req.Action) {
+	case "hit":
+		err = hand.Hit()
+	case "stick":
+		err = hand.Stick()
+	case "double":
+		err = hand.DoubleDown()
+	case "split":
+		_, err = hand.Split()
+	case "surrender":
+		err = hand.Surrender()
+	case "insure":
+		err = hand.Insure(req.Amount)
+	case "decline-insurance":
+		err = hand.DeclineInsurance()
+	case "even-money":
+		err = hand.TakeEvenMoney()
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownAction, req.Action)
+	}
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, newTableState(e.id, e.table))
+	return nil
+}
+
+// end has the dealer play out the round and settles it, describing each Hand's Result and the Table.
+func (h *Handler) end(w http.ResponseWriter, _ *http.Request, e *entry) (err error) {
+	err = e.table.EndRound()
+	if err != nil {
+		return err
+	}
+	results, err := e.table.Settle()
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, EndResponse{
+		Results: newResultStates(e.table, results),
+		Table:   newTableState(e.id, e.table),
+	})
+	return nil
+}
+
+// player returns the Player in the request's seat.
+func player(r *http.Request, e *entry) (seat int, p *blackjack.Player, err error) {
+	seat, err = strconv.Atoi(r.PathValue("seat"))
+	if err != nil || seat < 0 || seat >= len(e.table.Players) {
+		return 0, nil, fmt.Errorf("%w: %q", ErrPlayerNotFound, r.PathValue("seat"))
+	}
+	return seat, e.table.Players[seat], nil
+}
+
+// readJSON decodes the request body into v. If optional is true, an empty body leaves v as it is.
+// It returns an error wrapping ErrBadRequest if the body isn't valid JSON, or has fields v doesn't.
+func readJSON(r *http.Request, v any, optional bool) (err error) {
+	d := json.NewDecoder(io.LimitReader(r.Body, maxBody))
+	d.DisallowUnknownFields()
+	err = d.Decode(v)
+	if errors.Is(err, io.EOF) && optional {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBadRequest, err)
+	}
+	return nil
+}
+
+// writeJSON sends v as the response body, with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends an ErrorResponse describing err, with its status (see Code()).
+func writeError(w http.ResponseWriter, err error) {
+	code, status := Code(err)
+	writeJSON(w, status, ErrorResponse{Code: code, Message: err.Error()})
+}
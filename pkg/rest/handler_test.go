@@ -0,0 +1,182 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// restDo is a helper that sends a request to the handler, decoding the JSON response into v (if it isn't nil). Error
+// responses are only decoded into an *ErrorResponse, and successful ones into anything else.
+func restDo(t *testing.T, h *Handler, method string, path string, body string, v any) (status int) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	_, wantError := v.(*ErrorResponse)
+	if v != nil && w.Body.Len() > 0 && wantError == (w.Code >= 300) {
+		err := json.Unmarshal(w.Body.Bytes(), v)
+		if err != nil {
+			t.Fatalf("%s %s: %s", method, path, err)
+		}
+	}
+	return w.Code
+}
+
+// restTable is a helper that creates a seeded table with the given rules, seating players with 100 each.
+func restTable(t *testing.T, h *Handler, rules string, players int) (id string) {
+	t.Helper()
+	var state TableState
+	if status := restDo(t, h, "POST", "/tables", rules, &state); status != http.StatusCreated {
+		t.Fatalf("couldn't create a table, got %v", status)
+	}
+	err := h.Table(state.ID).SetSource(playdeck.NewSeededSource(1234))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < players; i++ {
+		var p PlayerState
+		if status := restDo(t, h, "POST", "/tables/"+state.ID+"/players", `{"bankroll":100}`, &p); status != http.StatusCreated {
+			t.Fatalf("couldn't join the table, got %v", status)
+		}
+		if p.Seat != i || p.Bankroll != 100 {
+			t.Errorf("expected seat %v with 100, got %+v", i, p)
+		}
+	}
+	return state.ID
+}
+
+// Test a whole round played over the API.
+func TestHandlerRound(t *testing.T) {
+	h := NewHandler()
+	id := restTable(t, h, `{"decks":2,"minBet":5}`, 2)
+	var state TableState
+	restDo(t, h, "GET", "/tables/"+id, "", &state)
+	if state.Rules.Decks != 2 || state.Rules.MinBet != 5 || state.Rules.BlackjackPayout.Pays != 3 {
+		t.Errorf("table doesn't play by the rules asked for (and the defaults), got %+v", state.Rules)
+	}
+
+	for _, seat := range []string{"0", "1"} {
+		var p PlayerState
+		if status := restDo(t, h, "POST", "/tables/"+id+"/players/"+seat+"/bet", `{"amount":10}`, &p); status != http.StatusOK {
+			t.Fatalf("couldn't bet, got %v", status)
+		}
+		if p.Bet != 10 || p.Bankroll != 90 {
+			t.Errorf("expected a bet of 10 from 100, got %+v", p)
+		}
+	}
+	restDo(t, h, "POST", "/tables/"+id+"/deal", "", &state)
+	if state.Phase != "player turns" || len(state.Players) != 2 {
+		t.Fatalf("expected player turns with two players, got %+v", state)
+	}
+	if len(state.Dealer.Cards) != 1 || !state.Dealer.HoleCard || state.Dealer.Score != 0 {
+		t.Errorf("dealer's hole card should be secret, got %+v", state.Dealer)
+	}
+	if state.Turn == nil || state.Turn.Seat != 0 || state.Turn.Hand != 0 {
+		t.Errorf("expected seat 0's turn, got %+v", state.Turn)
+	}
+	if h := state.Players[0].Hands[0]; len(h.Cards) != 2 || h.Wager != 10 || h.Outcome != "pending" {
+		t.Errorf("expected a pending hand of two cards with 10 on it, got %+v", h)
+	}
+
+	var e ErrorResponse
+	if status := restDo(t, h, "POST", "/tables/"+id+"/players/1/hands/0", `{"action":"hit"}`, &e); status != http.StatusConflict || e.Code != "not_your_turn" {
+		t.Errorf("expected not_your_turn, got %v %+v", status, e)
+	}
+	for _, seat := range []string{"0", "1"} {
+		if status := restDo(t, h, "POST", "/tables/"+id+"/players/"+seat+"/hands/0", `{"action":"stick"}`, &state); status != http.StatusOK {
+			t.Fatalf("couldn't stick, got %v", status)
+		}
+	}
+
+	var end EndResponse
+	if status := restDo(t, h, "POST", "/tables/"+id+"/end", "", &end); status != http.StatusOK {
+		t.Fatalf("couldn't end the round, got %v", status)
+	}
+	if len(end.Results) != 2 || end.Table.Phase != "idle" {
+		t.Fatalf("expected two results and an idle table, got %+v", end)
+	}
+	if len(end.Table.Dealer.Cards) < 2 || end.Table.Dealer.HoleCard || end.Table.Dealer.Score == 0 {
+		t.Errorf("dealer's hand should be shown once the round's over, got %+v", end.Table.Dealer)
+	}
+	for i, r := range end.Results {
+		p := end.Table.Players[i]
+		if r.Seat != i || r.Hand != 0 || r.Outcome != p.Hands[0].Outcome || r.Outcome == "pending" {
+			t.Errorf("result %v doesn't match its hand, got %+v and %+v", i, r, p.Hands[0])
+		}
+		if p.Bankroll != 100+r.Net {
+			t.Errorf("seat %v should have %v after netting %v, got %v", i, 100+r.Net, r.Net, p.Bankroll)
+		}
+	}
+
+	// Players can join between rounds.
+	var p PlayerState
+	if status := restDo(t, h, "POST", "/tables/"+id+"/players", "", &p); status != http.StatusCreated || p.Seat != 2 {
+		t.Errorf("expected to join in seat 2, got %v %+v", status, p)
+	}
+	var tables []TableState
+	restDo(t, h, "GET", "/tables", "", &tables)
+	if len(tables) != 1 || tables[0].ID != id || len(tables[0].Players) != 3 {
+		t.Errorf("expected the one table with three players, got %+v", tables)
+	}
+}
+
+// Test that errors are reported with their codes and statuses.
+func TestHandlerErrors(t *testing.T) {
+	h := NewHandler()
+	id := restTable(t, h, "", 1)
+	table := "/tables/" + id
+	cases := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"GET", "/nowhere", "", http.StatusNotFound, "not_found"},
+		{"GET", table + "/deal", "", http.StatusMethodNotAllowed, "method_not_allowed"},
+		{"PUT", table, "", http.StatusMethodNotAllowed, "method_not_allowed"},
+		{"GET", "/tables/99", "", http.StatusNotFound, "table_not_found"},
+		{"POST", "/tables", "{", http.StatusBadRequest, "bad_request"},
+		{"POST", "/tables", `{"colour":"green"}`, http.StatusBadRequest, "bad_request"},
+		{"POST", "/tables", `{"decks":0}`, http.StatusBadRequest, "invalid_rule"},
+		{"POST", table + "/players", `{"bankroll":-5}`, http.StatusBadRequest, "invalid_amount"},
+		{"POST", table + "/players/5/bet", `{"amount":10}`, http.StatusNotFound, "player_not_found"},
+		{"POST", table + "/players/0/bet", `{"amount":-1}`, http.StatusBadRequest, "invalid_amount"},
+		{"POST", table + "/players/0/bet", `{"amount":1000}`, http.StatusUnprocessableEntity, "insufficient_funds"},
+		{"POST", table + "/players/0/hands/0", `{"action":"hit"}`, http.StatusNotFound, "hand_not_found"},
+		{"POST", table + "/end", "", http.StatusConflict, "table_not_in_play"},
+		{"POST", table + "/peek", "", http.StatusConflict, "table_not_in_play"},
+		{"POST", table + "/players/0/bet", `{"amount":10}`, http.StatusOK, ""},
+		{"POST", table + "/deal", "", http.StatusOK, ""},
+		{"POST", table + "/deal", "", http.StatusConflict, "table_in_play"},
+		{"POST", table + "/players", "", http.StatusConflict, "table_in_play"},
+		{"POST", table + "/peek", "", http.StatusConflict, "no_peek_pending"},
+		{"POST", table + "/end", "", http.StatusConflict, "hand_not_locked"},
+		{"POST", table + "/players/0/hands/0", `{"action":"dance"}`, http.StatusBadRequest, "unknown_action"},
+		{"POST", table + "/players/0/hands/0", `{"action":"surrender"}`, http.StatusUnprocessableEntity, "surrender_not_allowed"},
+		{"POST", table + "/players/0/hands/0", `{"action":"insure","amount":5}`, http.StatusConflict, "insurance_closed"},
+		{"POST", table + "/players/0/hands/0", `{"action":"stick"}`, http.StatusOK, ""},
+		{"POST", table + "/players/0/hands/0", `{"action":"hit"}`, http.StatusConflict, "hand_locked"},
+		{"POST", table + "/end", "", http.StatusOK, ""},
+		{"POST", table + "/end", "", http.StatusConflict, "table_not_in_play"},
+		{"DELETE", table, "", http.StatusNoContent, ""},
+		{"GET", table, "", http.StatusNotFound, "table_not_found"},
+		{"DELETE", table, "", http.StatusNotFound, "table_not_found"},
+	}
+	for _, c := range cases {
+		var e ErrorResponse
+		status := restDo(t, h, c.method, c.path, c.body, &e)
+		if status != c.status || e.Code != c.code {
+			t.Errorf("%s %s %s: expected %v %q, got %v %q (%s)", c.method, c.path, c.body, c.status, c.code, status, e.Code, e.Message)
+		}
+	}
+	// A wrong method says which ones would do.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/tables/1/deal", nil))
+	if allow := w.Header().Get("Allow"); allow != "POST" {
+		t.Errorf("expected a wrong method to be told POST is allowed, got %q", allow)
+	}
+}
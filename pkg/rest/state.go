@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"time"
+)
+
+// TableState is the JSON form of a Table, as a player sees it: everything but the shoe, and the dealer's hole card
+// until the round's over.
+type TableState struct {
+	ID    string            `json:"id"`
+	Rules blackjack.RuleSet `json:"rules"`
+	// Phase is the name of the round's Phase (e.g. "player turns").
+	Phase       string `json:"phase"`
+	PeekPending bool   `json:"peekPending"`
+	// Turn is the Hand whose turn it is, if there is one.
+	Turn *TurnState `json:"turn,omitempty"`
+	// Deadline is when the Hand (or Hands) being played run out of time, if there's a clock running.
+	Deadline       *time.Time    `json:"deadline,omitempty"`
+	CardsRemaining int           `json:"cardsRemaining"`
+	Dealer         DealerState   `json:"dealer"`
+	Players        []PlayerState `json:"players"`
+}
+
+// TurnState points to the Hand whose turn it is.
+type TurnState struct {
+	Seat int `json:"seat"`
+	Hand int `json:"hand"`
+}
+
+// DealerState is the JSON form of the dealer's Hand.
+type DealerState struct {
+	// Cards are the dealer's face up cards.
+	Cards []playdeck.Card `json:"cards"`
+	// HoleCard is true while the dealer has a card face down, which isn't in Cards.
+	HoleCard bool `json:"holeCard"`
+	// Score is the dealer's score, once every card's face up.
+	Score int `json:"score,omitempty"`
+}
+
+// PlayerState is the JSON form of a Player.
+type PlayerState struct {
+	Seat     int         `json:"seat"`
+	Bankroll int         `json:"bankroll"`
+	Bet      int         `json:"bet"`
+	Hands    []HandState `json:"hands"`
+}
+
+// HandState is the JSON form of a Hand: its HandSnapshot, and how it fared once the round's over.
+type HandState struct {
+	blackjack.HandSnapshot
+	// Outcome is the name of the Hand's Outcome (e.g. "win"), or "pending" until the round's ended.
+	Outcome string `json:"outcome"`
+}
+
+// ResultState is the JSON form of a settled Hand's Result.
+type ResultState struct {
+	Seat            int    `json:"seat"`
+	Hand            int    `json:"hand"`
+	Outcome         string `json:"outcome"`
+	Wager           int    `json:"wager"`
+	Payout          int    `json:"payout"`
+	Insurance       int    `json:"insurance"`
+	InsurancePayout int    `json:"insurancePayout"`
+	Net             int    `json:"net"`
+}
+
+// newTableState describes a Table.
+func newTableState(id string, t *blackjack.Table) (state TableState) {
+	snap := t.Snapshot()
+	state = TableState{
+		ID:             id,
+		Rules:          snap.Rules,
+		Phase:          snap.Phase.String(),
+		PeekPending:    snap.Phase == blackjack.PhaseInsurance,
+		CardsRemaining: t.CardsRemaining(),
+		Dealer:         DealerState{Cards: []playdeck.Card{}},
+		Players:        make([]PlayerState, len(snap.Players)),
+	}
+	if at, ok := t.Deadline(); ok {
+		state.Deadline = &at
+	}
+
+	// The hole card stays secret until the round's over, just as with Table.DealerHand().
+	ended := snap.Phase == blackjack.PhaseSettlement || snap.Phase == blackjack.PhaseIdle
+	if len(snap.Dealer) > 0 {
+		dealer := snap.Dealer[0]
+		if ended || len(dealer.Cards) < 2 {
+			state.Dealer.Cards = dealer.Cards
+		} else {
+			state.Dealer.Cards = dealer.Cards[:1]
+			state.Dealer.HoleCard = true
+		}
+		if ended {
+			state.Dealer.Score = dealer.Score
+		}
+	}
+
+	active := t.ActiveHand()
+	for i, p := range snap.Players {
+		player := t.Players[i]
+		player.RLock()
+		hands := player.Hands
+		player.RUnlock()
+		state.Players[i] = PlayerState{Seat: i, Bankroll: p.Bankroll, Bet: p.Bet, Hands: make([]HandState, len(p.Hands))}
+		for j, h := range p.Hands {
+			hand := HandState{HandSnapshot: h, Outcome: blackjack.OutcomePending.String()}
+			if j < len(hands) {
+				if hands[j] == active {
+					state.Turn = &TurnState{Seat: i, Hand: j}
+				}
+				if ended {
+					if outcome, err := hands[j].Outcome(); err == nil {
+						hand.Outcome = outcome.String()
+					}
+				}
+			}
+			state.Players[i].Hands[j] = hand
+		}
+	}
+	return state
+}
+
+// newResultStates describes the Results of settling a Table's round.
+func newResultStates(t *blackjack.Table, results []blackjack.Result) (states []ResultState) {
+	states = make([]ResultState, len(results))
+	for i, r := range results {
+		states[i] = ResultState{
+			Seat:            -1,
+			Hand:            -1,
+			Outcome:         r.Outcome.String(),
+			Wager:           r.Wager,
+			Payout:          r.Payout,
+			Insurance:       r.Insurance,
+			InsurancePayout: r.InsurancePayout,
+			Net:             r.Net,
+		}
+		for seat, p := range t.Players {
+			if p != r.Player {
+				continue
+			}
+			states[i].Seat = seat
+			p.RLock()
+			for j, h := range p.Hands {
+				if h == r.Hand {
+					states[i].Hand = j
+				}
+			}
+			p.RUnlock()
+		}
+	}
+	return states
+}
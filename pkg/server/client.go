@@ -0,0 +1,256 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// outBuffer is the number of lines that can be waiting to be sent to a client. Clients that fall this far behind are
+// disconnected, rather than holding up everyone else at their table.
+const outBuffer = 256
+
+// writeTimeout is how long a client has to accept each line sent to it before it's disconnected.
+const writeTimeout = 10 * time.Second
+
+// A client is a connection being served by a Server.
+type client struct {
+	server *Server
+	conn   net.Conn
+
+	// mu guards out, which is closed (and closed set) once nothing more is to be sent.
+	mu     sync.Mutex
+	out    chan string
+	closed bool
+	// written is closed once everything sent has been written out.
+	written chan struct{}
+
+	// room and seat are where the client's sat, if anywhere. Only touched by the goroutine reading from the client.
+	room *room
+	seat *seat
+}
+
+// newClient returns a client for the given connection.
+func newClient(s *Server, conn net.Conn) *client {
+	return &client{server: s, conn: conn, out: make(chan string, outBuffer), written: make(chan struct{})}
+}
+
+// serve reads and carries out commands from the client, until it goes away (or quits). The client is then stood up
+// from its seat, and its connection closed.
+func (c *client) serve() {
+	go c.write()
+	c.send("hello tables=" + strings.Join(c.server.Tables(), ","))
+
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		if c.handle(parseCommand(scanner.Text())) {
+			break
+		}
+	}
+
+	if c.seat != nil {
+		c.room.leave(c.seat)
+	}
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.out)
+	}
+	c.mu.Unlock()
+	<-c.written
+	_ = c.conn.Close()
+}
+
+// write writes out every line sent to the client, in order. If the connection fails, it's closed, and the rest are thrown
+// away.
+func (c *client) write() {
+	defer close(c.written)
+	failed := false
+	for line := range c.out {
+		if failed {
+			continue
+		}
+		_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		_, err := io.WriteString(c.conn, line+"\n")
+		if err != nil {
+			failed = true
+			_ = c.conn.Close()
+		}
+	}
+}
+
+// send queues a line to be sent to the client. Clients that have fallen too far behind are disconnected.
+func (c *client) send(line string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.out <- line:
+	default:
+		c.closed = true
+		close(c.out)
+		_ = c.conn.Close()
+	}
+}
+
+// close disconnects the client.
+func (c *client) close() {
+	_ = c.conn.Close()
+}
+
+// handle carries out a command, sending the client its reply. It returns true if the client has quit.
+func (c *client) handle(cmd command) (quit bool) {
+	var err error
+	switch cmd.name {
+	case "":
+		return false
+	case "quit":
+		c.send("ok quit")
+		return true
+	case "join":
+		err = c.join(cmd.args)
+	case "bet":
+		err = c.bet(cmd.args)
+	case "hit", "stick", "double", "split":
+		err = c.play(cmd)
+	case "status":
+		err = c.status(cmd.args)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownCommand, cmd.name)
+	}
+	if err != nil {
+		c.send("err " + err.Error())
+	}
+	return false
+}
+
+// join sits the client down at a table: "join <table> <name>". The reply carries a reconnect token, and joining with the
+// name of someone who's disconnected and their token, "join <table> <name> <token>", gives the client their seat (and
+// bankroll) back.
+func (c *client) join(args []string) (err error) {
+	if len(args) != 2 && len(args) != 3 {
+		return fmt.Errorf("%w: expected join <table> <name> [token]", ErrBadArguments)
+	}
+	if c.seat != nil {
+		return ErrAlreadyJoined
+	}
+	r := c.server.room(args[0])
+	if r == nil {
+		return fmt.Errorf("%w: %q", ErrUnknownTable, args[0])
+	}
+	token := ""
+	if len(args) == 3 {
+		token = args[2]
+	}
+	r.Lock()
+	s, err := r.join(c, args[1], token, c.server.bankroll())
+	r.Unlock()
+	if err != nil {
+		return err
+	}
+	c.room, c.seat = r, s
+	c.send(fmt.Sprintf("ok join table=%s seat=%v bankroll=%v token=%s", r.name, r.seatOf(s), s.player.Bankroll(), s.token))
+	r.advance()
+	return nil
+}
+
+// bet places the client's bet on the next round: "bet <amount>". A bet of 0 sits the round out. The round is dealt as
+// soon as everyone connected at the table has bet, or once the betting clock runs out (see room.startBetting).
+func (c *client) bet(args []string) (err error) {
+	if c.seat == nil {
+		return ErrNotJoined
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected bet <amount>", ErrBadArguments)
+	}
+	amount, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %q is not an amount", ErrBadArguments, args[0])
+	}
+	r := c.room
+	r.Lock()
+	err = c.seat.player.PlaceBet(amount)
+	if err == nil {
+		r.seatsMu.Lock()
+		c.seat.ready = true
+		r.seatsMu.Unlock()
+	}
+	r.Unlock()
+	if err != nil {
+		return err
+	}
+	c.send(fmt.Sprintf("ok bet amount=%v", amount))
+	r.advance()
+	return nil
+}
+
+// play takes an action on one of the client's hands: "hit", "stick", "double" or "split", followed by the index of the
+// hand if it isn't the first one still in play.
+func (c *client) play(cmd command) (err error) {
+	if c.seat == nil {
+		return ErrNotJoined
+	}
+	index := -1
+	if len(cmd.args) > 1 {
+		return fmt.Errorf("%w: expected %s [hand]", ErrBadArguments, cmd.name)
+	}
+	if len(cmd.args) == 1 {
+		index, err = strconv.Atoi(cmd.args[0])
+		if err != nil || index < 0 {
+			return fmt.Errorf("%w: %q is not a hand", ErrBadArguments, cmd.args[0])
+		}
+	}
+	r := c.room
+	r.Lock()
+	h, err := c.seat.hand(index)
+	if err == nil {
+		switch cmd.name {
+		case "hit":
+			err = h.Hit()
+		case "stick":
+			err = h.Stick()
+		case "double":
+			err = h.DoubleDown()
+		case "split":
+			_, err = h.Split()
+		}
+	}
+	r.Unlock()
+	if err != nil {
+		return err
+	}
+	c.send("ok " + cmd.name)
+	r.advance()
+	return nil
+}
+
+// status describes a table: "status", or "status <table>" to look at one without sitting down.
+func (c *client) status(args []string) (err error) {
+	r := c.room
+	switch {
+	case len(args) > 1:
+		return fmt.Errorf("%w: expected status [table]", ErrBadArguments)
+	case len(args) == 1:
+		r = c.server.room(args[0])
+		if r == nil {
+			return fmt.Errorf("%w: %q", ErrUnknownTable, args[0])
+		}
+	case r == nil:
+		return ErrNotJoined
+	}
+	r.Lock()
+	lines := r.status()
+	r.Unlock()
+	for _, line := range lines {
+		c.send(line)
+	}
+	c.send("ok status")
+	return nil
+}
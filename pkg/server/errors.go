@@ -0,0 +1,19 @@
+package server
+
+import "errors"
+
+// Errors throwable by this module.
+var (
+	ErrServerClosed   = errors.New("server is closed")
+	ErrTableExists    = errors.New("table already exists")
+	ErrTableName      = errors.New("table name is invalid")
+	ErrTableOccupied  = errors.New("table already has players")
+	ErrUnknownTable   = errors.New("table is unknown")
+	ErrUnknownCommand = errors.New("command is unknown")
+	ErrBadArguments   = errors.New("command arguments are invalid")
+	ErrNotJoined      = errors.New("not seated at a table")
+	ErrAlreadyJoined  = errors.New("already seated at a table")
+	ErrNameTaken      = errors.New("name is taken by a connected player")
+	ErrBadToken       = errors.New("reconnect token is wrong")
+	ErrNoHand         = errors.New("no hand to play")
+)
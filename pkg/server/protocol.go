@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"strings"
+)
+
+// A command is a line sent by a client, split into its name and arguments.
+type command struct {
+	name string
+	args []string
+}
+
+// parseCommand splits a line into a command. Names aren't case-sensitive. Blank lines give a command with no name.
+func parseCommand(line string) (cmd command) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return cmd
+	}
+	return command{name: strings.ToLower(fields[0]), args: fields[1:]}
+}
+
+// formatEvent turns a Table's Event into an event line, e.g. "event card_dealt seat=0 player=alice hand=0 card=AS".
+// Only the fields relevant to the Event are included.
+func formatEvent(e blackjack.Event, player string) string {
+	var b strings.Builder
+	b.WriteString("event ")
+	b.WriteString(token(e.Type.String()))
+	if e.Seat == blackjack.SeatDealer {
+		b.WriteString(" seat=dealer")
+	} else {
+		fmt.Fprintf(&b, " seat=%v player=%s", e.Seat, player)
+	}
+	if e.HandIndex >= 0 {
+		fmt.Fprintf(&b, " hand=%v", e.HandIndex)
+	}
+	switch {
+	case e.Card != nil:
+		fmt.Fprintf(&b, " card=%s", e.Card.Notation())
+	case e.Hidden:
+		b.WriteString(" card=??")
+	}
+	if e.Score > 0 {
+		fmt.Fprintf(&b, " score=%v", e.Score)
+	}
+	// A bet or result of 0 is still worth knowing about.
+	if e.Amount != 0 || e.Type == blackjack.EventBetPlaced || e.Type == blackjack.EventSettled {
+		fmt.Fprintf(&b, " amount=%v", e.Amount)
+	}
+	if e.Type == blackjack.EventSettled {
+		fmt.Fprintf(&b, " outcome=%s", token(e.Outcome.String()))
+	}
+	return b.String()
+}
+
+// formatCards returns the compact notation for a list of cards, separated by commas (e.g. "AS,TD"), or "-" if there are none.
+func formatCards(cards []playdeck.Card) string {
+	if len(cards) == 0 {
+		return "-"
+	}
+	notations := make([]string, len(cards))
+	for i, c := range cards {
+		notations[i] = c.Notation()
+	}
+	return strings.Join(notations, ",")
+}
@@ -0,0 +1,383 @@
+package server
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A room is a Table being hosted by a Server, along with everyone who's ever sat at it.
+type room struct {
+	// The room lock is held while anything is done at the Table, so that only one thing happens there at a time.
+	sync.Mutex
+	name  string
+	table *blackjack.Table
+	// betting is the clock for the next round's bets, started once the first one's in if the Table has a DecisionTimeout.
+	// bettingRound tells a clock that's gone off apart from one that was stopped too late.
+	betting      *time.Timer
+	bettingRound int
+
+	// seatsMu guards seats, and each seat's client, which the Table's subscriber reads without the room lock.
+	seatsMu sync.Mutex
+	// seats are in the same order as the Table's Players. Nobody ever leaves, so a player who disconnects can come back.
+	seats []*seat
+}
+
+// A seat is a named player at a room's Table.
+type seat struct {
+	name   string
+	player *blackjack.Player
+	// token is given to the seat's player when they join, and has to be shown to get the seat back after disconnecting.
+	token string
+	// client is connected to the seat, or nil if its player has gone away.
+	client *client
+	// ready is true once the seat has bet (or chosen to sit out, with a bet of 0) on the next round.
+	ready bool
+}
+
+// newRoom returns a room hosting the given Table, broadcasting everything that happens at it.
+func newRoom(name string, t *blackjack.Table) *room {
+	r := &room{name: name, table: t}
+	t.Subscribe(r.event)
+	return r
+}
+
+// event broadcasts a Table's Event to everyone at it.
+func (r *room) event(e blackjack.Event) {
+	r.seatsMu.Lock()
+	name := ""
+	if e.Seat >= 0 && e.Seat < len(r.seats) {
+		name = r.seats[e.Seat].name
+	}
+	r.seatsMu.Unlock()
+	r.broadcast(formatEvent(e, name))
+
+	// Hands are played out when their time runs out without the room knowing, so have a look at whether the round can
+	// move on.
+	if e.Type == blackjack.EventTimeout || e.Type == blackjack.EventStick || e.Type == blackjack.EventSurrender {
+		go r.advance()
+	}
+}
+
+// broadcast sends a line to every client at the room's Table.
+func (r *room) broadcast(line string) {
+	r.seatsMu.Lock()
+	defer r.seatsMu.Unlock()
+	for _, s := range r.seats {
+		if s.client != nil {
+			s.client.send(line)
+		}
+	}
+}
+
+// join seats a client under the given name, or gives them back the seat they left if they've got its reconnect token.
+// New players are given the bankroll.
+// The room lock must be held by the caller.
+func (r *room) join(c *client, name string, token string, bankroll int) (s *seat, err error) {
+	r.seatsMu.Lock()
+	for _, s = range r.seats {
+		if s.name != name {
+			continue
+		}
+		if s.client != nil {
+			r.seatsMu.Unlock()
+			return nil, fmt.Errorf("%w: %q", ErrNameTaken, name)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			r.seatsMu.Unlock()
+			return nil, fmt.Errorf("%w: for %q", ErrBadToken, name)
+		}
+		s.client = c
+		r.seatsMu.Unlock()
+		r.broadcast(fmt.Sprintf("event returned seat=%v player=%s", r.seatOf(s), name))
+		return s, nil
+	}
+	r.seatsMu.Unlock()
+
+	// Players can only sit down before the round's dealt, and the last round's hands have to be cleared away first.
+	if r.table.CurrentPhase() == blackjack.PhaseIdle {
+		err = r.table.Reset()
+		if err != nil {
+			return nil, err
+		}
+	}
+	token, err = newToken()
+	if err != nil {
+		return nil, err
+	}
+	s = &seat{name: name, player: blackjack.NewPlayer(), token: token, client: c}
+	if bankroll > 0 {
+		err = s.player.Deposit(bankroll)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The seat's added before joining, so that everyone's told the new player's name.
+	r.seatsMu.Lock()
+	r.seats = append(r.seats, s)
+	r.seatsMu.Unlock()
+	err = r.table.Join(s.player)
+	if err != nil {
+		r.seatsMu.Lock()
+		r.seats = r.seats[:len(r.seats)-1]
+		r.seatsMu.Unlock()
+		return nil, err
+	}
+	return s, nil
+}
+
+// newToken returns a new, unguessable, reconnect token.
+func newToken() (token string, err error) {
+	b := make([]byte, 16)
+	_, err = cryptorand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// leave disconnects a client from their seat. Any bet they had on the next round is taken back, and their hands are played
+// out for them (by sticking) so that nobody's kept waiting.
+func (r *room) leave(s *seat) {
+	r.Lock()
+	r.seatsMu.Lock()
+	s.client = nil
+	s.ready = false
+	r.seatsMu.Unlock()
+	if s.player.Bet() > 0 {
+		_ = s.player.PlaceBet(0)
+	}
+	r.broadcast(fmt.Sprintf("event left seat=%v player=%s", r.seatOf(s), s.name))
+	r.Unlock()
+	r.advance()
+}
+
+// seatOf returns the index of a seat.
+func (r *room) seatOf(s *seat) int {
+	r.seatsMu.Lock()
+	defer r.seatsMu.Unlock()
+	for i, o := range r.seats {
+		if o == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// advance moves the round along as far as it can go without the players: dealing once everyone connected is ready,
+// having the dealer peek, playing out the hands of anyone who isn't there (or sat out), and ending and settling the
+// round once every hand's done.
+func (r *room) advance() {
+	r.Lock()
+	defer r.Unlock()
+	for r.step() {
+	}
+}
+
+// step takes the next thing to be done at the Table, if there is one, returning true if it did anything.
+// The room lock must be held by the caller.
+func (r *room) step() bool {
+	t := r.table
+	switch t.CurrentPhase() {
+	case blackjack.PhaseBetting, blackjack.PhaseIdle:
+		r.seatsMu.Lock()
+		connected, betting, waiting := 0, 0, 0
+		for _, s := range r.seats {
+			if s.client == nil {
+				continue
+			}
+			connected++
+			if !s.ready {
+				waiting++
+			}
+			if s.player.Bet() > 0 {
+				betting++
+			}
+		}
+		if waiting > 0 || connected == 0 || betting == 0 {
+			r.seatsMu.Unlock()
+			// Nobody gets to hold up the round by never betting.
+			if waiting > 0 && betting > 0 {
+				r.startBetting()
+			}
+			return false
+		}
+		for _, s := range r.seats {
+			s.ready = false
+		}
+		r.seatsMu.Unlock()
+		r.stopBetting()
+		errs := t.Deal()
+		if len(errs) > 0 {
+			r.broadcast("event error " + errors.Join(errs...).Error())
+		}
+		return t.CurrentPhase() != blackjack.PhaseBetting
+	case blackjack.PhaseInsurance:
+		return t.DealerPeek() == nil
+	case blackjack.PhasePlayerTurns:
+		if r.stickAbsent() {
+			return true
+		}
+		if t.EndRound() != nil {
+			return false
+		}
+		return true
+	case blackjack.PhaseSettlement:
+		_, err := t.Settle()
+		return err == nil
+	}
+	return false
+}
+
+// startBetting starts the clock for the next round's bets, if the Table has a DecisionTimeout and it isn't already running.
+// The room lock must be held by the caller.
+func (r *room) startBetting() {
+	timeout := r.table.Rules().DecisionTimeout
+	if r.betting != nil || timeout <= 0 {
+		return
+	}
+	r.bettingRound++
+	round := r.bettingRound
+	r.betting = time.AfterFunc(timeout, func() {
+		r.betsDue(round)
+	})
+}
+
+// stopBetting stops the betting clock, if it's running.
+// The room lock must be held by the caller.
+func (r *room) stopBetting() {
+	if r.betting != nil {
+		r.betting.Stop()
+		r.betting = nil
+	}
+}
+
+// betsDue is called when the betting clock runs out. Everyone connected who hasn't bet sits the round out, and the round
+// is dealt without them.
+func (r *room) betsDue(round int) {
+	r.Lock()
+	if r.betting == nil || round != r.bettingRound {
+		r.Unlock()
+		return
+	}
+	r.betting = nil
+	var late []string
+	r.seatsMu.Lock()
+	for i, s := range r.seats {
+		if s.client != nil && !s.ready {
+			s.ready = true
+			late = append(late, fmt.Sprintf("event sat_out seat=%v player=%s", i, s.name))
+		}
+	}
+	r.seatsMu.Unlock()
+	for _, line := range late {
+		r.broadcast(line)
+	}
+	r.Unlock()
+	r.advance()
+}
+
+// stickAbsent sticks the next hand belonging to someone who isn't playing this round: either they've disconnected, or
+// they sat it out. It returns true if it stuck one.
+// The room lock must be held by the caller.
+func (r *room) stickAbsent() bool {
+	t := r.table
+	active := t.ActiveHand()
+	r.seatsMu.Lock()
+	var absent []*blackjack.Hand
+	for _, s := range r.seats {
+		s.player.RLock()
+		hands := s.player.Hands
+		s.player.RUnlock()
+		for _, h := range hands {
+			if s.client != nil && h.Wager() > 0 {
+				continue
+			}
+			// Under seat order, only the hand whose turn it is can be played.
+			if active == nil || active == h {
+				absent = append(absent, h)
+			}
+		}
+	}
+	r.seatsMu.Unlock()
+	for _, h := range absent {
+		if _, _, locked, _ := h.Score(); !locked && h.Stick() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hand returns the seat's hand with the given index, or its first one that's still in play if index is -1.
+func (s *seat) hand(index int) (h *blackjack.Hand, err error) {
+	s.player.RLock()
+	hands := s.player.Hands
+	s.player.RUnlock()
+	if index >= 0 {
+		if index >= len(hands) {
+			return nil, fmt.Errorf("%w: there's no hand %v", ErrNoHand, index)
+		}
+		return hands[index], nil
+	}
+	for _, h := range hands {
+		if _, _, locked, _ := h.Score(); !locked {
+			return h, nil
+		}
+	}
+	return nil, ErrNoHand
+}
+
+// status describes the room's Table, as a set of status lines.
+// The room lock must be held by the caller.
+func (r *room) status() (lines []string) {
+	snap := r.table.Snapshot()
+	phase := token(snap.Phase.String())
+	active := r.table.ActiveHand()
+	turn := "-"
+	r.seatsMu.Lock()
+	for i, s := range r.seats {
+		s.player.RLock()
+		for j, h := range s.player.Hands {
+			if h == active {
+				turn = fmt.Sprintf("%v/%v", i, j)
+			}
+		}
+		s.player.RUnlock()
+	}
+	r.seatsMu.Unlock()
+	lines = append(lines, fmt.Sprintf("status table=%s phase=%s turn=%s", r.name, phase, turn))
+
+	dealer := "-"
+	if len(snap.Dealer) > 0 {
+		dealer = formatCards(snap.Dealer[0].Cards)
+		// The hole card stays face down until the dealer plays, or peeks at a blackjack.
+		if snap.Phase < blackjack.PhaseDealerTurn && len(snap.Dealer[0].Cards) > 1 {
+			dealer = formatCards(snap.Dealer[0].Cards[:1]) + ",??"
+		}
+	}
+	lines = append(lines, "status dealer cards="+dealer)
+
+	r.seatsMu.Lock()
+	defer r.seatsMu.Unlock()
+	for i, s := range r.seats {
+		p := snap.Players[i]
+		lines = append(lines, fmt.Sprintf("status seat=%v player=%s bankroll=%v bet=%v connected=%v",
+			i, s.name, p.Bankroll, p.Bet, s.client != nil))
+		for j, h := range p.Hands {
+			lines = append(lines, fmt.Sprintf("status seat=%v hand=%v cards=%s score=%v wager=%v locked=%v",
+				i, j, formatCards(h.Cards), h.Score, h.Wager, h.Locked))
+		}
+	}
+	return lines
+}
+
+// token turns a name with spaces (e.g. "player turns") into a single protocol token ("player_turns").
+func token(name string) string {
+	return strings.ReplaceAll(name, " ", "_")
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultBankroll is what new players start with, if the Server doesn't say otherwise.
+const DefaultBankroll = 1000
+
+// A Server hosts blackjack Tables over a line-based text protocol (see the package README). Players connect, join a
+// Table by name, and play; the Server deals, peeks, ends and settles each round itself, and tells everyone at a Table
+// about everything that happens there.
+// Safe for use in asynchronous environments.
+type Server struct {
+	// Bankroll is what new players start with. 0 means DefaultBankroll.
+	Bankroll int
+
+	mu        sync.Mutex
+	rooms     map[string]*room
+	names     []string
+	listeners []net.Listener
+	clients   map[*client]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewServer returns a Server with no Tables.
+func NewServer() (server *Server) {
+	return &Server{rooms: map[string]*room{}, clients: map[*client]struct{}{}}
+}
+
+// AddTable hosts a Table under the given name, which players use to join it. The Server takes over running the Table's
+// rounds, so it must not have any Players yet, and shouldn't be played by anything else.
+// It returns ErrTableName if the name is empty or has spaces in, ErrTableExists if it's taken, and ErrTableOccupied if
+// the Table already has Players.
+func (s *Server) AddTable(name string, t *blackjack.Table) (err error) {
+	if name == "" || strings.ContainsAny(name, " \t\r\n") {
+		return fmt.Errorf("%w: %q", ErrTableName, name)
+	}
+	if len(t.Snapshot().Players) > 0 {
+		return ErrTableOccupied
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.rooms[name]; exists {
+		return fmt.Errorf("%w: %q", ErrTableExists, name)
+	}
+	s.rooms[name] = newRoom(name, t)
+	s.names = append(s.names, name)
+	return nil
+}
+
+// Tables returns the names of the Tables being hosted, in the order they were added.
+func (s *Server) Tables() (names []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.names)
+}
+
+// room returns the room for the named Table, or nil.
+func (s *Server) room(name string) *room {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rooms[name]
+}
+
+// bankroll returns what new players start with.
+func (s *Server) bankroll() int {
+	if s.Bankroll > 0 {
+		return s.Bankroll
+	}
+	return DefaultBankroll
+}
+
+// Serve accepts connections from the Listener, serving each on its own goroutine, until the Listener fails or the
+// Server is closed. It always returns an error: ErrServerClosed once the Server's been closed.
+func (s *Server) Serve(l net.Listener) (err error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.ServeConn(conn)
+		}()
+	}
+}
+
+// ServeConn serves a single connection until it's closed, by either end. The connection is closed when it returns.
+func (s *Server) ServeConn(conn net.Conn) {
+	c := newClient(s, conn)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	c.serve()
+
+	s.mu.Lock()
+	delete(s.clients, c)
+	s.mu.Unlock()
+}
+
+// Close stops the Server: its Listeners are closed, and so is every connection. Players' seats (and bankrolls) are kept
+// by their Tables. It waits for every connection that Serve() accepted to finish.
+func (s *Server) Close() (err error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.closed = true
+	var errs []error
+	for _, l := range s.listeners {
+		errs = append(errs, l.Close())
+	}
+	for c := range s.clients {
+		c.close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	// Everyone's gone, so there are no more bets to wait for.
+	s.mu.Lock()
+	rooms := make([]*room, 0, len(s.rooms))
+	for _, r := range s.rooms {
+		rooms = append(rooms, r)
+	}
+	s.mu.Unlock()
+	for _, r := range rooms {
+		r.Lock()
+		r.stopBetting()
+		r.Unlock()
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,328 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serverStart is a helper that serves a single seeded table named "main" on a loopback port, returning its address.
+func serverStart(t *testing.T, simultaneous bool) (server *Server, table *blackjack.Table, addr string) {
+	t.Helper()
+	rules := blackjack.DefaultRules
+	rules.SimultaneousPlay = simultaneous
+	table, err := blackjack.NewTableWithRules(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = table.SetSource(playdeck.NewSeededSource(1234))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = NewServer()
+	err = server.AddTable("main", table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = server.Serve(l) }()
+	t.Cleanup(func() { _ = server.Close() })
+	return server, table, l.Addr().String()
+}
+
+// serverClient is a player connected to a test server.
+type serverClient struct {
+	t     *testing.T
+	conn  net.Conn
+	lines *bufio.Scanner
+	// token is the reconnect token the server gave the player when they joined.
+	token string
+}
+
+// serverDial is a helper that connects to a test server, and waits for its greeting.
+func serverDial(t *testing.T, addr string) (c *serverClient) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	c = &serverClient{t: t, conn: conn, lines: bufio.NewScanner(conn)}
+	c.expect("hello tables=main")
+	return c
+}
+
+// serverJoin is a helper that connects a named player to the test server's table.
+func serverJoin(t *testing.T, addr string, name string) (c *serverClient) {
+	t.Helper()
+	c = serverDial(t, addr)
+	c.send("join main " + name)
+	line := c.expect("ok join")
+	_, c.token, _ = strings.Cut(line, " token=")
+	return c
+}
+
+// send sends a line to the server.
+func (c *serverClient) send(line string) {
+	c.t.Helper()
+	_, err := c.conn.Write([]byte(line + "\n"))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// expect reads lines from the server until one starts with the given prefix, and returns it.
+func (c *serverClient) expect(prefix string) (line string) {
+	c.t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for c.lines.Scan() {
+		if strings.HasPrefix(c.lines.Text(), prefix) {
+			return c.lines.Text()
+		}
+	}
+	c.t.Fatalf("never got a line starting %q: %v", prefix, c.lines.Err())
+	return ""
+}
+
+// reply reads lines from the server until it replies to a command, and returns the reply.
+func (c *serverClient) reply() (line string) {
+	c.t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for c.lines.Scan() {
+		if line = c.lines.Text(); strings.HasPrefix(line, "ok ") || strings.HasPrefix(line, "err ") {
+			return line
+		}
+	}
+	c.t.Fatalf("never got a reply: %v", c.lines.Err())
+	return ""
+}
+
+// Test that a round is dealt once everyone's bet, broadcast to everyone, and settled once everyone's played.
+func TestServerRound(t *testing.T) {
+	_, table, addr := serverStart(t, true)
+	alice := serverJoin(t, addr, "alice")
+	bob := serverJoin(t, addr, "bob")
+	alice.expect("event player_joined seat=1 player=bob")
+
+	alice.send("bet 10")
+	alice.expect("event bet_placed seat=0 player=alice amount=10")
+	alice.expect("ok bet amount=10")
+	if table.CurrentPhase() != blackjack.PhaseBetting {
+		t.Errorf("round was dealt before everyone bet")
+	}
+	bob.expect("event bet_placed seat=0 player=alice amount=10")
+	bob.send("bet 20")
+	for _, c := range []*serverClient{alice, bob} {
+		c.expect("event bet_placed seat=1 player=bob amount=20")
+		c.expect("event card_dealt seat=dealer hand=0 card=??")
+		c.expect("event round_dealt seat=dealer")
+	}
+
+	for _, c := range []*serverClient{alice, bob} {
+		c.send("stick")
+		// A natural doesn't need playing.
+		if reply := c.reply(); reply != "ok stick" && !strings.Contains(reply, ErrNoHand.Error()) {
+			t.Errorf("expected the hand to stick, got %q", reply)
+		}
+	}
+	for _, c := range []*serverClient{alice, bob} {
+		c.expect("event round_ended")
+		c.expect("event settled seat=0 player=alice hand=0")
+		c.expect("event settled seat=1 player=bob hand=0")
+	}
+
+	alice.send("status")
+	status := alice.expect("status table=main")
+	if status != "status table=main phase=idle turn=-" {
+		t.Errorf("unexpected status %q", status)
+	}
+	alice.expect("status dealer cards=")
+	seat := alice.expect("status seat=0")
+	if want := "bankroll=" + strconv.Itoa(table.Players[0].Bankroll()); !strings.Contains(seat, want) {
+		t.Errorf("expected alice's status to show %s, got %q", want, seat)
+	}
+	alice.expect("status seat=0 hand=0")
+	alice.expect("status seat=1 player=bob")
+	alice.expect("ok status")
+}
+
+// Test that hands are played in seat order, with everyone told whose turn it is.
+func TestServerTurns(t *testing.T) {
+	_, table, addr := serverStart(t, false)
+	alice := serverJoin(t, addr, "alice")
+	bob := serverJoin(t, addr, "bob")
+	alice.send("bet 10")
+	alice.expect("ok bet")
+	bob.send("bet 10")
+	bob.expect("ok bet")
+	bob.expect("event round_dealt")
+
+	for seat, c := range []*serverClient{alice, bob} {
+		active := table.ActiveHand()
+		if active == nil || active.Player != table.Players[seat] {
+			t.Fatalf("expected seat %v's turn", seat)
+		}
+		c.expect("event turn seat=" + strconv.Itoa(seat))
+		if seat == 0 {
+			bob.send("hit")
+			if reply := bob.reply(); !strings.Contains(reply, blackjack.ErrNotYourTurn.Error()) {
+				t.Errorf("expected a hit out of turn to fail, got %q", reply)
+			}
+		}
+		c.send("stick")
+		if reply := c.reply(); reply != "ok stick" {
+			t.Errorf("expected the hand to stick, got %q", reply)
+		}
+	}
+	alice.expect("event round_ended")
+	bob.expect("event settled seat=1")
+}
+
+// Test that a player who disconnects doesn't hold up the table, and can come back to their seat.
+func TestServerDisconnect(t *testing.T) {
+	_, table, addr := serverStart(t, false)
+	alice := serverJoin(t, addr, "alice")
+	bob := serverJoin(t, addr, "bob")
+	bob.send("bet 10")
+	bob.expect("ok bet")
+	alice.send("bet 10")
+	alice.expect("event round_dealt")
+
+	// Alice's turn is played out for her as soon as she's gone, and the round carries on.
+	_ = alice.conn.Close()
+	bob.expect("event left seat=0 player=alice")
+	bob.expect("event stick seat=0 player=alice")
+	bob.expect("event turn seat=1")
+	bob.send("stick")
+	bob.expect("event settled seat=1")
+	bankroll := table.Players[0].Bankroll()
+
+	// The next round is dealt without her.
+	bob.send("bet 10")
+	bob.expect("event round_dealt")
+	bob.expect("event stick seat=0 player=alice")
+	bob.send("stick")
+	bob.expect("event settled seat=1")
+	if table.Players[0].Bankroll() != bankroll {
+		t.Errorf("disconnected player's bankroll changed from %v to %v", bankroll, table.Players[0].Bankroll())
+	}
+
+	// Only someone with her reconnect token can have her seat.
+	token := alice.token
+	impostor := serverDial(t, addr)
+	for _, line := range []string{"join main alice", "join main alice " + strings.Repeat("0", len(token))} {
+		impostor.send(line)
+		if reply := impostor.reply(); !strings.Contains(reply, ErrBadToken.Error()) {
+			t.Errorf("expected %q to be refused without the token, got %q", line, reply)
+		}
+	}
+	alice = serverDial(t, addr)
+	alice.send("join main alice " + token)
+	if reply := alice.reply(); reply != "ok join table=main seat=0 bankroll="+strconv.Itoa(bankroll)+" token="+token {
+		t.Errorf("expected alice to get her seat back, got %q", reply)
+	}
+	bob.expect("event returned seat=0 player=alice")
+	if len(table.Players) != 2 {
+		t.Errorf("expected 2 players at the table, got %v", len(table.Players))
+	}
+	impostor.send("join main alice " + token)
+	if reply := impostor.reply(); !strings.Contains(reply, ErrNameTaken.Error()) {
+		t.Errorf("expected a taken name to be refused, got %q", reply)
+	}
+}
+
+// Test that a player who's connected but never bets only holds up the round until the table's decision timeout.
+func TestServerBetTimeout(t *testing.T) {
+	_, table, addr := serverStart(t, false)
+	err := table.SetDecisionTimeout(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := serverJoin(t, addr, "alice")
+	idle := serverJoin(t, addr, "idle")
+	alice.send("bet 10")
+	alice.expect("ok bet")
+	for _, c := range []*serverClient{alice, idle} {
+		c.expect("event sat_out seat=1 player=idle")
+		c.expect("event round_dealt")
+	}
+	if table.Players[1].Hands[0].Wager() != 0 {
+		t.Errorf("expected the idle player to sit the round out, got a wager of %v", table.Players[1].Hands[0].Wager())
+	}
+	alice.send("stick")
+	alice.expect("event settled seat=0 player=alice")
+}
+
+// Test that bad commands are refused, without disconnecting anyone.
+func TestServerErrors(t *testing.T) {
+	server, _, addr := serverStart(t, false)
+	c := serverDial(t, addr)
+	cases := []struct {
+		line string
+		err  error
+	}{
+		{"dance", ErrUnknownCommand},
+		{"bet 10", ErrNotJoined},
+		{"hit", ErrNotJoined},
+		{"status", ErrNotJoined},
+		{"status nowhere", ErrUnknownTable},
+		{"join nowhere alice", ErrUnknownTable},
+		{"join main", ErrBadArguments},
+		{"join main alice", nil},
+		{"join main bob", ErrAlreadyJoined},
+		{"bet lots", ErrBadArguments},
+		{"bet -10", blackjack.ErrInvalidAmount},
+		{"bet 100000", blackjack.ErrInsufficientFunds},
+		{"hit", ErrNoHand},
+		{"hit 1", ErrNoHand},
+		{"stick first", ErrBadArguments},
+	}
+	for _, tc := range cases {
+		c.send(tc.line)
+		reply := c.reply()
+		if tc.err == nil {
+			if !strings.HasPrefix(reply, "ok ") {
+				t.Errorf("%s: expected ok, got %q", tc.line, reply)
+			}
+			continue
+		}
+		if reply != "err "+tc.err.Error() && !strings.HasPrefix(reply, "err "+tc.err.Error()+":") {
+			t.Errorf("didn't get appropriate error for %q, expected %s got %q", tc.line, tc.err, reply)
+		}
+	}
+	c.send("quit")
+	c.expect("ok quit")
+
+	err := server.AddTable("main", blackjack.NewTable(1))
+	if !errors.Is(err, ErrTableExists) {
+		t.Errorf("didn't get appropriate error when adding a table twice, expected TableExists got %s", err)
+	}
+	err = server.AddTable("two words", blackjack.NewTable(1))
+	if !errors.Is(err, ErrTableName) {
+		t.Errorf("didn't get appropriate error when naming a table badly, expected TableName got %s", err)
+	}
+	occupied := blackjack.NewTable(1)
+	_ = occupied.Join(blackjack.NewPlayer())
+	err = server.AddTable("occupied", occupied)
+	if !errors.Is(err, ErrTableOccupied) {
+		t.Errorf("didn't get appropriate error when adding a table with players, expected TableOccupied got %s", err)
+	}
+
+	err = server.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = server.Serve(nil)
+	if !errors.Is(err, ErrServerClosed) {
+		t.Errorf("didn't get appropriate error when serving after closing, expected ServerClosed got %s", err)
+	}
+}
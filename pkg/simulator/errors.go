@@ -0,0 +1,8 @@
+package simulator
+
+import "errors"
+
+// Errors throwable by this module.
+var (
+	ErrInvalidConfig = errors.New("simulation config is invalid")
+)
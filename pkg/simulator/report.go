@@ -0,0 +1,274 @@
+package simulator
+
+import (
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Bust is the total Report.Totals files bust hands under.
+const Bust = 22
+
+// A Tally counts how a set of Hands turned out.
+type Tally struct {
+	// Hands is the number of Hands counted.
+	Hands int `json:"hands"`
+	// Wagered is the total staked on them, including doubles.
+	Wagered int `json:"wagered"`
+	// Net is the total won on them (or lost, if negative), including any insurance.
+	Net int `json:"net"`
+
+	Wins       int `json:"wins"`
+	Blackjacks int `json:"blackjacks"`
+	EvenMoney  int `json:"evenMoney"`
+	Pushes     int `json:"pushes"`
+	Losses     int `json:"losses"`
+	Surrenders int `json:"surrenders"`
+}
+
+// add counts a single Hand's Result.
+func (t *Tally) add(r blackjack.Result) {
+	t.Hands++
+	t.Wagered += r.Wager
+	t.Net += r.Net
+	switch r.Outcome {
+	case blackjack.OutcomeWin:
+		t.Wins++
+	case blackjack.OutcomeBlackjack:
+		t.Blackjacks++
+	case blackjack.OutcomeEvenMoney:
+		t.EvenMoney++
+	case blackjack.OutcomePush:
+		t.Pushes++
+	case blackjack.OutcomeLose:
+		t.Losses++
+	case blackjack.OutcomeSurrender:
+		t.Surrenders++
+	}
+}
+
+// merge adds another Tally's counts to this one.
+func (t *Tally) merge(o Tally) {
+	t.Hands += o.Hands
+	t.Wagered += o.Wagered
+	t.Net += o.Net
+	t.Wins += o.Wins
+	t.Blackjacks += o.Blackjacks
+	t.EvenMoney += o.EvenMoney
+	t.Pushes += o.Pushes
+	t.Losses += o.Losses
+	t.Surrenders += o.Surrenders
+}
+
+// WinRate returns the proportion of Hands that won, including naturals and even money.
+func (t Tally) WinRate() float64 {
+	return ratio(t.Wins+t.Blackjacks+t.EvenMoney, t.Hands)
+}
+
+// PushRate returns the proportion of Hands that pushed.
+func (t Tally) PushRate() float64 {
+	return ratio(t.Pushes, t.Hands)
+}
+
+// LossRate returns the proportion of Hands that lost, including surrenders.
+func (t Tally) LossRate() float64 {
+	return ratio(t.Losses+t.Surrenders, t.Hands)
+}
+
+// Return returns the average amount won on each chip wagered on these Hands (negative if they lost money).
+func (t Tally) Return() float64 {
+	return ratio(t.Net, t.Wagered)
+}
+
+// A Report is the outcome of a simulation: how every Hand turned out, broken down by the Hand's final total and the
+// dealer's up card, along with what's needed to work out the house edge and how sure we can be of it.
+// A round is counted once for each Player who bet on it.
+type Report struct {
+	// Rounds is the number of rounds counted.
+	Rounds int `json:"rounds"`
+	// Bets is the total of the bets placed at the start of each round (not including doubles, splits or insurance).
+	Bets int `json:"bets"`
+	// Overall counts every Hand played.
+	Overall Tally `json:"overall"`
+	// Totals counts Hands by their final total (Bust for bust hands).
+	Totals map[int]*Tally `json:"totals"`
+	// UpCards counts Hands by the dealer's up card, from 2 to 11 (the ace).
+	UpCards map[int]*Tally `json:"upCards"`
+
+	// SumSquares, SumNetBets and SumBetSquares are the sums of each round's net squared, net times bet, and bet squared,
+	// which the variance and confidence interval are worked out from.
+	SumSquares    float64 `json:"sumSquares"`
+	SumNetBets    float64 `json:"sumNetBets"`
+	SumBetSquares float64 `json:"sumBetSquares"`
+}
+
+// NewReport returns an empty Report.
+func NewReport() (report *Report) {
+	return &Report{Totals: map[int]*Tally{}, UpCards: map[int]*Tally{}}
+}
+
+// addRound counts a settled round, for every Player that bet on it. bets are each Player's opening bet.
+func (r *Report) addRound(results []blackjack.Result, players []*blackjack.Player, bets []int, up playdeck.Card) {
+	for i, p := range players {
+		if bets[i] == 0 {
+			continue
+		}
+		net := 0
+		for _, result := range results {
+			if result.Player != p {
+				continue
+			}
+			net += result.Net
+			r.Overall.add(result)
+			tally(r.Totals, finalTotal(result.Hand)).add(result)
+			tally(r.UpCards, upCardValue(up)).add(result)
+		}
+		r.Rounds++
+		r.Bets += bets[i]
+		r.SumSquares += float64(net) * float64(net)
+		r.SumNetBets += float64(net) * float64(bets[i])
+		r.SumBetSquares += float64(bets[i]) * float64(bets[i])
+	}
+}
+
+// Merge adds another Report's counts to this one.
+func (r *Report) Merge(o *Report) {
+	r.Rounds += o.Rounds
+	r.Bets += o.Bets
+	r.Overall.merge(o.Overall)
+	for total, t := range o.Totals {
+		tally(r.Totals, total).merge(*t)
+	}
+	for up, t := range o.UpCards {
+		tally(r.UpCards, up).merge(*t)
+	}
+	r.SumSquares += o.SumSquares
+	r.SumNetBets += o.SumNetBets
+	r.SumBetSquares += o.SumBetSquares
+}
+
+// HouseEdge returns the house's average winnings on each chip bet at the start of a round (so 0.005 is half a percent).
+// Negative edges are in the Player's favour.
+func (r *Report) HouseEdge() float64 {
+	return -ratio(r.Overall.Net, r.Bets)
+}
+
+// Variance returns the variance of a round's net winnings, measured in average opening bets. Flat betting basic strategy
+// comes out at around 1.3.
+func (r *Report) Variance() float64 {
+	if r.Rounds == 0 {
+		return math.NaN()
+	}
+	n := float64(r.Rounds)
+	mean := float64(r.Overall.Net) / n
+	bet := float64(r.Bets) / n
+	return (r.SumSquares/n - mean*mean) / (bet * bet)
+}
+
+// StdDev returns the standard deviation of a round's net winnings, measured in average opening bets.
+func (r *Report) StdDev() float64 {
+	return math.Sqrt(r.Variance())
+}
+
+// StdErr returns the standard error of HouseEdge(). Since bets can vary, it's worked out for the ratio of what was won to
+// what was bet, rather than the average of each round's.
+func (r *Report) StdErr() float64 {
+	if r.Bets == 0 {
+		return math.NaN()
+	}
+	ret := float64(r.Overall.Net) / float64(r.Bets)
+	sum := r.SumSquares - 2*ret*r.SumNetBets + ret*ret*r.SumBetSquares
+	return math.Sqrt(max(sum, 0)) / float64(r.Bets)
+}
+
+// Confidence returns the confidence interval for HouseEdge() at the given number of standard errors (1.96 for 95%).
+func (r *Report) Confidence(z float64) (low float64, high float64) {
+	edge, err := r.HouseEdge(), r.StdErr()
+	return edge - z*err, edge + z*err
+}
+
+// String summarises the Report, with a table of outcomes for each final total and each dealer up card.
+func (r *Report) String() string {
+	var b strings.Builder
+	low, high := r.Confidence(1.96)
+	fmt.Fprintf(&b, "Rounds: %v, hands: %v\n", r.Rounds, r.Overall.Hands)
+	fmt.Fprintf(&b, "House edge: %.3f%% (95%% CI %.3f%% to %.3f%%)\n", r.HouseEdge()*100, low*100, high*100)
+	fmt.Fprintf(&b, "Variance: %.3f, standard deviation: %.3f (per round, in opening bets)\n", r.Variance(), r.StdDev())
+	fmt.Fprintf(&b, "Win: %.2f%%, push: %.2f%%, loss: %.2f%% (per hand)\n",
+		r.Overall.WinRate()*100, r.Overall.PushRate()*100, r.Overall.LossRate()*100)
+
+	b.WriteString("\nBy final total:\n")
+	writeTallies(&b, "total", r.Totals, func(total int) string {
+		if total == Bust {
+			return "bust"
+		}
+		return fmt.Sprint(total)
+	})
+	b.WriteString("\nBy dealer up card:\n")
+	writeTallies(&b, "up card", r.UpCards, func(up int) string {
+		if up == 11 {
+			return "A"
+		}
+		return fmt.Sprint(up)
+	})
+	return b.String()
+}
+
+// writeTallies writes a table of Tallies, in order of their keys.
+func writeTallies(b *strings.Builder, heading string, tallies map[int]*Tally, label func(int) string) {
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "%s\thands\twin\tpush\tloss\treturn\t\n", heading)
+	keys := make([]int, 0, len(tallies))
+	for k := range tallies {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		t := tallies[k]
+		fmt.Fprintf(w, "%s\t%v\t%.2f%%\t%.2f%%\t%.2f%%\t%+.2f%%\t\n", label(k), t.Hands,
+			t.WinRate()*100, t.PushRate()*100, t.LossRate()*100, t.Return()*100)
+	}
+	_ = w.Flush()
+}
+
+// tally returns the Tally for the given key, adding it if it's not there yet.
+func tally(tallies map[int]*Tally, key int) *Tally {
+	t, exists := tallies[key]
+	if !exists {
+		t = &Tally{}
+		tallies[key] = t
+	}
+	return t
+}
+
+// finalTotal returns the total a Hand finished on, or Bust.
+func finalTotal(h *blackjack.Hand) int {
+	score, _, _, valid := h.Score()
+	if !valid {
+		return Bust
+	}
+	return score
+}
+
+// upCardValue returns the blackjack value of an up card, with aces as 11.
+func upCardValue(c playdeck.Card) int {
+	switch {
+	case c.Value == playdeck.ValueAce:
+		return 11
+	case c.Value >= playdeck.ValueTen:
+		return 10
+	}
+	return c.Value.Value()
+}
+
+// ratio returns a divided by b, or NaN if b is 0.
+func ratio(a int, b int) float64 {
+	if b == 0 {
+		return math.NaN()
+	}
+	return float64(a) / float64(b)
+}
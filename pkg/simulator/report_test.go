@@ -0,0 +1,104 @@
+package simulator
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Test that Tallies count outcomes and work out their rates.
+func TestTally(t *testing.T) {
+	var tally Tally
+	for _, r := range []blackjack.Result{
+		{Outcome: blackjack.OutcomeWin, Wager: 10, Net: 10},
+		{Outcome: blackjack.OutcomeBlackjack, Wager: 10, Net: 15},
+		{Outcome: blackjack.OutcomePush, Wager: 10},
+		{Outcome: blackjack.OutcomeLose, Wager: 20, Net: -20},
+		{Outcome: blackjack.OutcomeSurrender, Wager: 10, Net: -5},
+	} {
+		tally.add(r)
+	}
+	if tally.Hands != 5 || tally.Wagered != 60 || tally.Net != 0 {
+		t.Errorf("expected 5 hands, 60 wagered and 0 net, got %+v", tally)
+	}
+	if tally.WinRate() != 0.4 || tally.PushRate() != 0.2 || tally.LossRate() != 0.4 {
+		t.Errorf("expected rates of 40%%, 20%% and 40%%, got %v, %v and %v", tally.WinRate(), tally.PushRate(), tally.LossRate())
+	}
+	if tally.Return() != 0 {
+		t.Errorf("expected a return of 0, got %v", tally.Return())
+	}
+	if !math.IsNaN(Tally{}.WinRate()) {
+		t.Errorf("expected an empty tally's win rate to be NaN, got %v", Tally{}.WinRate())
+	}
+}
+
+// Test the house edge and its error on a Report simple enough to work out by hand.
+func TestReportStatistics(t *testing.T) {
+	// Four rounds of 10: two wins and two losses, then one more loss.
+	r := NewReport()
+	for _, net := range []int{10, -10, 10, -10, -10} {
+		r.Rounds++
+		r.Bets += 10
+		r.Overall.Net += net
+		r.SumSquares += float64(net * net)
+		r.SumNetBets += float64(net * 10)
+		r.SumBetSquares += 100
+	}
+	if edge := r.HouseEdge(); edge != 0.2 {
+		t.Errorf("expected a house edge of 0.2, got %v", edge)
+	}
+	// Each round is +1 or -1 bets, averaging -0.2, so the variance is 1 - 0.04.
+	if v := r.Variance(); math.Abs(v-0.96) > 1e-9 {
+		t.Errorf("expected a variance of 0.96, got %v", v)
+	}
+	// With flat bets, the standard error is the standard deviation over the square root of the rounds.
+	if se := r.StdErr(); math.Abs(se-math.Sqrt(0.96/5)) > 1e-9 {
+		t.Errorf("expected a standard error of %v, got %v", math.Sqrt(0.96/5), se)
+	}
+	low, high := r.Confidence(2)
+	if math.Abs(high-low-4*r.StdErr()) > 1e-9 {
+		t.Errorf("expected the confidence interval to be 4 standard errors wide, got %v to %v", low, high)
+	}
+
+	empty := NewReport()
+	if !math.IsNaN(empty.HouseEdge()) || !math.IsNaN(empty.Variance()) || !math.IsNaN(empty.StdErr()) {
+		t.Errorf("expected an empty report's statistics to be NaN")
+	}
+}
+
+// Test that merging Reports adds everything up.
+func TestReportMerge(t *testing.T) {
+	a := simulate(t, Config{Rules: blackjack.DefaultRules, Seed: 1, Rounds: 100})
+	b := simulate(t, Config{Rules: blackjack.DefaultRules, Seed: 2, Rounds: 100})
+	merged := NewReport()
+	merged.Merge(a)
+	merged.Merge(b)
+	if merged.Rounds != 200 || merged.Overall.Hands != a.Overall.Hands+b.Overall.Hands {
+		t.Errorf("expected 200 rounds and %v hands, got %v and %v", a.Overall.Hands+b.Overall.Hands, merged.Rounds, merged.Overall.Hands)
+	}
+	hands := 0
+	for _, tally := range merged.Totals {
+		hands += tally.Hands
+	}
+	if hands != merged.Overall.Hands {
+		t.Errorf("expected the totals to add up to %v hands, got %v", merged.Overall.Hands, hands)
+	}
+	hands = 0
+	for _, tally := range merged.UpCards {
+		hands += tally.Hands
+	}
+	if hands != merged.Overall.Hands {
+		t.Errorf("expected the up cards to add up to %v hands, got %v", merged.Overall.Hands, hands)
+	}
+}
+
+// Test that a Report's summary includes its tables.
+func TestReportString(t *testing.T) {
+	s := simulate(t, Config{Rules: blackjack.DefaultRules, Seed: 1, Rounds: 1000}).String()
+	for _, want := range []string{"House edge:", "By final total:", "bust", "By dealer up card:", "A "} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, s)
+		}
+	}
+}
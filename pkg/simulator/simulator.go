@@ -0,0 +1,270 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"runtime"
+	"sync"
+)
+
+// shardRounds is the number of rounds played at each Table. A simulation is split into shards of this many rounds, each
+// with its own Table and seed, so that the results don't depend on how many workers play them.
+const shardRounds = 1000
+
+// A Strategy decides what to do with a Hand being played at a Table. strategy.Chart is one.
+// Strategies are shared between workers, so must be safe for concurrent use.
+type Strategy interface {
+	Hint(h *blackjack.Hand) (strategy.Action, error)
+}
+
+// StrategyFunc adapts a function to a Strategy.
+type StrategyFunc func(h *blackjack.Hand) (strategy.Action, error)
+
+// Hint calls the function.
+func (f StrategyFunc) Hint(h *blackjack.Hand) (strategy.Action, error) {
+	return f(h)
+}
+
+// A Betting policy decides how much a Player bets on their next Hand, between rounds. 0 sits the round out: the Player is
+// still dealt in, but the round isn't counted.
+// Betting policies are shared between workers, so must be safe for concurrent use.
+type Betting interface {
+	Bet(t *blackjack.Table, p *blackjack.Player) int
+}
+
+// BettingFunc adapts a function to a Betting policy.
+type BettingFunc func(t *blackjack.Table, p *blackjack.Player) int
+
+// Bet calls the function.
+func (f BettingFunc) Bet(t *blackjack.Table, p *blackjack.Player) int {
+	return f(t, p)
+}
+
+// FlatBet is a Betting policy that bets the same amount every round.
+type FlatBet int
+
+// Bet returns the flat bet.
+func (b FlatBet) Bet(*blackjack.Table, *blackjack.Player) int {
+	return int(b)
+}
+
+// DefaultBet is the bet used if a Config doesn't have a Betting policy. It's big enough for 3:2 and 6:5 payouts to come
+// out in whole chips.
+const DefaultBet = FlatBet(10)
+
+// A Config describes a simulation.
+type Config struct {
+	// Rules are the rules every Table plays by. Decision timeouts are ignored, since nobody's kept waiting.
+	Rules blackjack.RuleSet
+	// Seed seeds every Table's shuffles. The same Config and Seed always give the same Report.
+	Seed int64
+	// Rounds is the number of rounds to play.
+	Rounds int
+	// Players is the number of Players at each Table, all playing the same way. 0 is taken to be 1.
+	Players int
+	// Workers is the number of Tables played at once. 0 means one per CPU.
+	Workers int
+	// Strategy plays every Hand. nil means strategy.Basic.
+	Strategy Strategy
+	// Betting decides every bet. nil means DefaultBet.
+	Betting Betting
+}
+
+// validate checks this Config makes sense, returning an error wrapping ErrInvalidConfig (or blackjack.ErrInvalidRule)
+// if not.
+func (c Config) validate() (err error) {
+	if c.Rounds < 0 || c.Players < 0 || c.Workers < 0 {
+		return fmt.Errorf("%w: rounds, players and workers must not be negative", ErrInvalidConfig)
+	}
+	return c.Rules.Validate()
+}
+
+// withDefaults returns this Config with its zero values filled in.
+func (c Config) withDefaults() Config {
+	c.Rules.DecisionTimeout = 0
+	c.Players = max(c.Players, 1)
+	if c.Workers == 0 {
+		c.Workers = runtime.NumCPU()
+	}
+	if c.Strategy == nil {
+		c.Strategy = strategy.Basic
+	}
+	if c.Betting == nil {
+		c.Betting = DefaultBet
+	}
+	return c
+}
+
+// Run plays the rounds described by the Config across its workers, and returns a Report of how they went.
+// It stops early with the context's error if the context is cancelled, and returns any error a Table, Strategy or bet
+// runs into (e.g. a Strategy choosing an Action the rules don't allow).
+func Run(ctx context.Context, config Config) (report *Report, err error) {
+	err = config.validate()
+	if err != nil {
+		return nil, err
+	}
+	config = config.withDefaults()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	shards := (config.Rounds + shardRounds - 1) / shardRounds
+	reports := make([]*Report, shards)
+	errs := make([]error, shards)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(config.Workers, shards); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range jobs {
+				rounds := min(shardRounds, config.Rounds-shard*shardRounds)
+				reports[shard], errs[shard] = playShard(ctx, config, config.Seed+int64(shard), rounds)
+				if errs[shard] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+	for shard := 0; shard < shards && ctx.Err() == nil; shard++ {
+		select {
+		case jobs <- shard:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	// Merge in shard order, so the sums come out exactly the same every time.
+	report = NewReport()
+	for _, r := range reports {
+		if r == nil {
+			// Only the caller's context can have stopped a shard without an error of its own.
+			return nil, context.Cause(ctx)
+		}
+		report.Merge(r)
+	}
+	return report, nil
+}
+
+// playShard plays the given number of rounds at a fresh Table, shuffled from the given seed.
+func playShard(ctx context.Context, config Config, seed int64, rounds int) (report *Report, err error) {
+	table, err := blackjack.NewTableWithRules(config.Rules)
+	if err != nil {
+		return nil, err
+	}
+	err = table.SetSource(playdeck.NewSeededSource(seed))
+	if err != nil {
+		return nil, err
+	}
+	players := make([]*blackjack.Player, config.Players)
+	for i := range players {
+		players[i] = blackjack.NewPlayer()
+		err = table.Join(players[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report = NewReport()
+	bets := make([]int, len(players))
+	for round := 0; round < rounds; round++ {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i, p := range players {
+			bets[i], err = placeBet(config, table, p)
+			if err != nil {
+				return nil, err
+			}
+		}
+		results, err := playRound(config, table, players)
+		if err != nil {
+			return nil, err
+		}
+		up, err := table.DealerUpCard()
+		if err != nil {
+			return nil, err
+		}
+		report.addRound(results, players, bets, up)
+	}
+	return report, nil
+}
+
+// placeBet places the Betting policy's bet for a Player, topping up their bankroll first so they never run out.
+// The simulation only counts what's won and lost, so the bankroll itself doesn't matter.
+func placeBet(config Config, table *blackjack.Table, p *blackjack.Player) (bet int, err error) {
+	bet = config.Betting.Bet(table, p)
+	if bet <= 0 {
+		return 0, nil
+	}
+	// Enough to double down on every hand of a full split, with insurance.
+	need := bet * 2 * (max(config.Rules.Split.MaxHands, 1) + 1)
+	if bankroll := p.Bankroll(); bankroll < need {
+		err = p.Deposit(need - bankroll)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return bet, p.PlaceBet(bet)
+}
+
+// playRound deals a round, plays every Player's Hands with the Strategy, and returns the settled Results.
+// Insurance and even money are never taken, and the dealer peeks before any Hand is played.
+func playRound(config Config, table *blackjack.Table, players []*blackjack.Player) (results []blackjack.Result, err error) {
+	if errs := table.Deal(); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if table.PeekPending() {
+		err = table.DealerPeek()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for seat, p := range players {
+		for i := 0; ; i++ {
+			// Splitting adds Hands as we go.
+			p.RLock()
+			if i >= len(p.Hands) {
+				p.RUnlock()
+				break
+			}
+			h := p.Hands[i]
+			p.RUnlock()
+			err = playHand(config.Strategy, h)
+			if err != nil {
+				return nil, fmt.Errorf("seat %v, hand %v: %w", seat, i, err)
+			}
+		}
+	}
+	err = table.EndRound()
+	if err != nil {
+		return nil, err
+	}
+	return table.Settle()
+}
+
+// playHand plays a Hand with the Strategy until it's locked.
+func playHand(s Strategy, h *blackjack.Hand) (err error) {
+	for {
+		if _, _, locked, _ := h.Score(); locked {
+			return nil
+		}
+		action, err := s.Hint(h)
+		if err != nil {
+			return err
+		}
+		err = action.Play(h)
+		if err != nil {
+			return fmt.Errorf("%s: %w", action, err)
+		}
+	}
+}
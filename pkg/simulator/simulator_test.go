@@ -0,0 +1,140 @@
+package simulator
+
+import (
+	"context"
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/strategy"
+	"reflect"
+	"testing"
+)
+
+// simulate is a helper that runs a simulation, failing the test if it doesn't work.
+func simulate(t *testing.T, config Config) (report *Report) {
+	t.Helper()
+	report, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// alwaysStand is a Strategy that never draws a card.
+var alwaysStand = StrategyFunc(func(*blackjack.Hand) (strategy.Action, error) {
+	return strategy.ActionStand, nil
+})
+
+// Test that the same seed gives the same Report, however many workers play it, and that a different seed doesn't.
+func TestRunReproducible(t *testing.T) {
+	config := Config{Rules: blackjack.VegasStripRules, Seed: 1234, Rounds: 3500, Players: 2, Workers: 1}
+	one := simulate(t, config)
+	config.Workers = 3
+	three := simulate(t, config)
+	if !reflect.DeepEqual(one, three) {
+		t.Errorf("different reports from 1 and 3 workers:\n%s\n%s", one, three)
+	}
+	if one.Rounds != 7000 {
+		t.Errorf("expected 7000 rounds (two players for 3500), got %v", one.Rounds)
+	}
+
+	config.Seed = 4321
+	other := simulate(t, config)
+	if reflect.DeepEqual(one, other) {
+		t.Errorf("got the same report from different seeds")
+	}
+}
+
+// Test that basic strategy plays close to even, and that never drawing a card is a long way behind it.
+func TestRunHouseEdge(t *testing.T) {
+	basic := simulate(t, Config{Rules: blackjack.VegasStripRules, Seed: 1234, Rounds: 20000})
+	if edge := basic.HouseEdge(); edge < -0.03 || edge > 0.03 {
+		t.Errorf("expected basic strategy to have a house edge close to 0, got %.3f", edge)
+	}
+	if v := basic.Variance(); v < 1.1 || v > 1.5 {
+		t.Errorf("expected basic strategy to have a variance around 1.3, got %.3f", v)
+	}
+	low, high := basic.Confidence(1.96)
+	if !(low < basic.HouseEdge() && basic.HouseEdge() < high) {
+		t.Errorf("house edge %.3f isn't inside its confidence interval %.3f to %.3f", basic.HouseEdge(), low, high)
+	}
+	if basic.Overall.Hands <= basic.Rounds {
+		t.Errorf("expected some splits, got %v hands from %v rounds", basic.Overall.Hands, basic.Rounds)
+	}
+
+	stand := simulate(t, Config{Rules: blackjack.VegasStripRules, Seed: 1234, Rounds: 20000, Strategy: alwaysStand})
+	if edge := stand.HouseEdge(); edge < 0.1 {
+		t.Errorf("expected always standing to have a house edge over 10%%, got %.3f", edge)
+	}
+	if stand.Totals[Bust] != nil {
+		t.Errorf("expected no bust hands when always standing, got %v", stand.Totals[Bust].Hands)
+	}
+	if stand.Overall.Hands != stand.Rounds {
+		t.Errorf("expected a hand per round when always standing, got %v hands from %v rounds", stand.Overall.Hands, stand.Rounds)
+	}
+}
+
+// Test that rounds aren't counted for Players who don't bet on them, and that bets are staked as the policy says.
+func TestRunBetting(t *testing.T) {
+	firstSeat := BettingFunc(func(table *blackjack.Table, p *blackjack.Player) int {
+		if table.Players[0] == p {
+			return 20
+		}
+		return 0
+	})
+	report := simulate(t, Config{Rules: blackjack.DefaultRules, Seed: 1234, Rounds: 500, Players: 3, Betting: firstSeat})
+	if report.Rounds != 500 {
+		t.Errorf("expected 500 rounds from a single betting seat, got %v", report.Rounds)
+	}
+	if report.Bets != 500*20 {
+		t.Errorf("expected %v bet, got %v", 500*20, report.Bets)
+	}
+}
+
+// Test that Run checks its Config.
+func TestRunInvalid(t *testing.T) {
+	_, err := Run(context.Background(), Config{Rules: blackjack.DefaultRules, Rounds: -1})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("didn't get appropriate error when running negative rounds, expected ErrInvalidConfig got %s", err)
+	}
+	_, err = Run(context.Background(), Config{Rounds: 1})
+	if !errors.Is(err, blackjack.ErrInvalidRule) {
+		t.Errorf("didn't get appropriate error when running with no rules, expected ErrInvalidRule got %s", err)
+	}
+	_, err = Run(context.Background(), Config{Rules: blackjack.DefaultRules, Rounds: 1, Betting: FlatBet(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := blackjack.DefaultRules
+	rules.MinBet = 10
+	_, err = Run(context.Background(), Config{Rules: rules, Rounds: 1, Betting: FlatBet(5)})
+	if !errors.Is(err, blackjack.ErrBetBelowMinimum) {
+		t.Errorf("didn't get appropriate error when betting under the minimum, expected ErrBetBelowMinimum got %s", err)
+	}
+}
+
+// Test that an Action the Table won't take stops the simulation.
+func TestRunStrategyError(t *testing.T) {
+	alwaysSplit := StrategyFunc(func(*blackjack.Hand) (strategy.Action, error) {
+		return strategy.ActionSplit, nil
+	})
+	_, err := Run(context.Background(), Config{Rules: blackjack.DefaultRules, Rounds: 100, Strategy: alwaysSplit})
+	if !errors.Is(err, blackjack.ErrSplitNotPair) {
+		t.Errorf("didn't get appropriate error when splitting everything, expected ErrSplitNotPair got %s", err)
+	}
+}
+
+// Test that a cancelled context stops the simulation.
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Run(ctx, Config{Rules: blackjack.DefaultRules, Rounds: 5000})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("didn't get appropriate error when cancelled, expected context.Canceled got %s", err)
+	}
+
+	// Nothing to play is fine.
+	report := simulate(t, Config{Rules: blackjack.DefaultRules})
+	if report.Rounds != 0 {
+		t.Errorf("expected no rounds, got %v", report.Rounds)
+	}
+}
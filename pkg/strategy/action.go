@@ -0,0 +1,115 @@
+package strategy
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"strings"
+)
+
+// Action is a decision a Player can make about a Hand.
+type Action uint8
+
+// Basic strategy recommends one of these Actions.
+const (
+	ActionHit Action = iota
+	ActionStand
+	ActionDouble
+	ActionSplit
+	ActionSurrender
+)
+
+var actionNames = map[Action]string{
+	ActionHit:       "hit",
+	ActionStand:     "stand",
+	ActionDouble:    "double down",
+	ActionSplit:     "split",
+	ActionSurrender: "surrender",
+}
+
+// String returns a human-readable name for this Action (e.g. "double down"). Unknown values return "unknown".
+func (a Action) String() string {
+	name, exists := actionNames[a]
+	if !exists {
+		return "unknown"
+	}
+	return name
+}
+
+// Play takes this Action on the given Hand, returning whatever error the Hand does.
+func (a Action) Play(h *blackjack.Hand) (err error) {
+	switch a {
+	case ActionHit:
+		return h.Hit()
+	case ActionStand:
+		return h.Stick()
+	case ActionDouble:
+		return h.DoubleDown()
+	case ActionSplit:
+		_, err = h.Split()
+		return err
+	case ActionSurrender:
+		return h.Surrender()
+	}
+	return ErrUnknownAction
+}
+
+// Entry is a single cell of a Chart. Some Entries depend on what the table's rules allow, e.g. "double, otherwise hit".
+type Entry uint8
+
+// A Chart is filled in with these Entries. Their codes (in brackets) are the ones used by published basic strategy charts,
+// and by ReadChart().
+const (
+	// EntryHit (H) hits.
+	EntryHit Entry = iota
+	// EntryStand (S) stands.
+	EntryStand
+	// EntryDouble (D) doubles down if allowed, and hits otherwise.
+	EntryDouble
+	// EntryDoubleStand (Ds) doubles down if allowed, and stands otherwise.
+	EntryDoubleStand
+	// EntrySplit (P) splits if allowed, and plays the hand's total otherwise.
+	EntrySplit
+	// EntrySplitDAS (Ph) splits if allowed and doubling after splitting is too, and plays the hand's total otherwise.
+	EntrySplitDAS
+	// EntrySurrenderHit (Rh) surrenders if allowed, and hits otherwise.
+	EntrySurrenderHit
+	// EntrySurrenderStand (Rs) surrenders if allowed, and stands otherwise.
+	EntrySurrenderStand
+	// EntrySurrenderSplit (Rp) surrenders if allowed, and splits otherwise.
+	EntrySurrenderSplit
+)
+
+var entryCodes = map[Entry]string{
+	EntryHit:            "H",
+	EntryStand:          "S",
+	EntryDouble:         "D",
+	EntryDoubleStand:    "Ds",
+	EntrySplit:          "P",
+	EntrySplitDAS:       "Ph",
+	EntrySurrenderHit:   "Rh",
+	EntrySurrenderStand: "Rs",
+	EntrySurrenderSplit: "Rp",
+}
+
+// String returns this Entry's chart code (e.g. "Ds"). Unknown values return "unknown".
+func (e Entry) String() string {
+	code, exists := entryCodes[e]
+	if !exists {
+		return "unknown"
+	}
+	return code
+}
+
+// splits returns whether this Entry may split, and so only belongs in the pairs section of a Chart.
+func (e Entry) splits() bool {
+	return e == EntrySplit || e == EntrySplitDAS || e == EntrySurrenderSplit
+}
+
+// parseEntry returns the Entry for a chart code, ignoring case.
+func parseEntry(code string) (e Entry, ok bool) {
+	for e, c := range entryCodes {
+		if strings.EqualFold(c, code) {
+			return e, true
+		}
+	}
+	return e, false
+}
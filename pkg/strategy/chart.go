@@ -0,0 +1,268 @@
+package strategy
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A Chart is a basic strategy chart: what to do with every hand total against every dealer up card. Like a printed
+// chart, it has three sections - hard totals, soft totals and pairs - and rows that replace some of them when the dealer
+// hits soft 17.
+//
+// Charts are read from a simple CSV format (see ReadChart()), so you can ship your own. Basic is the one built in.
+type Chart struct {
+	// rows holds each section's rows, indexed by total (or, for pairs, by the value of one card with aces as 11).
+	rows [sectionCount]map[int][upCards]Entry
+	// h17 holds the rows that replace those in rows when the dealer hits soft 17.
+	h17 [sectionCount]map[int][upCards]Entry
+}
+
+// section is one of the three sections of a Chart.
+type section uint8
+
+const (
+	sectionHard section = iota
+	sectionSoft
+	sectionPairs
+	sectionCount
+)
+
+var sectionNames = map[section]string{
+	sectionHard:  "hard",
+	sectionSoft:  "soft",
+	sectionPairs: "pairs",
+}
+
+// sectionRows are the first and last rows each section may have, and sectionRequired the first row each must have (they
+// must all run to the last).
+var (
+	sectionRows     = [sectionCount][2]int{{4, 21}, {12, 21}, {2, 11}}
+	sectionRequired = [sectionCount]int{5, 13, 2}
+)
+
+// upCards is the number of columns in a Chart: one for each dealer up card, 2 to 10 and then the ace.
+const upCards = 10
+
+//go:embed charts/basic.csv
+var basicChart string
+
+// Basic is the standard basic strategy for four to eight decks, with the deviations for the dealer hitting soft 17.
+// Doubling after splitting and surrender are taken into account according to the table's rules.
+var Basic = mustParseChart(basicChart)
+
+// ParseChart reads a Chart from a string. See ReadChart() for the format.
+func ParseChart(s string) (chart *Chart, err error) {
+	return ReadChart(strings.NewReader(s))
+}
+
+// mustParseChart is ParseChart() for built-in charts, which can't be wrong.
+func mustParseChart(s string) (chart *Chart) {
+	chart, err := ParseChart(s)
+	if err != nil {
+		panic(err)
+	}
+	return chart
+}
+
+// ReadChart reads a Chart in CSV format. Blank lines and lines starting with # are ignored.
+//
+// Each section starts with a header naming it ("hard", "soft" or "pairs"), followed by the dealer up cards its columns
+// are for (2 to 10, or T, and A, in any order). Each row below starts with a total (for pairs, a single card: 2 to 10,
+// or T, or A) followed by an Entry code (H, S, D, Ds, P, Ph, Rh, Rs or Rp) for each column:
+//
+//	hard,2,3,4,5,6,7,8,9,10,A
+//	9,H,D,D,D,D,H,H,H,H,H
+//
+// The hard section needs every total from 5 to 21 (4 is optional, and played as 5 if missing), the soft section every
+// total from 13 to 21 (soft 12 is played as 13 if missing), and the pairs section every card. Sections named with " h17"
+// on the end (e.g. "hard h17") hold rows that replace the ones above when the dealer hits soft 17, and can have as few
+// rows as needed. Split entries (P, Ph and Rp) can only be used for pairs.
+//
+// It returns an error wrapping ErrChartSyntax (with the line it's on) if the chart can't be read, and one wrapping
+// ErrChartIncomplete if a section is missing rows.
+func ReadChart(r io.Reader) (chart *Chart, err error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	chart = &Chart{}
+	var rows map[int][upCards]Entry
+	var sec section
+	var columns []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrChartSyntax, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		// A section header?
+		if s, h17, ok := parseSection(record[0]); ok {
+			columns, err = parseColumns(record[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %v: %w", ErrChartSyntax, line, err)
+			}
+			sec = s
+			table := &chart.rows
+			if h17 {
+				table = &chart.h17
+			}
+			if table[sec] != nil {
+				return nil, fmt.Errorf("%w: line %v: section %q appears twice", ErrChartSyntax, line, record[0])
+			}
+			table[sec] = map[int][upCards]Entry{}
+			rows = table[sec]
+			continue
+		}
+
+		if rows == nil {
+			return nil, fmt.Errorf("%w: line %v: row before the first section", ErrChartSyntax, line)
+		}
+		index, ok := parseRow(sec, record[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: line %v: invalid %s row %q", ErrChartSyntax, line, sectionNames[sec], record[0])
+		}
+		if _, exists := rows[index]; exists {
+			return nil, fmt.Errorf("%w: line %v: %s row %q appears twice", ErrChartSyntax, line, sectionNames[sec], record[0])
+		}
+		if len(record)-1 != len(columns) {
+			return nil, fmt.Errorf("%w: line %v: expected %v entries, got %v", ErrChartSyntax, line, len(columns), len(record)-1)
+		}
+		var row [upCards]Entry
+		for i, code := range record[1:] {
+			e, ok := parseEntry(strings.TrimSpace(code))
+			if !ok {
+				return nil, fmt.Errorf("%w: line %v: unknown entry %q", ErrChartSyntax, line, code)
+			}
+			if e.splits() && sec != sectionPairs {
+				return nil, fmt.Errorf("%w: line %v: %s can only be used for pairs", ErrChartSyntax, line, e)
+			}
+			row[columns[i]] = e
+		}
+		rows[index] = row
+	}
+
+	for sec := sectionHard; sec < sectionCount; sec++ {
+		for i := sectionRequired[sec]; i <= sectionRows[sec][1]; i++ {
+			if _, exists := chart.rows[sec][i]; !exists {
+				return nil, fmt.Errorf("%w: no %s row for %s", ErrChartIncomplete, sectionNames[sec], rowName(sec, i))
+			}
+		}
+	}
+	return chart, nil
+}
+
+// LoadChart reads the chart in the file at the given path with ReadChart(), or returns Basic if there's no path.
+func LoadChart(path string) (chart *Chart, err error) {
+	if path == "" {
+		return Basic, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadChart(f)
+}
+
+// parseSection returns the section a header names, and whether it's for the dealer hitting soft 17.
+func parseSection(name string) (sec section, h17 bool, ok bool) {
+	fields := strings.Fields(strings.ToLower(name))
+	if len(fields) == 2 && fields[1] == "h17" {
+		h17 = true
+	} else if len(fields) != 1 {
+		return sec, false, false
+	}
+	for s, n := range sectionNames {
+		if fields[0] == n {
+			return s, h17, true
+		}
+	}
+	return sec, false, false
+}
+
+// parseColumns returns the column of a Chart each of a section header's up cards is for, checking there's one of each.
+func parseColumns(labels []string) (columns []int, err error) {
+	if len(labels) != upCards {
+		return nil, fmt.Errorf("expected %v up cards, got %v", upCards, len(labels))
+	}
+	seen := map[int]bool{}
+	for _, label := range labels {
+		value, ok := parseCard(label)
+		if !ok {
+			return nil, fmt.Errorf("invalid up card %q", label)
+		}
+		if seen[value] {
+			return nil, fmt.Errorf("up card %q appears twice", label)
+		}
+		seen[value] = true
+		columns = append(columns, column(value))
+	}
+	return columns, nil
+}
+
+// parseRow returns the index of a row in the given section.
+func parseRow(sec section, label string) (index int, ok bool) {
+	if sec == sectionPairs {
+		index, ok = parseCard(label)
+	} else {
+		var err error
+		index, err = strconv.Atoi(strings.TrimSpace(label))
+		ok = err == nil
+	}
+	return index, ok && index >= sectionRows[sec][0] && index <= sectionRows[sec][1]
+}
+
+// parseCard returns the blackjack value of a card label (2 to 10, T or A), with aces as 11.
+func parseCard(label string) (value int, ok bool) {
+	switch strings.ToUpper(strings.TrimSpace(label)) {
+	case "A":
+		return 11, true
+	case "T":
+		return 10, true
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(label))
+	return value, err == nil && value >= 2 && value <= 10
+}
+
+// rowName returns a row's label, as it would be written in a chart.
+func rowName(sec section, index int) string {
+	if sec == sectionPairs && index == 11 {
+		return "A"
+	}
+	return strconv.Itoa(index)
+}
+
+// column returns the column of a Chart for an up card's blackjack value, with aces as 11.
+func column(value int) int {
+	if value == 11 {
+		return upCards - 1
+	}
+	return value - 2
+}
+
+// lookup returns the Entry for the given row of a section against an up card's column, using the h17 rows if asked.
+// Totals below a section's first row are played as its first row.
+func (c *Chart) lookup(sec section, index int, col int, h17 bool) Entry {
+	if min := sectionRequired[sec]; index < min {
+		if _, exists := c.rows[sec][index]; !exists {
+			index = min
+		}
+	}
+	if h17 {
+		if row, exists := c.h17[sec][index]; exists {
+			return row[col]
+		}
+	}
+	return c.rows[sec][index][col]
+}
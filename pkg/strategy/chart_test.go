@@ -0,0 +1,116 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// Test that the built-in chart reads back as written.
+func TestChartBasic(t *testing.T) {
+	cases := []struct {
+		sec   section
+		index int
+		up    int
+		h17   bool
+		entry Entry
+	}{
+		{sectionHard, 16, 10, false, EntrySurrenderHit},
+		{sectionHard, 11, 11, false, EntryHit},
+		{sectionHard, 11, 11, true, EntryDouble},
+		{sectionHard, 4, 5, false, EntryHit},
+		{sectionSoft, 18, 3, false, EntryDoubleStand},
+		{sectionSoft, 12, 5, false, EntryDouble},
+		{sectionPairs, 2, 2, false, EntrySplitDAS},
+		{sectionPairs, 8, 11, true, EntrySurrenderSplit},
+		{sectionPairs, 9, 7, false, EntryStand},
+	}
+	for _, c := range cases {
+		e := Basic.lookup(c.sec, c.index, column(c.up), c.h17)
+		if e != c.entry {
+			t.Errorf("%s %v against %v (h17 %v): expected %s, got %s", sectionNames[c.sec], c.index, c.up, c.h17, c.entry, e)
+		}
+	}
+}
+
+// Test that charts can be written in any column order and case.
+func TestChartRead(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("HARD, A,10,9,8,7,6,5,4,3,2\n")
+	for i := 5; i <= 21; i++ {
+		fmt.Fprintf(&b, "%v,s,H,H,H,H,H,H,H,H,ds\n", i)
+	}
+	b.WriteString("soft,2,3,4,5,6,7,8,9,T,A\n")
+	for i := 13; i <= 21; i++ {
+		fmt.Fprintf(&b, "%v,S,S,S,S,S,S,S,S,S,S\n", i)
+	}
+	b.WriteString("pairs,2,3,4,5,6,7,8,9,T,A\n")
+	for _, card := range []string{"2", "3", "4", "5", "6", "7", "8", "9", "T", "A"} {
+		b.WriteString(card + ",P,P,P,P,P,P,P,P,P,Rp\n")
+	}
+	chart, err := ParseChart(b.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := chart.lookup(sectionHard, 12, column(11), false); e != EntryStand {
+		t.Errorf("expected the first column to be the ace, got %s", e)
+	}
+	if e := chart.lookup(sectionHard, 12, column(2), false); e != EntryDoubleStand {
+		t.Errorf("expected the last column to be the 2, got %s", e)
+	}
+	if e := chart.lookup(sectionPairs, 10, column(11), false); e != EntrySurrenderSplit {
+		t.Errorf("expected T to be read as a pair of tens, got %s", e)
+	}
+}
+
+// Test that charts are loaded from files, falling back to the built-in one without a path.
+func TestChartLoad(t *testing.T) {
+	chart, err := LoadChart("")
+	if err != nil || chart != Basic {
+		t.Errorf("expected the built-in chart without a path, got %p (%v)", chart, err)
+	}
+	chart, err = LoadChart("charts/basic.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := chart.lookup(sectionHard, 16, column(10), false); e != EntrySurrenderHit {
+		t.Errorf("expected the loaded chart to match the built-in one, got %s", e)
+	}
+	_, err = LoadChart("charts/missing.csv")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("didn't get appropriate error when loading a missing chart, expected ErrNotExist got %s", err)
+	}
+}
+
+// Test that broken charts are rejected.
+func TestChartErrors(t *testing.T) {
+	header := "hard,2,3,4,5,6,7,8,9,10,A\n"
+	cases := []struct {
+		name  string
+		chart string
+		err   error
+	}{
+		{"row before section", "5,H,H,H,H,H,H,H,H,H,H\n", ErrChartSyntax},
+		{"short header", "hard,2,3,4\n", ErrChartSyntax},
+		{"duplicate up card", "hard,2,2,4,5,6,7,8,9,10,A\n", ErrChartSyntax},
+		{"unknown entry", header + "5,H,H,H,H,H,H,H,H,H,X\n", ErrChartSyntax},
+		{"short row", header + "5,H,H\n", ErrChartSyntax},
+		{"total out of range", header + "22,H,H,H,H,H,H,H,H,H,H\n", ErrChartSyntax},
+		{"duplicate row", header + "5,H,H,H,H,H,H,H,H,H,H\n5,H,H,H,H,H,H,H,H,H,H\n", ErrChartSyntax},
+		{"split a total", header + "5,P,H,H,H,H,H,H,H,H,H\n", ErrChartSyntax},
+		{"duplicate section", header + header, ErrChartSyntax},
+		{"missing rows", header + "5,H,H,H,H,H,H,H,H,H,H\n", ErrChartIncomplete},
+		{"empty", "", ErrChartIncomplete},
+	}
+	for _, c := range cases {
+		_, err := ParseChart(c.chart)
+		if !errors.Is(err, c.err) {
+			t.Errorf("%s: didn't get appropriate error, expected %s got %s", c.name, c.err, err)
+		}
+	}
+	if Entry(42).String() != "unknown" || Action(42).String() != "unknown" {
+		t.Errorf("expected unknown entries and actions to be \"unknown\"")
+	}
+}
@@ -0,0 +1,182 @@
+package strategy
+
+import (
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+)
+
+// A Situation is everything basic strategy needs to know to decide what to do with a Hand.
+type Situation struct {
+	// Cards are the Hand's cards.
+	Cards []playdeck.Card
+	// UpCard is the dealer's up card.
+	UpCard playdeck.Card
+	// Rules are the rules the Table plays by.
+	Rules blackjack.RuleSet
+	// Split is true if the Hand was made by splitting a pair.
+	Split bool
+	// Hands is the number of Hands the Player holds, including this one. 0 is taken to be 1.
+	Hands int
+}
+
+// Decide returns the Action this Chart recommends for the given Situation. Entries that depend on the rules are
+// resolved against the Situation's, so the Action returned is always one the table allows. Hands that are already 21 or
+// more are stood on.
+func (c *Chart) Decide(s Situation) Action {
+	total, soft := handTotal(s.Cards)
+	if total >= 21 {
+		return ActionStand
+	}
+	col := column(cardValue(s.UpCard))
+	h17 := s.Rules.DealerRule == blackjack.DealerHitsSoft17
+
+	if s.pair() {
+		switch e := c.lookup(sectionPairs, cardValue(s.Cards[0]), col, h17); e {
+		case EntrySplit:
+			if s.canSplit() {
+				return ActionSplit
+			}
+		case EntrySplitDAS:
+			if s.canSplit() && s.Rules.Double.AfterSplit {
+				return ActionSplit
+			}
+		case EntrySurrenderSplit:
+			if s.canSurrender() {
+				return ActionSurrender
+			}
+			if s.canSplit() {
+				return ActionSplit
+			}
+		default:
+			return s.resolve(e)
+		}
+		// A pair that isn't being split is played like any other hand.
+	}
+	// Split aces that only get one card can't be drawn to.
+	if s.Split && s.Rules.Split.AcesOneCard && s.Cards[0].Value == playdeck.ValueAce {
+		return ActionStand
+	}
+
+	sec := sectionHard
+	if soft {
+		sec = sectionSoft
+	}
+	return s.resolve(c.lookup(sec, total, col, h17))
+}
+
+// Hint returns the Action this Chart recommends for a Hand being played at a Table, taking the dealer's up card and the
+// rules from the Table.
+// It returns ErrHandNoTable if the Hand isn't at a Table, blackjack.ErrHandLocked if it can't be played any further,
+// and any error the Table returns for the dealer's up card (e.g. if the round hasn't been dealt).
+func (c *Chart) Hint(h *blackjack.Hand) (action Action, err error) {
+	if h == nil || h.Table == nil || h.Player == nil {
+		return action, ErrHandNoTable
+	}
+	up, err := h.Table.DealerUpCard()
+	if err != nil {
+		return action, err
+	}
+	_, _, locked, _ := h.Score()
+	if locked {
+		return action, blackjack.ErrHandLocked
+	}
+
+	s := Situation{UpCard: up, Rules: h.Table.Rules(), Split: h.WasSplit()}
+	h.RLock()
+	s.Cards = append([]playdeck.Card(nil), h.Cards...)
+	h.RUnlock()
+	h.Player.RLock()
+	s.Hands = len(h.Player.Hands)
+	h.Player.RUnlock()
+	return c.Decide(s), nil
+}
+
+// resolve turns a non-split Entry into the Action the table allows.
+func (s Situation) resolve(e Entry) Action {
+	switch e {
+	case EntryStand:
+		return ActionStand
+	case EntryDouble:
+		if s.canDouble() {
+			return ActionDouble
+		}
+		return ActionHit
+	case EntryDoubleStand:
+		if s.canDouble() {
+			return ActionDouble
+		}
+		return ActionStand
+	case EntrySurrenderHit:
+		if s.canSurrender() {
+			return ActionSurrender
+		}
+		return ActionHit
+	case EntrySurrenderStand:
+		if s.canSurrender() {
+			return ActionSurrender
+		}
+		return ActionStand
+	}
+	return ActionHit
+}
+
+// pair returns whether the Hand is a pair that could be split under the rules.
+func (s Situation) pair() bool {
+	if len(s.Cards) != 2 {
+		return false
+	}
+	a, b := s.Cards[0].Value, s.Cards[1].Value
+	return a == b || (s.Rules.Split.AnyTens && a >= playdeck.ValueTen && b >= playdeck.ValueTen)
+}
+
+// canSplit returns whether the rules allow the Hand to be split (assuming it's a pair).
+func (s Situation) canSplit() bool {
+	hands := max(s.Hands, 1)
+	rules := s.Rules.Split
+	if rules.MaxHands < 2 || hands >= rules.MaxHands {
+		return false
+	}
+	return !(s.Split && s.Cards[0].Value == playdeck.ValueAce && !rules.ResplitAces)
+}
+
+// canDouble returns whether the rules allow the Hand to be doubled down on.
+func (s Situation) canDouble() bool {
+	if len(s.Cards) != 2 || (s.Split && !s.Rules.Double.AfterSplit) {
+		return false
+	}
+	total, _ := handTotal(s.Cards)
+	return s.Rules.Double.Totals.Allows(total)
+}
+
+// canSurrender returns whether the rules allow the Hand to be surrendered.
+func (s Situation) canSurrender() bool {
+	return s.Rules.Surrender != blackjack.SurrenderNone && len(s.Cards) == 2 && !s.Split
+}
+
+// handTotal returns the best total of the given cards, and whether it's soft (an ace is being counted as 11).
+func handTotal(cards []playdeck.Card) (total int, soft bool) {
+	aces := false
+	for _, c := range cards {
+		v := cardValue(c)
+		if v == 11 {
+			aces = true
+			v = 1
+		}
+		total += v
+	}
+	if aces && total+10 <= 21 {
+		return total + 10, true
+	}
+	return total, false
+}
+
+// cardValue returns the blackjack value of a card, with aces as 11.
+func cardValue(c playdeck.Card) int {
+	switch {
+	case c.Value == playdeck.ValueAce:
+		return 11
+	case c.Value >= playdeck.ValueTen:
+		return 10
+	}
+	return c.Value.Value()
+}
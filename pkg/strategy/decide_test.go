@@ -0,0 +1,130 @@
+package strategy
+
+import (
+	"errors"
+	"github.com/duckfullstop/checkmate/pkg/blackjack"
+	"github.com/duckfullstop/checkmate/pkg/playdeck"
+	"testing"
+)
+
+// Test basic strategy decisions, including the deviations for the table's rules.
+func TestDecide(t *testing.T) {
+	noDAS := blackjack.VegasStripRules
+	noDAS.Double.AfterSplit = false
+	h17 := blackjack.VegasStripRules
+	h17.DealerRule = blackjack.DealerHitsSoft17
+	noSurrender := blackjack.VegasStripRules
+	noSurrender.Surrender = blackjack.SurrenderNone
+	reno := blackjack.VegasStripRules
+	reno.Double.Totals = blackjack.DoubleNineToEleven
+	acesDraw := blackjack.VegasStripRules
+	acesDraw.Split.AcesOneCard = false
+	acesDrawNoDAS := acesDraw
+	acesDrawNoDAS.Double.AfterSplit = false
+	resplitAces := blackjack.VegasStripRules
+	resplitAces.Split.ResplitAces = true
+
+	cases := []struct {
+		cards  string
+		up     string
+		rules  blackjack.RuleSet
+		split  bool
+		hands  int
+		action Action
+	}{
+		{"TS 6H", "TD", blackjack.VegasStripRules, false, 1, ActionSurrender},
+		{"TS 6H", "TD", noSurrender, false, 1, ActionHit},
+		{"TS 2H 4C", "TD", blackjack.VegasStripRules, false, 1, ActionHit},
+		{"TS 6H", "6D", blackjack.VegasStripRules, false, 1, ActionStand},
+		{"6S 5H", "AD", blackjack.VegasStripRules, false, 1, ActionHit},
+		{"6S 5H", "AD", h17, false, 1, ActionDouble},
+		{"4S 2H 5C", "6D", blackjack.VegasStripRules, false, 1, ActionHit},
+		{"TS 7H", "AD", h17, false, 1, ActionSurrender},
+		{"AS 7H", "3D", blackjack.VegasStripRules, false, 1, ActionDouble},
+		{"AS 7H", "3D", reno, false, 1, ActionStand},
+		{"AS 2H 5C", "3D", blackjack.VegasStripRules, false, 1, ActionStand},
+		{"AS 7H", "2D", blackjack.VegasStripRules, false, 1, ActionStand},
+		{"AS 7H", "2D", h17, false, 1, ActionDouble},
+		{"AS 8H", "6D", h17, false, 1, ActionDouble},
+		{"2S 2H", "2D", blackjack.VegasStripRules, false, 1, ActionSplit},
+		{"2S 2H", "2D", noDAS, false, 1, ActionHit},
+		{"8S 8H", "AD", blackjack.VegasStripRules, false, 1, ActionSplit},
+		{"8S 8H", "AD", h17, false, 1, ActionSurrender},
+		{"8S 8H", "AD", h17, true, 2, ActionSplit},
+		{"8S 8H", "TD", blackjack.VegasStripRules, false, 4, ActionSurrender},
+		{"8S 8H", "6D", blackjack.VegasStripRules, true, 4, ActionStand},
+		{"5S 5H", "6D", blackjack.VegasStripRules, false, 1, ActionDouble},
+		{"KS QH", "6D", blackjack.VegasStripRules, false, 1, ActionStand},
+		{"AS AH", "6D", blackjack.VegasStripRules, false, 1, ActionSplit},
+		{"AS AH", "6D", acesDraw, true, 2, ActionDouble},
+		{"AS AH", "6D", acesDrawNoDAS, true, 2, ActionHit},
+		{"AS AH", "6D", blackjack.VegasStripRules, true, 2, ActionStand},
+		{"AS AH", "6D", resplitAces, true, 2, ActionSplit},
+		{"AS AH", "6D", resplitAces, true, 4, ActionStand},
+		{"AS KH", "6D", blackjack.VegasStripRules, false, 1, ActionStand},
+	}
+	for _, c := range cases {
+		cards, err := playdeck.ParseCards(c.cards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, err := playdeck.ParseCard(c.up)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := Situation{Cards: cards, UpCard: up, Rules: c.rules, Split: c.split, Hands: c.hands}
+		if action := Basic.Decide(s); action != c.action {
+			t.Errorf("%s against %s (split %v, %v hands): expected %s, got %s", c.cards, c.up, c.split, c.hands, c.action, action)
+		}
+	}
+}
+
+// Test hints for Hands being played at a Table, and playing them.
+func TestHint(t *testing.T) {
+	table, err := blackjack.NewTableWithRules(blackjack.VegasStripRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := blackjack.NewPlayer()
+	err = table.Join(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hand := &blackjack.Hand{}
+	_, err = Basic.Hint(hand)
+	if !errors.Is(err, ErrHandNoTable) {
+		t.Errorf("didn't get appropriate error for a hand with no table, expected HandNoTable got %s", err)
+	}
+
+	errs := table.Deal()
+	if len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if table.PeekPending() {
+		err = table.DealerPeek()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	hand = player.Hands[0]
+	for {
+		if _, _, locked, _ := hand.Score(); locked {
+			break
+		}
+		action, err := Basic.Hint(hand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = action.Play(hand)
+		if err != nil {
+			t.Fatalf("couldn't %s on %s: %s", action, hand, err)
+		}
+	}
+	_, err = Basic.Hint(hand)
+	if !errors.Is(err, blackjack.ErrHandLocked) {
+		t.Errorf("didn't get appropriate error for a locked hand, expected HandLocked got %s", err)
+	}
+	if err = Action(42).Play(hand); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("didn't get appropriate error for an unknown action, expected UnknownAction got %s", err)
+	}
+}
@@ -0,0 +1,11 @@
+package strategy
+
+import "errors"
+
+// Errors throwable by this module.
+var (
+	ErrChartSyntax     = errors.New("chart is malformed")
+	ErrChartIncomplete = errors.New("chart is missing rows")
+	ErrHandNoTable     = errors.New("hand is not being played at a table")
+	ErrUnknownAction   = errors.New("action is unknown")
+)